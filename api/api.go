@@ -0,0 +1,8 @@
+// Package api embeds the OpenAPI document describing the HTTP API. It is
+// served to clients and requests are validated against it.
+package api
+
+import _ "embed"
+
+//go:embed openapi.yml
+var OpenAPI []byte
@@ -1,17 +1,29 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
-	"tender_system/internal/http-server/handlers/api/bids"
-	"tender_system/internal/http-server/handlers/api/ping"
-	"tender_system/internal/http-server/handlers/api/tender"
+	"tender_system/api"
+	"tender_system/internal/authz"
+	ratelimitdomain "tender_system/internal/domain/ratelimit"
+	scheduledomain "tender_system/internal/domain/schedule"
+	"tender_system/internal/http-server/middleware/clientip"
+	"tender_system/internal/http-server/middleware/ratelimit"
+	"tender_system/internal/http-server/router"
+	"tender_system/internal/jobs"
+	"tender_system/internal/lib/openapi"
+	"tender_system/internal/notify"
+	"tender_system/internal/scheduler"
+	"tender_system/internal/service"
+	"tender_system/internal/storage/blob"
 	"tender_system/internal/storage/postgres"
+	"time"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/joho/godotenv"
 )
 
@@ -29,34 +41,90 @@ func main() {
 	storage, err := postgres.New(connStr)
 	if err != nil {
 		log.Error("Failed to connect to postgresql", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		os.Exit(1)
 	}
 
-	router := chi.NewRouter()
+	blobStore, err := newBlobStore()
+	if err != nil {
+		log.Error("Failed to initialize blob storage", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		os.Exit(1)
+	}
 
-	router.Get("/api/tenders", tender.NewGetTenders(log, storage))
-	router.Route("/api", func(r chi.Router) {
-		// r.Post("/", )
-		r.Get("/ping", ping.New(log))
-		r.Route("/tenders", func(r chi.Router) {
-			r.Post("/new", tender.NewPostTender(log, storage))
-			r.Get("/my", tender.NewGetMyTenders(log, storage))
-			r.Get("/{tenderId}/status", tender.NewGetTenderStatus(log, storage))
-			r.Put("/{tenderId}/status", tender.NewPutTenderStatus(log, storage))
-			r.Patch("/{tenderId}/edit", tender.NewPatchTender(log, storage))
-			r.Put("/{tenderId}/rollback/{version}", tender.NewRollbackTender(log, storage))
-		})
-		r.Route("/bids", func(r chi.Router) {
-			r.Post("/new", bids.NewPostBid(log, storage))
-			r.Get("/my", bids.NewGetMyBids(log, storage))
-			r.Get("/{tenderId}/list", bids.NewGetTenderBids(log, storage))
-			r.Get("/{bidId}/status", bids.NewGetBidStatus(log, storage))
-			r.Put("/{bidId}/status", bids.NewPutBidStatus(log, storage))
-			r.Patch("/{bidId}/edit", bids.NewPatchBid(log, storage))
-			r.Put("/{bidId}/feedback", bids.NewPutBidFeedback(log, storage))
-			r.Put("/{bidId}/rollback/{version}", bids.NewRollbackBid(log, storage))
-			r.Get("/{tenderId}/reviews", bids.NewReadBidFeedback(log, storage))
-			r.Put("/{bidId}/submit_decision", bids.NewPutBidDecision(log, storage))
-		})
+	jobQueue := storage.JobQueue()
+	runner := jobs.New(log, jobQueue, 10, time.Second)
+
+	var mail notify.Sender
+	if mailer := newMailer(); mailer != nil {
+		jobs.Handle(runner, notify.EmailJob, jobs.Options{Concurrency: 4}, notify.Deliver(mailer))
+		mail = notify.NewQueued(runner)
+	}
+
+	authorizer := authz.New(storage)
+	notifier := notify.New(log, storage, mail)
+	tenderService := service.NewTenderService(storage, authorizer, notifier)
+	bidService := service.NewBidService(storage, authorizer, notifier)
+	roleService := service.NewRoleService(storage, authorizer)
+	organizationService := service.NewOrganizationService(storage, authorizer)
+	employeeService := service.NewEmployeeService(storage)
+	lotService := service.NewLotService(storage, authorizer)
+	auctionService := service.NewAuctionService(storage, authorizer, notifier)
+	auditService := service.NewAuditService(storage, authorizer)
+	notificationService := service.NewNotificationService(storage)
+	supplierService := service.NewSupplierService(storage, authorizer)
+	conflictService := service.NewConflictService(storage, authorizer)
+	templateService := service.NewTemplateService(storage, authorizer)
+	scheduleService := service.NewScheduleService(storage, authorizer, tenderService)
+	jobService := service.NewJobService(storage, jobQueue, adminUsernames())
+
+	scheduleJobs := jobs.Options{MaxAttempts: scheduledomain.MaxAttempts}
+	jobs.Handle(runner, scheduledomain.PublishTender, scheduleJobs, scheduleService.PublishScheduled)
+	jobs.Handle(runner, scheduledomain.SpawnRecurrence, scheduleJobs, scheduleService.SpawnRecurring)
+	transferService := service.NewTransferService(storage, authorizer, storage)
+	attachmentService := service.NewAttachmentService(storage, authorizer)
+
+	var limitStore ratelimit.Store = ratelimit.NewMemory()
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		limitStore = storage.RateLimitStore()
+	}
+
+	trustedProxies, err := clientip.ParseTrusted(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Error("Failed to read TRUSTED_PROXIES", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		os.Exit(1)
+	}
+
+	apiDoc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		log.Error("Failed to load the API document", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		os.Exit(1)
+	}
+
+	handler := router.New(log, storage, blobStore, router.Services{
+		Tender:       tenderService,
+		Bid:          bidService,
+		Role:         roleService,
+		Organization: organizationService,
+		Employee:     employeeService,
+		Lot:          lotService,
+		Auction:      auctionService,
+		Audit:        auditService,
+		Notification: notificationService,
+		Supplier:     supplierService,
+		Conflict:     conflictService,
+		Template:     templateService,
+		Schedule:     scheduleService,
+		Job:          jobService,
+		Transfer:     transferService,
+		Attachment:   attachmentService,
+	}, router.Options{
+		Doc:            apiDoc,
+		Spec:           api.OpenAPI,
+		TrustedProxies: trustedProxies,
+		LimitStore:     limitStore,
+		TendersLimit:   rateLimitPolicy(log, "TENDERS", router.DefaultTendersLimit),
+		BidsLimit:      rateLimitPolicy(log, "BIDS", router.DefaultBidsLimit),
+		AccountsLimit:  rateLimitPolicy(log, "ACCOUNTS", router.DefaultAccountsLimit),
+		BulkLimit:      rateLimitPolicy(log, "BULK", router.DefaultBulkLimit),
 	})
 
 	done := make(chan os.Signal, 1)
@@ -64,16 +132,119 @@ func main() {
 
 	srv := &http.Server{
 		Addr:    ":8080",
-		Handler: router,
+		Handler: handler,
 	}
 
 	go func() {
-		if err := srv.ListenAndServe(); err != nil {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Error("failed to start the server")
 		}
 	}()
 
+	sched := scheduler.New(log, storage.NewLease(postgres.SchedulerLock), 5*time.Second)
+	sched.Every("settle auctions", auctionService.SettleExpired)
+	sched.Every("purge idempotency keys", storage.PurgeIdempotencyKeys)
+	if store, ok := limitStore.(*postgres.RateLimitStore); ok {
+		sched.Every("purge rate limit buckets", store.Purge)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go sched.Run(ctx)
+
+	runnerDone := make(chan struct{})
+	go func() {
+		runner.Run(ctx)
+		close(runnerDone)
+	}()
+
 	log.Info("starting server on port 8080")
 	<-done
+	log.Info("stopping server")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("Failed to stop the server", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+	}
+
+	cancel()
+	select {
+	case <-runnerDone:
+	case <-shutdownCtx.Done():
+		log.Error("Background jobs did not finish in time")
+	}
+
 	log.Info("server stopped")
 }
+
+func newBlobStore() (blob.BlobStore, error) {
+	if os.Getenv("BLOB_STORAGE") == "s3" {
+		return blob.NewS3(blob.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	}
+
+	path := os.Getenv("BLOB_LOCAL_PATH")
+	if path == "" {
+		path = "./data/attachments"
+	}
+	return blob.NewLocal(path)
+}
+
+// newMailer returns the SMTP sender configured by the environment, or nil
+// when SMTP_HOST is unset and notifications stay in-app only.
+func newMailer() notify.Sender {
+	if os.Getenv("SMTP_HOST") == "" {
+		return nil
+	}
+
+	return notify.NewSMTP(notify.SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	})
+}
+
+// adminUsernames returns the users allowed to inspect the whole
+// installation, listed comma-separated in ADMIN_USERNAMES.
+func adminUsernames() []string {
+	var result []string
+	for _, username := range strings.Split(os.Getenv("ADMIN_USERNAMES"), ",") {
+		if username = strings.TrimSpace(username); username != "" {
+			result = append(result, username)
+		}
+	}
+	return result
+}
+
+// rateLimitPolicy overrides the defaults of a route group with the
+// RATE_LIMIT_<GROUP>_USER, _ORG and _IP variables, written like 60/m. Zero
+// counts turn a limit off.
+func rateLimitPolicy(log *slog.Logger, group string, policy ratelimit.Policy) ratelimit.Policy {
+	for suffix, limit := range map[string]*ratelimitdomain.Limit{
+		"USER": &policy.User,
+		"ORG":  &policy.Organization,
+		"IP":   &policy.IP,
+	} {
+		name := "RATE_LIMIT_" + group + "_" + suffix
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := ratelimitdomain.ParseLimit(value)
+		if err != nil {
+			log.Error("Failed to parse "+name, slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			continue
+		}
+		*limit = parsed
+	}
+	return policy
+}
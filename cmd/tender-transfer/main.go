@@ -0,0 +1,124 @@
+// Command tender-transfer exports and imports tenders and bids in bulk with
+// the same permission checks as the HTTP API.
+//
+//	tender-transfer [flags] export|import tenders|bids
+//
+// Files are read from stdin and written to stdout unless -file is given.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"tender_system/internal/authz"
+	transferlib "tender_system/internal/lib/transfer"
+	"tender_system/internal/models/transfer"
+	"tender_system/internal/service"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	username := flag.String("username", "", "user to act as")
+	format := flag.String("format", string(transferlib.CSV), "csv or jsonl")
+	file := flag.String("file", "", "file to read or write instead of stdin or stdout")
+	organization := flag.String("organization", "", "export only what is visible while acting for this organization")
+	serviceType := flag.String("service-type", "", "export only tenders of this service type")
+	status := flag.String("status", "", "export only tenders or bids in this status")
+	tenderId := flag.String("tender", "", "export only bids on this tender")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] export|import tenders|bids\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 || *username == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	action, kind := flag.Arg(0), flag.Arg(1)
+	if (action != "export" && action != "import") || (kind != "tenders" && kind != "bids") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := transferlib.ParseFormat(*format)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(2)
+	}
+
+	err = godotenv.Load()
+	if err != nil {
+		log.Warn("Failed to load .env", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+	}
+
+	storage, err := postgres.New(os.Getenv("POSTGRES_CONN"))
+	if err != nil {
+		log.Error("Failed to connect to postgresql", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		os.Exit(1)
+	}
+	transfers := service.NewTransferService(storage, authz.New(storage), storage)
+
+	if action == "export" {
+		out := io.Writer(os.Stdout)
+		if *file != "" {
+			dst, err := os.Create(*file)
+			if err != nil {
+				log.Error("Failed to create the file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				os.Exit(1)
+			}
+			defer dst.Close()
+			out = dst
+		}
+
+		if kind == "tenders" {
+			err = transfers.ExportTenders(*username, *organization, transfer.TenderFilter{ServiceType: *serviceType, Status: *status}, f, out)
+		} else {
+			err = transfers.ExportBids(*username, *organization, transfer.BidFilter{TenderId: *tenderId, Status: *status}, f, out)
+		}
+		if err != nil {
+			log.Error("Failed to export "+kind, slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			os.Exit(1)
+		}
+		return
+	}
+
+	in := io.Reader(os.Stdin)
+	if *file != "" {
+		src, err := os.Open(*file)
+		if err != nil {
+			log.Error("Failed to open the file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			os.Exit(1)
+		}
+		defer src.Close()
+		in = src
+	}
+
+	var report transfer.Report
+	if kind == "tenders" {
+		report, err = transfers.ImportTenders(context.Background(), *username, f, in)
+	} else {
+		report, err = transfers.ImportBids(context.Background(), *username, f, in)
+	}
+	if err != nil && len(report.Errors) == 0 {
+		log.Error("Failed to import "+kind, slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if encErr := encoder.Encode(report); encErr != nil {
+		log.Error("Failed to print the report", slog.Attr{Key: "error", Value: slog.StringValue(encErr.Error())})
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
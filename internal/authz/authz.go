@@ -0,0 +1,117 @@
+package authz
+
+import (
+	"fmt"
+	"tender_system/internal/storage"
+)
+
+type Role string
+
+const (
+	Owner              Role = "Owner"
+	ProcurementManager Role = "ProcurementManager"
+	Reviewer           Role = "Reviewer"
+	Viewer             Role = "Viewer"
+	Bidder             Role = "Bidder"
+)
+
+type Permission string
+
+const (
+	CreateTender       Permission = "tender:create"
+	EditTender         Permission = "tender:edit"
+	PublishTender      Permission = "tender:publish"
+	ViewTender         Permission = "tender:view"
+	ViewBids           Permission = "bids:view"
+	LeaveFeedback      Permission = "feedback:write"
+	ReadFeedback       Permission = "feedback:read"
+	Vote               Permission = "decision:vote"
+	ViewAward          Permission = "award:view"
+	SubmitBid          Permission = "bid:submit"
+	ManageRoles        Permission = "roles:manage"
+	ManageOrganization Permission = "organization:manage"
+	ViewAudit          Permission = "audit:view"
+)
+
+// matrix lists what each organization role may do. Responsibles of an
+// organization are always Owners of it.
+var matrix = map[Role][]Permission{
+	Owner: {
+		CreateTender, EditTender, PublishTender, ViewTender, ViewBids,
+		LeaveFeedback, ReadFeedback, Vote, ViewAward, SubmitBid, ManageRoles,
+		ManageOrganization, ViewAudit,
+	},
+	ProcurementManager: {
+		CreateTender, EditTender, PublishTender, ViewTender, ViewBids,
+		LeaveFeedback, ReadFeedback, Vote, ViewAward,
+	},
+	Reviewer: {ViewTender, ViewBids, LeaveFeedback, ReadFeedback, Vote, ViewAward},
+	Viewer:   {ViewTender, ViewBids, ReadFeedback, ViewAward},
+	Bidder:   {SubmitBid, ViewAward},
+}
+
+func ValidRole(role string) bool {
+	_, ok := matrix[Role(role)]
+	return ok
+}
+
+func Roles() []Role {
+	return []Role{Owner, ProcurementManager, Reviewer, Viewer, Bidder}
+}
+
+func Allows(role Role, perm Permission) bool {
+	for _, p := range matrix[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func AllowsAny(roles []string, perm Permission) bool {
+	for _, role := range roles {
+		if Allows(Role(role), perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// Membership returns the effective roles of a user in an organization.
+type Membership interface {
+	ReadMemberRoles(organizationId, userId string) ([]string, error)
+}
+
+// Authorizer is the single place that decides whether a user may perform an
+// action on behalf of an organization.
+type Authorizer struct {
+	members Membership
+}
+
+func New(members Membership) *Authorizer {
+	return &Authorizer{members: members}
+}
+
+func (a *Authorizer) Can(organizationId, userId string, perm Permission) (bool, error) {
+	const op = "authz.Authorizer.Can"
+
+	roles, err := a.members.ReadMemberRoles(organizationId, userId)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return AllowsAny(roles, perm), nil
+}
+
+// Require returns storage.ErrForbidden unless the user holds a role granting
+// perm in the organization.
+func (a *Authorizer) Require(organizationId, userId string, perm Permission) error {
+	ok, err := a.Can(organizationId, userId, perm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s is required", storage.ErrForbidden, perm)
+	}
+	return nil
+}
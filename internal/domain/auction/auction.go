@@ -0,0 +1,97 @@
+// Package auction holds the rules of reverse auctions: bidders undercut the
+// best price in timed rounds until a round passes without a new price or the
+// last round ends.
+package auction
+
+import (
+	"errors"
+	"fmt"
+	biddomain "tender_system/internal/domain/bid"
+	"tender_system/internal/models/auction"
+	"time"
+)
+
+const (
+	Pending  = "Pending"
+	Running  = "Running"
+	Finished = "Finished"
+)
+
+// ReverseAuction is the tender type that runs an auction once published.
+const ReverseAuction = "ReverseAuction"
+
+var ErrRejected = errors.New("price rejected")
+
+// Start opens the first round.
+func Start(settings auction.Settings, state auction.State, now time.Time) auction.State {
+	if state.Status != Pending {
+		return state
+	}
+
+	endsAt := now.Add(time.Duration(settings.RoundSeconds) * time.Second)
+	state.Status = Running
+	state.Round = 1
+	state.RoundBids = 0
+	state.RoundEndsAt = &endsAt
+	return state
+}
+
+// Advance moves the auction past every round that ended before now. A round
+// without new prices or the last round finishes the auction.
+func Advance(settings auction.Settings, state auction.State, now time.Time) auction.State {
+	for state.Status == Running && !now.Before(*state.RoundEndsAt) {
+		if state.RoundBids == 0 || state.Round >= settings.MaxRounds {
+			state.Status = Finished
+			state.ProposedBidId = state.BestBidId
+			break
+		}
+
+		endsAt := state.RoundEndsAt.Add(time.Duration(settings.RoundSeconds) * time.Second)
+		state.Round++
+		state.RoundBids = 0
+		state.RoundEndsAt = &endsAt
+	}
+	return state
+}
+
+// CanBid reports whether a bid in the given status may place prices.
+func CanBid(status string) bool {
+	status = biddomain.Normalize(status)
+	return status == biddomain.Draft || status == biddomain.Submitted
+}
+
+// Ceiling is the highest price the next bid may offer.
+func Ceiling(settings auction.Settings, state auction.State) float64 {
+	if state.BestPrice == nil {
+		return settings.StartPrice
+	}
+	return *state.BestPrice - settings.MinDecrement
+}
+
+// Place accepts price from bidId if it undercuts the best price by at least
+// the minimum decrement. Late prices extend the round.
+func Place(settings auction.Settings, state auction.State, bidId string, price float64, now time.Time) (auction.State, error) {
+	state = Advance(settings, state, now)
+	if state.Status != Running {
+		return state, fmt.Errorf("%w: the auction is %s", ErrRejected, state.Status)
+	}
+
+	if ceiling := Ceiling(settings, state); price > ceiling {
+		return state, fmt.Errorf("%w: the price must not exceed %.2f", ErrRejected, ceiling)
+	}
+	if price <= 0 {
+		return state, fmt.Errorf("%w: the price must be positive", ErrRejected)
+	}
+
+	state.BestPrice = &price
+	state.BestBidId = bidId
+	state.RoundBids++
+
+	extension := time.Duration(settings.ExtensionSeconds) * time.Second
+	if state.RoundEndsAt.Sub(now) < extension {
+		endsAt := now.Add(extension)
+		state.RoundEndsAt = &endsAt
+	}
+
+	return state, nil
+}
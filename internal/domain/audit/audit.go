@@ -0,0 +1,94 @@
+// Package audit chains audit log entries together so that changing or
+// removing any of them is detectable.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"tender_system/internal/models/audit"
+	"time"
+)
+
+const (
+	Tender       = "tender"
+	Bid          = "bid"
+	Organization = "organization"
+	Employee     = "employee"
+)
+
+// Origin is where a change was asked for: the client address and request id
+// of an API call. Changes made by background work have none.
+type Origin struct {
+	IP        string
+	RequestId string
+}
+
+type originKey struct{}
+
+// WithOrigin returns a copy of ctx carrying the origin of the changes made
+// under it.
+func WithOrigin(ctx context.Context, o Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, o)
+}
+
+// OriginFrom returns the origin ctx carries, or a zero one.
+func OriginFrom(ctx context.Context) Origin {
+	o, _ := ctx.Value(originKey{}).(Origin)
+	return o
+}
+
+// Hash returns the hash of the entry chained to prevHash. It covers every
+// field except Hash itself.
+func Hash(prevHash string, e audit.Entry) string {
+	payload, _ := json.Marshal(struct {
+		Id             int64
+		CreatedAt      string
+		EntityType     string
+		EntityId       string
+		Action         string
+		Actor          string
+		OrganizationId string
+		IP             string
+		RequestId      string
+		Before         string
+		After          string
+	}{
+		e.Id,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.EntityType,
+		e.EntityId,
+		e.Action,
+		e.Actor,
+		e.OrganizationId,
+		e.IP,
+		e.RequestId,
+		string(e.Before),
+		string(e.After),
+	})
+
+	sum := sha256.Sum256(append([]byte(prevHash+"\n"), payload...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain fills in the id, previous hash and hash of e so that it follows last.
+// A zero last starts the chain.
+func Chain(last audit.Entry, e audit.Entry) audit.Entry {
+	e.Id = last.Id + 1
+	e.PrevHash = last.Hash
+	e.Hash = Hash(e.PrevHash, e)
+	return e
+}
+
+// Verify walks consecutive entries, oldest first, starting after last, and
+// returns the id of the first entry that does not match the chain, or 0.
+func Verify(last audit.Entry, entries []audit.Entry) int64 {
+	for _, e := range entries {
+		if e.Id != last.Id+1 || e.PrevHash != last.Hash || e.Hash != Hash(e.PrevHash, e) {
+			return e.Id
+		}
+		last = e
+	}
+	return 0
+}
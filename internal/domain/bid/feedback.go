@@ -0,0 +1,20 @@
+package bid
+
+import "time"
+
+// FeedbackEditWindow is how long after writing a comment its author may still
+// edit or delete it.
+const FeedbackEditWindow = 15 * time.Minute
+
+const (
+	// SideTender marks comments written by reviewers of the tender.
+	SideTender = "Tender"
+	// SideBid marks comments written on behalf of the bid author.
+	SideBid = "Bid"
+)
+
+// CanChangeComment reports whether a comment written at createdAt may still
+// be edited or deleted at now.
+func CanChangeComment(createdAt, now time.Time) bool {
+	return now.Sub(createdAt) <= FeedbackEditWindow
+}
@@ -0,0 +1,117 @@
+package bid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	Draft     = "Draft"
+	Submitted = "Submitted"
+	Withdrawn = "Withdrawn"
+	Approved  = "Approved"
+	Rejected  = "Rejected"
+)
+
+var ErrInvalidTransition = errors.New("invalid bid status transition")
+
+// TransitionError explains why a bid cannot move from one status to another.
+type TransitionError struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change bid status from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+var transitions = map[string][]string{
+	Draft:     {Submitted, Withdrawn},
+	Submitted: {Withdrawn, Approved, Rejected},
+	Withdrawn: {Submitted},
+	Approved:  {},
+	Rejected:  {},
+}
+
+// legacy maps the statuses used before the state machine was introduced.
+var legacy = map[string]string{
+	"Created":   Draft,
+	"Published": Submitted,
+	"Canceled":  Withdrawn,
+}
+
+// Normalize converts legacy statuses to their state machine equivalents and
+// returns other values unchanged.
+func Normalize(status string) string {
+	if s, ok := legacy[status]; ok {
+		return s
+	}
+	return status
+}
+
+func Valid(status string) bool {
+	_, ok := transitions[Normalize(status)]
+	return ok
+}
+
+// IsDecision reports whether the status can only be reached by voting.
+func IsDecision(status string) bool {
+	status = Normalize(status)
+	return status == Approved || status == Rejected
+}
+
+// Next lists the statuses reachable from the given one.
+func Next(from string) []string {
+	return transitions[Normalize(from)]
+}
+
+// CheckEdit tells why a bid in the given status cannot be edited, or returns
+// an empty refusal when it can. Only drafts and submitted bids change, and
+// only until the tender deadline; a nil deadline never passes.
+func CheckEdit(status string, deadline *time.Time, now time.Time) (refusal string) {
+	status = Normalize(status)
+	if status != Draft && status != Submitted {
+		return fmt.Sprintf("%s bids cannot be edited", strings.ToLower(status))
+	}
+	if deadline != nil && now.After(*deadline) {
+		return "the tender deadline has passed"
+	}
+	return ""
+}
+
+// Transition checks that a bid in status from may move to status to. A nil
+// deadline means the tender accepts bids indefinitely.
+func Transition(from, to string, deadline *time.Time, now time.Time) error {
+	from, to = Normalize(from), Normalize(to)
+
+	if _, ok := transitions[to]; !ok {
+		return &TransitionError{From: from, To: to, Reason: "unknown status"}
+	}
+
+	allowed := false
+	for _, next := range transitions[from] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		if len(transitions[from]) == 0 {
+			return &TransitionError{From: from, To: to, Reason: fmt.Sprintf("%s is a final status", from)}
+		}
+		return &TransitionError{From: from, To: to, Reason: fmt.Sprintf("allowed statuses are %v", transitions[from])}
+	}
+
+	if deadline != nil && now.After(*deadline) && (to == Submitted || to == Withdrawn) {
+		return &TransitionError{From: from, To: to, Reason: "the tender deadline has passed"}
+	}
+
+	return nil
+}
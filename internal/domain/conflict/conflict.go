@@ -0,0 +1,87 @@
+// Package conflict decides what happens when someone bids on or votes for a
+// tender they are too close to.
+package conflict
+
+import (
+	"fmt"
+	"strings"
+	"tender_system/internal/models/conflict"
+)
+
+const (
+	// MemberBid is hit when a member of the tender's organization, or the
+	// organization itself, bids on the tender.
+	MemberBid = "member_bid"
+	// OwnBidVote is hit when a voter decides on a bid they authored or that
+	// an organization they belong to placed.
+	OwnBidVote = "own_bid_vote"
+	// ColleagueVote is hit when a voter decides on a bid of a user they share
+	// another organization with.
+	ColleagueVote = "colleague_vote"
+)
+
+const (
+	// Block refuses the bid or vote.
+	Block = "Block"
+	// Record lets it through but writes the conflict to the audit trail.
+	Record = "Record"
+	// Allow ignores the rule.
+	Allow = "Allow"
+)
+
+var defaults = map[string]string{
+	MemberBid:     Block,
+	OwnBidVote:    Block,
+	ColleagueVote: Record,
+}
+
+// Rules lists every rule with its default action.
+func Rules() []conflict.Rule {
+	return []conflict.Rule{
+		{Rule: MemberBid, Action: defaults[MemberBid]},
+		{Rule: OwnBidVote, Action: defaults[OwnBidVote]},
+		{Rule: ColleagueVote, Action: defaults[ColleagueVote]},
+	}
+}
+
+func ValidRule(rule string) bool {
+	_, ok := defaults[rule]
+	return ok
+}
+
+// Evaluate applies the organization's actions, falling back to the defaults,
+// to the rules that were hit. Allowed hits are dropped; blocked reports
+// whether any of the remaining ones blocks.
+func Evaluate(rules []conflict.Rule, hits []conflict.Finding) (findings []conflict.Finding, blocked bool) {
+	actions := make(map[string]string, len(defaults))
+	for rule, action := range defaults {
+		actions[rule] = action
+	}
+	for _, r := range rules {
+		actions[r.Rule] = r.Action
+	}
+
+	for _, hit := range hits {
+		hit.Action = actions[hit.Rule]
+		if hit.Action == Allow {
+			continue
+		}
+		if hit.Action == Block {
+			blocked = true
+		}
+		findings = append(findings, hit)
+	}
+
+	return findings, blocked
+}
+
+// Describe joins the details of blocking findings into one message.
+func Describe(findings []conflict.Finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, f := range findings {
+		if f.Action == Block {
+			parts = append(parts, fmt.Sprintf("%s: %s", f.Rule, f.Detail))
+		}
+	}
+	return "conflict of interest (" + strings.Join(parts, "; ") + ")"
+}
@@ -0,0 +1,113 @@
+// Package ratelimit holds the token bucket arithmetic shared by the rate
+// limit stores.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit lets Burst requests through at once and refills at Rate tokens per
+// second. A zero limit does not limit anything.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Full is the bucket of a key seen for the first time.
+func Full(l Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(l.Burst), UpdatedAt: now}
+}
+
+// Take refills the bucket up to now and takes a token from it. Without a
+// token left it returns how long until the next one.
+func Take(b Bucket, l Limit, now time.Time) (Bucket, bool, time.Duration) {
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	b.Tokens = math.Min(float64(l.Burst), b.Tokens+elapsed*l.Rate)
+	b.UpdatedAt = now
+
+	if b.Tokens < 1 {
+		wait := time.Duration((1 - b.Tokens) / l.Rate * float64(time.Second))
+		return b, false, wait
+	}
+
+	b.Tokens--
+	return b, true, 0
+}
+
+// Charge is a token a request takes from the bucket of Key.
+type Charge struct {
+	Key   string
+	Limit Limit
+}
+
+// TakeAll refills the buckets up to now and takes a token from each of them,
+// or from none when one of them is empty. A request throttled by one bucket
+// thus leaves the others, which colleagues may share, untouched. Without a
+// token left it returns how long until every bucket has one.
+func TakeAll(buckets []Bucket, limits []Limit, now time.Time) ([]Bucket, bool, time.Duration) {
+	next := make([]Bucket, len(buckets))
+	var wait time.Duration
+	for i, b := range buckets {
+		refilled, allowed, after := Take(b, limits[i], now)
+		if !allowed {
+			wait = max(wait, after)
+			next[i] = refilled
+			continue
+		}
+		// Taken back until every bucket is known to have a token.
+		refilled.Tokens++
+		next[i] = refilled
+	}
+	if wait > 0 {
+		return next, false, wait
+	}
+
+	for i := range next {
+		next[i].Tokens--
+	}
+	return next, true, 0
+}
+
+// ParseLimit reads a limit written as count/unit with unit s, m or h, such
+// as 60/m. The whole count may be spent at once.
+func ParseLimit(s string) (Limit, error) {
+	count, unit, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q is not count/unit", s)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("limit %q has an invalid count", s)
+	}
+
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("limit %q has an invalid unit", s)
+	}
+
+	return Limit{Rate: float64(n) / per.Seconds(), Burst: n}, nil
+}
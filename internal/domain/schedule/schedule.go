@@ -0,0 +1,52 @@
+// Package schedule holds the rules for jobs that run at a set time: scheduled
+// tender publication and recurring tenders.
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"tender_system/internal/models/job"
+	"tender_system/internal/models/schedule"
+	"time"
+)
+
+const (
+	// PublishTender publishes the tender named by the job's subject.
+	PublishTender = "publish_tender"
+	// SpawnRecurrence creates the next tender of the recurrence named by the
+	// job's subject.
+	SpawnRecurrence = "spawn_recurrence"
+)
+
+const (
+	Monthly   = "Monthly"
+	Quarterly = "Quarterly"
+)
+
+// MaxAttempts is how often a failing job runs before it is given up.
+const MaxAttempts = 5
+
+// Next returns the run following t for the frequency.
+func Next(frequency string, t time.Time) (time.Time, error) {
+	switch frequency {
+	case Monthly:
+		return t.AddDate(0, 1, 0), nil
+	case Quarterly:
+		return t.AddDate(0, 3, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown frequency %s", frequency)
+	}
+}
+
+// NewJob returns the background job of the kind acting on the subject on
+// behalf of actor at runAt.
+func NewJob(kind, subjectId, actor string, runAt time.Time) job.Job {
+	payload, _ := json.Marshal(schedule.JobPayload{SubjectId: subjectId, Actor: actor})
+	return job.Job{
+		Kind:        kind,
+		SubjectId:   subjectId,
+		Payload:     payload,
+		MaxAttempts: MaxAttempts,
+		RunAt:       runAt.UTC(),
+	}
+}
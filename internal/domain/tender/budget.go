@@ -0,0 +1,59 @@
+package tender
+
+import (
+	"fmt"
+	"tender_system/internal/models/tender"
+)
+
+const (
+	BudgetPublic = "Public"
+	BudgetHidden = "Hidden"
+)
+
+const (
+	// BudgetReject refuses bids priced outside the budget.
+	BudgetReject = "Reject"
+	// BudgetFlag accepts such bids but marks them for the reviewers.
+	BudgetFlag = "Flag"
+)
+
+// WithinBudget reports whether price lies between the optional bounds.
+func WithinBudget(min, max *float64, price float64) bool {
+	if min != nil && price < *min {
+		return false
+	}
+	if max != nil && price > *max {
+		return false
+	}
+	return true
+}
+
+// CheckPrice compares a bid price with the tender budget. Prices outside it
+// are refused under the Reject policy, with the refusal naming the budget
+// unless it is hidden, and marked out of budget otherwise.
+func CheckPrice(b tender.Budget, price float64) (outOfBudget bool, refusal string) {
+	if WithinBudget(b.Min, b.Max, price) {
+		return false, ""
+	}
+
+	if b.Policy != BudgetReject {
+		return true, ""
+	}
+
+	if b.Visibility == BudgetHidden {
+		return false, "the price is outside the tender budget"
+	}
+	return false, "the price is outside the tender budget " + formatBudget(b)
+}
+
+func formatBudget(b tender.Budget) string {
+	switch {
+	case b.Min != nil && b.Max != nil:
+		return fmt.Sprintf("%.2f-%.2f %s", *b.Min, *b.Max, b.Currency)
+	case b.Min != nil:
+		return fmt.Sprintf("from %.2f %s", *b.Min, b.Currency)
+	case b.Max != nil:
+		return fmt.Sprintf("up to %.2f %s", *b.Max, b.Currency)
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package tender
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Created   = "Created"
+	Published = "Published"
+	Closed    = "Closed"
+	Cancelled = "Cancelled"
+	Awarded   = "Awarded"
+)
+
+var ErrInvalidTransition = errors.New("invalid tender status transition")
+
+// TransitionError explains why a tender cannot move from one status to
+// another.
+type TransitionError struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change tender status from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// Facts are the parts of the tender state the guards depend on.
+type Facts struct {
+	PendingDecisions int
+	ApprovedBids     int
+}
+
+var transitions = map[string][]string{
+	Created:   {Published, Cancelled},
+	Published: {Closed, Awarded, Cancelled},
+	Closed:    {Published, Awarded, Cancelled},
+	Cancelled: {},
+	Awarded:   {},
+}
+
+func Valid(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// IsSystem reports whether the status is only set by the server itself, e.g.
+// Awarded once a bid reaches the approval quorum.
+func IsSystem(status string) bool {
+	return status == Awarded
+}
+
+// AcceptsBids reports whether new bids may be placed on a tender.
+func AcceptsBids(status string) bool {
+	return status == Created || status == Published
+}
+
+// AcceptsDecisions reports whether the bids on a tender may still be voted
+// on, that is whether it may yet be awarded.
+func AcceptsDecisions(status string) bool {
+	return status == Published || status == Closed
+}
+
+// Transition checks that a tender in status from may move to status to.
+func Transition(from, to string, facts Facts) error {
+	if !Valid(to) {
+		return &TransitionError{From: from, To: to, Reason: "unknown status"}
+	}
+
+	allowed := false
+	for _, next := range transitions[from] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		if len(transitions[from]) == 0 {
+			return &TransitionError{From: from, To: to, Reason: fmt.Sprintf("%s is a final status", from)}
+		}
+		return &TransitionError{From: from, To: to, Reason: fmt.Sprintf("allowed statuses are %v", transitions[from])}
+	}
+
+	switch {
+	case to == Closed && facts.PendingDecisions > 0:
+		return &TransitionError{From: from, To: to, Reason: fmt.Sprintf("%d decisions are still pending, cancel the tender instead", facts.PendingDecisions)}
+	case to == Published && facts.ApprovedBids > 0:
+		return &TransitionError{From: from, To: to, Reason: "the tender already has an approved bid"}
+	case to == Awarded && facts.ApprovedBids == 0:
+		return &TransitionError{From: from, To: to, Reason: "the tender has no approved bid"}
+	}
+
+	return nil
+}
+
+// Next lists the statuses a tender may move to given the current facts.
+// Statuses only the server can set are left out.
+func Next(from string, facts Facts) []string {
+	result := make([]string, 0)
+	for _, to := range transitions[from] {
+		if IsSystem(to) {
+			continue
+		}
+		if Transition(from, to, facts) == nil {
+			result = append(result, to)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package admin
+
+import (
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/job"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/render"
+)
+
+type FailedJobLister interface {
+	ListFailedJobs(username, kind string, limit, offset int) ([]job.Job, error)
+}
+
+// NewGetFailedJobs lists the background jobs that ran out of attempts,
+// optionally only those of the kind query parameter.
+func NewGetFailedJobs(log *slog.Logger, failedJobLister FailedJobLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		username := query.Get("username")
+		if username == "" {
+			render.Status(r, 401)
+			render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+			return
+		}
+
+		limit, offset := 20, 0
+		var err error
+		if query.Get("limit") != "" {
+			limit, err = strconv.Atoi(query.Get("limit"))
+			if err != nil || limit < 0 || limit > 100 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect limit value"))
+				return
+			}
+		}
+		if query.Get("offset") != "" {
+			offset, err = strconv.Atoi(query.Get("offset"))
+			if err != nil || offset < 0 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect offset value"))
+				return
+			}
+		}
+
+		resp, err := failedJobLister.ListFailedJobs(username, query.Get("kind"), limit, offset)
+		if err != nil {
+			switch {
+			case serrors.Is(err, postgres.ErrUserNotFound):
+				render.Status(r, 401)
+			case serrors.Is(err, postgres.ErrForbidden):
+				render.Status(r, 403)
+			default:
+				log.Error("Failed to list failed jobs", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Status(r, 500)
+			}
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
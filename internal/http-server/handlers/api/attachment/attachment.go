@@ -0,0 +1,257 @@
+package attachment
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	serrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/attachment"
+	"tender_system/internal/storage/blob"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const MaxUploadSize = 20 << 20
+
+type AttachmentAuthorizer interface {
+	AuthorizeAttachments(entityType, entityId, username string, write bool) error
+}
+
+type AttachmentSaver interface {
+	AttachmentAuthorizer
+	AddAttachment(ctx context.Context, att attachment.Attachment) (attachment.Attachment, error)
+}
+
+type AttachmentLister interface {
+	AttachmentAuthorizer
+	ListAttachments(entityType, entityId string) ([]attachment.Attachment, error)
+}
+
+type AttachmentGetter interface {
+	AttachmentAuthorizer
+	GetAttachment(entityType, entityId, attachmentId string) (attachment.Attachment, error)
+}
+
+type AttachmentRemover interface {
+	AttachmentAuthorizer
+	RemoveAttachment(ctx context.Context, entityType, entityId, attachmentId, username string) error
+}
+
+// NewPostAttachment handles multipart uploads of a single "file" part and
+// attaches it to the tender or bid identified by the {tenderId} or {bidId}
+// url parameter, depending on entityType.
+func NewPostAttachment(log *slog.Logger, entityType string, attachmentSaver AttachmentSaver, store blob.BlobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entityId, username, ok := parseParams(w, r, entityType)
+		if !ok {
+			return
+		}
+
+		err := attachmentSaver.AuthorizeAttachments(entityType, entityId, username, true)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			log.Error("Failed to read uploaded file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The file is missing or too large"))
+			return
+		}
+		defer file.Close()
+
+		fileName := filepath.Base(header.Filename)
+		if fileName == "" || fileName == "." || len(fileName) > 255 {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The file name is invalid"))
+			return
+		}
+
+		contentType := header.Header.Get("Content-Type")
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			contentType = "application/octet-stream"
+		}
+
+		key, err := newStorageKey(entityType, entityId)
+		if err != nil {
+			render.Status(r, 500)
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		hash := sha256.New()
+		err = store.Put(r.Context(), key, io.TeeReader(file, hash), header.Size, contentType)
+		if err != nil {
+			log.Error("Failed to store the file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Status(r, 500)
+			render.JSON(w, r, errors.NewHttpError("Failed to store the file"))
+			return
+		}
+
+		resp, err := attachmentSaver.AddAttachment(r.Context(), attachment.Attachment{
+			EntityType:  entityType,
+			EntityId:    entityId,
+			FileName:    fileName,
+			ContentType: contentType,
+			Size:        header.Size,
+			Checksum:    hex.EncodeToString(hash.Sum(nil)),
+			StorageKey:  key,
+			UploadedBy:  username,
+		})
+		if err != nil {
+			if err := store.Delete(r.Context(), key); err != nil {
+				log.Error("Failed to clean up the file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetAttachments(log *slog.Logger, entityType string, attachmentLister AttachmentLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entityId, username, ok := parseParams(w, r, entityType)
+		if !ok {
+			return
+		}
+
+		err := attachmentLister.AuthorizeAttachments(entityType, entityId, username, false)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		resp, err := attachmentLister.ListAttachments(entityType, entityId)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDownloadAttachment(log *slog.Logger, entityType string, attachmentGetter AttachmentGetter, store blob.BlobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entityId, username, ok := parseParams(w, r, entityType)
+		if !ok {
+			return
+		}
+
+		err := attachmentGetter.AuthorizeAttachments(entityType, entityId, username, false)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		att, err := attachmentGetter.GetAttachment(entityType, entityId, chi.URLParam(r, "attachmentId"))
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		body, err := store.Get(r.Context(), att.StorageKey)
+		if err != nil {
+			log.Error("Failed to read the file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			if serrors.Is(err, blob.ErrNotFound) {
+				render.Status(r, 404)
+			} else {
+				render.Status(r, 500)
+			}
+			render.JSON(w, r, errors.NewHttpError("Failed to read the file"))
+			return
+		}
+		defer body.Close()
+
+		w.Header().Set("Content-Type", att.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(att.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": att.FileName}))
+		w.Header().Set("Digest", "sha-256="+att.Checksum)
+		_, err = io.Copy(w, body)
+		if err != nil {
+			log.Error("Failed to send the file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+	}
+}
+
+func NewDeleteAttachment(log *slog.Logger, entityType string, attachmentRemover AttachmentRemover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entityId, username, ok := parseParams(w, r, entityType)
+		if !ok {
+			return
+		}
+
+		err := attachmentRemover.AuthorizeAttachments(entityType, entityId, username, true)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		err = attachmentRemover.RemoveAttachment(r.Context(), entityType, entityId, chi.URLParam(r, "attachmentId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func parseParams(w http.ResponseWriter, r *http.Request, entityType string) (string, string, bool) {
+	entityId := chi.URLParam(r, entityType+"Id")
+	if entityId == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(fmt.Sprintf("The %s id is invalid", entityType)))
+		return "", "", false
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	return entityId, username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
+
+func newStorageKey(entityType, entityId string) (string, error) {
+	buf := make([]byte, 16)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%ss/%s/%s", entityType, entityId, hex.EncodeToString(buf)), nil
+}
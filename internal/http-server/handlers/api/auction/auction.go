@@ -0,0 +1,57 @@
+package auction
+
+import (
+	"context"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type AuctionReader interface {
+	ReadAuction(ctx context.Context, tenderId, username string) (auction.View, error)
+}
+
+// NewGetAuction returns the current round and best price of a reverse
+// auction. The username is only needed before the tender is published.
+func NewGetAuction(log *slog.Logger, auctionReader AuctionReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId := chi.URLParam(r, "tenderId")
+		if tenderId == "" {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+			return
+		}
+
+		resp, err := auctionReader.ReadAuction(r.Context(), tenderId, r.URL.Query().Get("username"))
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"tender_system/internal/http-server/middleware/actingorg"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/storage/postgres"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type AuditLister interface {
+	ListAudit(username string, f audit.Filter) ([]audit.Entry, error)
+}
+
+type AuditVerifier interface {
+	VerifyAudit(username string) (audit.Verification, error)
+}
+
+// NewGetAudit lists audit entries of the acting organization, filtered by
+// the entityType, entityId, actor, from and to query parameters. Times are
+// RFC 3339; from is inclusive and to exclusive.
+func NewGetAudit(log *slog.Logger, auditLister AuditLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		f := audit.Filter{
+			OrganizationId: actingorg.FromContext(r.Context()),
+			EntityType:     query.Get("entityType"),
+			EntityId:       query.Get("entityId"),
+			Actor:          query.Get("actor"),
+			Limit:          5,
+		}
+
+		var err error
+		if query.Get("limit") != "" {
+			f.Limit, err = strconv.Atoi(query.Get("limit"))
+			if err != nil || f.Limit < 0 || f.Limit > 100 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect limit value"))
+				return
+			}
+		}
+		if query.Get("offset") != "" {
+			f.Offset, err = strconv.Atoi(query.Get("offset"))
+			if err != nil || f.Offset < 0 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect offset value"))
+				return
+			}
+		}
+
+		for name, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+			if query.Get(name) == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, query.Get(name))
+			if err != nil {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect "+name+" value"))
+				return
+			}
+			*dst = &t
+		}
+
+		resp, err := auditLister.ListAudit(username, f)
+		if err != nil {
+			log.Error("Failed to read audit log", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// NewGetAuditVerification checks that the audit log has not been tampered
+// with.
+func NewGetAuditVerification(log *slog.Logger, auditVerifier AuditVerifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := auditVerifier.VerifyAudit(username)
+		if err != nil {
+			log.Error("Failed to verify audit log", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func parseUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", false
+	}
+
+	return username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
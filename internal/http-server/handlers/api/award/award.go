@@ -0,0 +1,151 @@
+package award
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/contract"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/award"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type AwardReader interface {
+	ReadAward(tenderId, lotId, username string) (award.Award, error)
+}
+
+type ContractReader interface {
+	ReadContract(tenderId, lotId, username string) (award.Contract, error)
+}
+
+type DeliveryRecorder interface {
+	RecordDelivery(ctx context.Context, tenderId, lotId, username string, req award.DeliveryRequest) (award.Award, error)
+}
+
+func NewGetAward(log *slog.Logger, awardReader AwardReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := awardReader.ReadAward(tenderId, r.URL.Query().Get("lotId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetContract(log *slog.Logger, contractReader ContractReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = contract.FormatMarkdown
+		}
+		if format != contract.FormatMarkdown && format != contract.FormatHTML {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The format must be md or html"))
+			return
+		}
+
+		resp, err := contractReader.ReadContract(tenderId, r.URL.Query().Get("lotId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", contract.ContentType(format))
+		w.Header().Set("Content-Disposition", "attachment; filename=\"contract-"+resp.Award.TenderId+"."+format+"\"")
+		err = contract.Render(w, format, resp)
+		if err != nil {
+			log.Error("Failed to render the contract", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+	}
+}
+
+// NewPutDelivery records whether the winner delivered on time, which feeds
+// into the supplier's profile.
+func NewPutDelivery(log *slog.Logger, deliveryRecorder DeliveryRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req award.DeliveryRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&req)
+		if err != nil {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		err = validate.Struct(req)
+		if err != nil {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+			return
+		}
+
+		resp, err := deliveryRecorder.RecordDelivery(r.Context(), tenderId, r.URL.Query().Get("lotId"), username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func parseParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	tenderId := chi.URLParam(r, "tenderId")
+	if tenderId == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+		return "", "", false
+	}
+
+	return tenderId, username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
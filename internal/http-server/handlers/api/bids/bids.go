@@ -1,30 +1,35 @@
 package bids
 
 import (
+	"context"
 	"encoding/json"
 	serrors "errors"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	biddomain "tender_system/internal/domain/bid"
+	"tender_system/internal/http-server/middleware/actingorg"
 	"tender_system/internal/lib/errors"
 	"tender_system/internal/models/bids"
 	"tender_system/internal/storage/postgres"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
 )
 
-type BidSaver interface {
-	SaveBid(bid bids.BidRequest) (bids.BidResponse, error)
+var validate = validator.New()
+
+type BidCreator interface {
+	CreateBid(ctx context.Context, bid bids.BidRequest) (bids.BidResponse, error)
 }
 
 type MyBidsReader interface {
-	ReadMyBids(username string, limit int, offset int) ([]bids.BidResponse, error)
+	ReadMyBids(username, organizationId string, limit, offset int) ([]bids.BidResponse, error)
 }
 
 type TenderBidsReader interface {
-	ReadTenderBids(username string, tenderId string, limit int, offset int) ([]bids.BidResponse, error)
+	ReadTenderBids(username, organizationId, tenderId, lotId string, limit, offset int) ([]bids.BidResponse, error)
 }
 
 type BidStatusReader interface {
@@ -32,30 +37,46 @@ type BidStatusReader interface {
 }
 
 type BidStatusUpdater interface {
-	ChangeBidStatus(bidId, status, username string) (bids.BidResponse, error)
+	ChangeBidStatus(ctx context.Context, bidId, status, username string) (bids.BidResponse, error)
 }
 
 type BidEditor interface {
-	EditBid(bidId, username, name, desc string) (bids.BidResponse, error)
+	EditBid(ctx context.Context, bidId, username, name, desc string, price *float64) (bids.BidResponse, error)
 }
 
 type BidFeedbackWriter interface {
-	LeaveFeedback(bidId, bidFeedback, username string) (bids.BidResponse, error)
+	LeaveFeedback(ctx context.Context, bidId, bidFeedback, username string, rating *int) (bids.BidResponse, error)
 }
 
 type BidRollerBack interface {
-	RollbackBid(bidId, username string, version int) (bids.BidResponse, error)
+	RollbackBid(ctx context.Context, bidId, username string, version int) (bids.BidResponse, error)
 }
 
 type BidFeedbackReader interface {
 	GetTenderReviews(tenderId, authorUsername, requesterUsername string, limit, offset int) ([]bids.BidReviewResponse, error)
 }
 
+type BidThreadReader interface {
+	ReadFeedback(bidId, username string) ([]bids.Comment, error)
+}
+
+type BidCommentPoster interface {
+	PostComment(ctx context.Context, bidId, username string, req bids.CommentRequest) (bids.Comment, error)
+}
+
+type BidCommentEditor interface {
+	EditComment(ctx context.Context, bidId, commentId, username string, req bids.CommentPatchRequest) (bids.Comment, error)
+}
+
+type BidCommentDeleter interface {
+	DeleteComment(ctx context.Context, bidId, commentId, username string) error
+}
+
 type BidDecisionHandler interface {
-	SubmitDecision(bidId, decision, username string) (bids.BidResponse, error)
+	SubmitDecision(ctx context.Context, bidId, decision, username, lotId string) (bids.BidResponse, error)
 }
 
-func NewPostBid(log *slog.Logger, bidSaver BidSaver) http.HandlerFunc {
+func NewPostBid(log *slog.Logger, bidCreator BidCreator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req bids.BidRequest
 
@@ -70,15 +91,14 @@ func NewPostBid(log *slog.Logger, bidSaver BidSaver) http.HandlerFunc {
 			return
 		}
 
-		err = validateBidRequest(req)
+		err = validate.Struct(req)
 		if err != nil {
-			log.Error(err.Error())
 			render.Status(r, 400)
-			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			render.JSON(w, r, errors.NewHttpError("invalid bid request body"))
 			return
 		}
 
-		resp, err := bidSaver.SaveBid(req)
+		resp, err := bidCreator.CreateBid(r.Context(), req)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -89,6 +109,8 @@ func NewPostBid(log *slog.Logger, bidSaver BidSaver) http.HandlerFunc {
 				render.Status(r, 403)
 			case serrors.Is(err, postgres.ErrNotFound):
 				render.Status(r, 404)
+			case serrors.Is(err, postgres.ErrConflict):
+				render.Status(r, 409)
 			default:
 				render.Status(r, 400)
 			}
@@ -133,7 +155,7 @@ func NewGetMyBids(log *slog.Logger, myBidsReader MyBidsReader) http.HandlerFunc
 			}
 		}
 
-		resp, err := myBidsReader.ReadMyBids(username, limit, offset)
+		resp, err := myBidsReader.ReadMyBids(username, actingorg.FromContext(r.Context()), limit, offset)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -195,7 +217,7 @@ func NewGetTenderBids(log *slog.Logger, tenderBidsReader TenderBidsReader) http.
 			return
 		}
 
-		resp, err := tenderBidsReader.ReadTenderBids(username, tenderId, limit, offset)
+		resp, err := tenderBidsReader.ReadTenderBids(username, actingorg.FromContext(r.Context()), tenderId, r.URL.Query().Get("lotId"), limit, offset)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -267,15 +289,17 @@ func NewPutBidStatus(log *slog.Logger, bidStatusUpdater BidStatusUpdater) http.H
 			return
 		}
 		status := r.URL.Query().Get("status")
-		if status == "" || (status != "Created" && status != "Published" && status != "Canceled") {
-			render.Status(r, 401)
+		if !biddomain.Valid(status) || biddomain.IsDecision(status) {
+			render.Status(r, 400)
 			render.JSON(w, r, errors.NewHttpError("The status is wrong"))
 			return
 		}
 
-		resp, err := bidStatusUpdater.ChangeBidStatus(bidId, status, username)
+		resp, err := bidStatusUpdater.ChangeBidStatus(r.Context(), bidId, status, username)
 		if err != nil {
 			switch {
+			case serrors.Is(err, biddomain.ErrInvalidTransition):
+				render.Status(r, 409)
 			case serrors.Is(err, postgres.ErrBadRequest):
 				render.Status(r, 400)
 			case serrors.Is(err, postgres.ErrUserNotFound):
@@ -317,13 +341,18 @@ func NewPatchBid(log *slog.Logger, bidEditor BidEditor) http.HandlerFunc {
 			return
 		}
 
-		if req.Name == "" && req.Description == "" {
+		if req.Name == "" && req.Description == "" && req.Price == nil {
 			render.Status(r, 400)
 			render.JSON(w, r, errors.NewHttpError("The request body is empty"))
 			return
 		}
+		if req.Price != nil && *req.Price <= 0 {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The price must be positive"))
+			return
+		}
 
-		resp, err := bidEditor.EditBid(bidId, username, req.Name, req.Description)
+		resp, err := bidEditor.EditBid(r.Context(), bidId, username, req.Name, req.Description, req.Price)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -334,6 +363,8 @@ func NewPatchBid(log *slog.Logger, bidEditor BidEditor) http.HandlerFunc {
 				render.Status(r, 403)
 			case serrors.Is(err, postgres.ErrNotFound):
 				render.Status(r, 404)
+			case serrors.Is(err, postgres.ErrConflict):
+				render.Status(r, 409)
 			default:
 				render.Status(r, 400)
 			}
@@ -365,9 +396,13 @@ func NewPutBidDecision(log *slog.Logger, bidDecisionHandler BidDecisionHandler)
 			return
 		}
 
-		resp, err := bidDecisionHandler.SubmitDecision(bidId, decision, username)
+		resp, err := bidDecisionHandler.SubmitDecision(r.Context(), bidId, decision, username, r.URL.Query().Get("lotId"))
 		if err != nil {
 			switch {
+			case serrors.Is(err, biddomain.ErrInvalidTransition):
+				render.Status(r, 409)
+			case serrors.Is(err, postgres.ErrConflict):
+				render.Status(r, 409)
 			case serrors.Is(err, postgres.ErrBadRequest):
 				render.Status(r, 400)
 			case serrors.Is(err, postgres.ErrUserNotFound):
@@ -407,7 +442,18 @@ func NewPutBidFeedback(log *slog.Logger, bidFeedbackWriter BidFeedbackWriter) ht
 			return
 		}
 
-		resp, err := bidFeedbackWriter.LeaveFeedback(bidId, bidFeedback, username)
+		var rating *int
+		if r.URL.Query().Get("rating") != "" {
+			value, err := strconv.Atoi(r.URL.Query().Get("rating"))
+			if err != nil || value < 1 || value > 5 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("The rating must be between 1 and 5"))
+				return
+			}
+			rating = &value
+		}
+
+		resp, err := bidFeedbackWriter.LeaveFeedback(r.Context(), bidId, bidFeedback, username, rating)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -450,7 +496,7 @@ func NewRollbackBid(log *slog.Logger, bidRollerBack BidRollerBack) http.HandlerF
 			return
 		}
 
-		resp, err := bidRollerBack.RollbackBid(bidId, username, intVersion)
+		resp, err := bidRollerBack.RollbackBid(r.Context(), bidId, username, intVersion)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -461,6 +507,8 @@ func NewRollbackBid(log *slog.Logger, bidRollerBack BidRollerBack) http.HandlerF
 				render.Status(r, 403)
 			case serrors.Is(err, postgres.ErrNotFound):
 				render.Status(r, 404)
+			case serrors.Is(err, postgres.ErrConflict):
+				render.Status(r, 409)
 			default:
 				render.Status(r, 400)
 			}
@@ -538,10 +586,140 @@ func NewReadBidFeedback(log *slog.Logger, bidFeedbackReader BidFeedbackReader) h
 	}
 }
 
-func validateBidRequest(bid bids.BidRequest) error {
-	if bid.Name == "" || bid.Description == "" || bid.TenderId == "" || (bid.AuthorType != "Organization" && bid.AuthorType != "User") || bid.AuthorId == "" {
-		fmt.Println(bid)
-		return fmt.Errorf("invalid bid request body")
+// NewGetBidThread returns the feedback thread of a bid to its author and the
+// tender's reviewers.
+func NewGetBidThread(log *slog.Logger, bidThreadReader BidThreadReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bidId, username, ok := parseCommentParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := bidThreadReader.ReadFeedback(bidId, username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPostBidComment(log *slog.Logger, bidCommentPoster BidCommentPoster) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bidId, username, ok := parseCommentParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req bids.CommentRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := bidCommentPoster.PostComment(r.Context(), bidId, username, req)
+		if err != nil {
+			log.Error("Failed to post comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPatchBidComment(log *slog.Logger, bidCommentEditor BidCommentEditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bidId, username, ok := parseCommentParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req bids.CommentPatchRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := bidCommentEditor.EditComment(r.Context(), bidId, chi.URLParam(r, "commentId"), username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteBidComment(log *slog.Logger, bidCommentDeleter BidCommentDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bidId, username, ok := parseCommentParams(w, r)
+		if !ok {
+			return
+		}
+
+		err := bidCommentDeleter.DeleteComment(r.Context(), bidId, chi.URLParam(r, "commentId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func parseCommentParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	bidId := chi.URLParam(r, "bidId")
+	if bidId == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The bid id is invalid"))
+		return "", "", false
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	return bidId, username, true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
 	}
-	return nil
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
 }
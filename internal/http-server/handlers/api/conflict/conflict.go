@@ -0,0 +1,164 @@
+package conflict
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type RulesReader interface {
+	ReadRules(organizationId, username string) ([]conflict.Rule, error)
+}
+
+type RulesUpdater interface {
+	UpdateRules(ctx context.Context, organizationId, username string, req conflict.RulesRequest) ([]conflict.Rule, error)
+}
+
+type Recuser interface {
+	Recuse(ctx context.Context, tenderId, username string, req conflict.RecusalRequest) (conflict.Recusal, error)
+}
+
+type RecusalLister interface {
+	ListRecusals(tenderId, username string) ([]conflict.Recusal, error)
+}
+
+func NewGetRules(log *slog.Logger, rulesReader RulesReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := rulesReader.ReadRules(chi.URLParam(r, "organizationId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutRules(log *slog.Logger, rulesUpdater RulesUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req conflict.RulesRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := rulesUpdater.UpdateRules(r.Context(), chi.URLParam(r, "organizationId"), username, req)
+		if err != nil {
+			log.Error("Failed to update conflict rules", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// NewPutRecusal lets a voter step back from deciding on a tender's bids.
+func NewPutRecusal(log *slog.Logger, recuser Recuser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req conflict.RecusalRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := recuser.Recuse(r.Context(), chi.URLParam(r, "tenderId"), username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetRecusals(log *slog.Logger, recusalLister RecusalLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := recusalLister.ListRecusals(chi.URLParam(r, "tenderId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func parseUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", false
+	}
+
+	return username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
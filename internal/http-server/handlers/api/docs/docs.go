@@ -0,0 +1,44 @@
+package docs
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+)
+
+// swaggerUI loads Swagger UI from a CDN and points it at the served document.
+var swaggerUI = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Tender Management API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		window.ui = SwaggerUIBundle({ url: {{.}}, dom_id: "#swagger-ui" });
+	</script>
+</body>
+</html>
+`))
+
+// NewGetSpec serves the OpenAPI document.
+func NewGetSpec(log *slog.Logger, spec []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(spec)
+	}
+}
+
+// NewGetSwaggerUI serves a page browsing the document found at specURL.
+func NewGetSwaggerUI(log *slog.Logger, specURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := swaggerUI.Execute(w, specURL)
+		if err != nil {
+			log.Error("Failed to render the API docs", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+	}
+}
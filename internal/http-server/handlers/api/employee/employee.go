@@ -0,0 +1,214 @@
+package employee
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type EmployeeLister interface {
+	ListEmployees(limit, offset int) ([]user.User, error)
+}
+
+type EmployeeGetter interface {
+	GetEmployee(username string) (user.User, error)
+}
+
+type ProfileReader interface {
+	ReadProfile(username string) (user.Profile, error)
+}
+
+type EmployeeCreator interface {
+	CreateEmployee(ctx context.Context, req user.EmployeeRequest) (user.User, error)
+}
+
+type EmployeeUpdater interface {
+	UpdateEmployee(ctx context.Context, targetUsername, username string, req user.EmployeePatchRequest) (user.User, error)
+}
+
+type EmployeeDeleter interface {
+	DeleteEmployee(ctx context.Context, targetUsername, username string) error
+}
+
+func NewGetEmployees(log *slog.Logger, employeeLister EmployeeLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := 5, 0
+		var err error
+
+		if r.URL.Query().Get("limit") != "" {
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil || limit < 0 || limit > 50 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect limit value"))
+				return
+			}
+		}
+		if r.URL.Query().Get("offset") != "" {
+			offset, err = strconv.Atoi(r.URL.Query().Get("offset"))
+			if err != nil || offset < 0 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect offset value"))
+				return
+			}
+		}
+
+		resp, err := employeeLister.ListEmployees(limit, offset)
+		if err != nil {
+			log.Error("Failed to read employees", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetEmployee(log *slog.Logger, employeeGetter EmployeeGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := employeeGetter.GetEmployee(chi.URLParam(r, "employeeUsername"))
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// NewGetMe returns the requesting user with all of their organizations and
+// roles.
+func NewGetMe(log *slog.Logger, profileReader ProfileReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := profileReader.ReadProfile(username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPostEmployee(log *slog.Logger, employeeCreator EmployeeCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req user.EmployeeRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := employeeCreator.CreateEmployee(r.Context(), req)
+		if err != nil {
+			log.Error("Failed to create employee", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPatchEmployee(log *slog.Logger, employeeUpdater EmployeeUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req user.EmployeePatchRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := employeeUpdater.UpdateEmployee(r.Context(), chi.URLParam(r, "employeeUsername"), username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteEmployee(log *slog.Logger, employeeDeleter EmployeeDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		err := employeeDeleter.DeleteEmployee(r.Context(), chi.URLParam(r, "employeeUsername"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func parseUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", false
+	}
+
+	return username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
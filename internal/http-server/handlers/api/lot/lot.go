@@ -0,0 +1,170 @@
+package lot
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type LotLister interface {
+	ListLots(tenderId, username string) ([]lot.Lot, error)
+}
+
+type LotAdder interface {
+	AddLot(ctx context.Context, tenderId, username string, req lot.LotRequest) (lot.Lot, error)
+}
+
+type LotUpdater interface {
+	UpdateLot(ctx context.Context, tenderId, lotId, username string, req lot.LotPatchRequest) (lot.Lot, error)
+}
+
+type LotRemover interface {
+	RemoveLot(ctx context.Context, tenderId, lotId, username string) error
+}
+
+func NewGetLots(log *slog.Logger, lotLister LotLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := lotLister.ListLots(tenderId, username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPostLot(log *slog.Logger, lotAdder LotAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req lot.LotRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := lotAdder.AddLot(r.Context(), tenderId, username, req)
+		if err != nil {
+			log.Error("Failed to add lot", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPatchLot(log *slog.Logger, lotUpdater LotUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req lot.LotPatchRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := lotUpdater.UpdateLot(r.Context(), tenderId, chi.URLParam(r, "lotId"), username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteLot(log *slog.Logger, lotRemover LotRemover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenderId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		err := lotRemover.RemoveLot(r.Context(), tenderId, chi.URLParam(r, "lotId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func parseParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	tenderId := chi.URLParam(r, "tenderId")
+	if tenderId == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+		return "", "", false
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	return tenderId, username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type NotificationLister interface {
+	ListNotifications(username string, unreadOnly bool, limit, offset int) ([]notification.Notification, error)
+}
+
+type NotificationMarker interface {
+	MarkRead(username, notificationId string) error
+	MarkAllRead(username string) error
+}
+
+type SettingsReader interface {
+	ReadSettings(username string) (notification.Settings, error)
+}
+
+type SettingsUpdater interface {
+	UpdateSettings(username string, settings notification.Settings) (notification.Settings, error)
+}
+
+// NewGetNotifications lists the user's inbox, only unread notifications when
+// unread=true.
+func NewGetNotifications(log *slog.Logger, notificationLister NotificationLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset := 5, 0
+		var err error
+
+		if r.URL.Query().Get("limit") != "" {
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil || limit < 0 || limit > 50 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect limit value"))
+				return
+			}
+		}
+		if r.URL.Query().Get("offset") != "" {
+			offset, err = strconv.Atoi(r.URL.Query().Get("offset"))
+			if err != nil || offset < 0 {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("Incorrect offset value"))
+				return
+			}
+		}
+
+		resp, err := notificationLister.ListNotifications(username, r.URL.Query().Get("unread") == "true", limit, offset)
+		if err != nil {
+			log.Error("Failed to read notifications", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutRead(log *slog.Logger, notificationMarker NotificationMarker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		err := notificationMarker.MarkRead(username, chi.URLParam(r, "notificationId"))
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func NewPutReadAll(log *slog.Logger, notificationMarker NotificationMarker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		err := notificationMarker.MarkAllRead(username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func NewGetSettings(log *slog.Logger, settingsReader SettingsReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := settingsReader.ReadSettings(username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutSettings(log *slog.Logger, settingsUpdater SettingsUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req notification.Settings
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&req)
+		if err != nil {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		err = validate.Struct(req)
+		if err != nil {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+			return
+		}
+
+		resp, err := settingsUpdater.UpdateSettings(username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func parseUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", false
+	}
+
+	return username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
@@ -0,0 +1,325 @@
+package organization
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/organization"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type OrganizationLister interface {
+	ListOrganizations(limit, offset int) ([]organization.Organization, error)
+}
+
+type OrganizationGetter interface {
+	GetOrganization(organizationId string) (organization.Organization, error)
+}
+
+type OrganizationCreator interface {
+	CreateOrganization(ctx context.Context, username string, req organization.OrganizationRequest) (organization.Organization, error)
+}
+
+type OrganizationUpdater interface {
+	UpdateOrganization(ctx context.Context, organizationId, username string, req organization.OrganizationPatchRequest) (organization.Organization, error)
+}
+
+type OrganizationDeleter interface {
+	DeleteOrganization(ctx context.Context, organizationId, username string) error
+}
+
+type ResponsibleLister interface {
+	ListResponsibles(organizationId, username string, limit, offset int) ([]organization.Responsible, error)
+}
+
+type ResponsibleAdder interface {
+	AddResponsible(ctx context.Context, organizationId, username, targetUsername string) (organization.Responsible, error)
+}
+
+type ResponsibleRemover interface {
+	RemoveResponsible(ctx context.Context, organizationId, username, targetUsername string) error
+}
+
+func NewGetOrganizations(log *slog.Logger, organizationLister OrganizationLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, ok := parsePagination(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := organizationLister.ListOrganizations(limit, offset)
+		if err != nil {
+			log.Error("Failed to read organizations", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetOrganization(log *slog.Logger, organizationGetter OrganizationGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, ok := parseOrganizationId(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := organizationGetter.GetOrganization(organizationId)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPostOrganization(log *slog.Logger, organizationCreator OrganizationCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req organization.OrganizationRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := organizationCreator.CreateOrganization(r.Context(), username, req)
+		if err != nil {
+			log.Error("Failed to create organization", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPatchOrganization(log *slog.Logger, organizationUpdater OrganizationUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, ok := parseOrganizationId(w, r)
+		if !ok {
+			return
+		}
+
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req organization.OrganizationPatchRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := organizationUpdater.UpdateOrganization(r.Context(), organizationId, username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteOrganization(log *slog.Logger, organizationDeleter OrganizationDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, ok := parseOrganizationId(w, r)
+		if !ok {
+			return
+		}
+
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		err := organizationDeleter.DeleteOrganization(r.Context(), organizationId, username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func NewGetResponsibles(log *slog.Logger, responsibleLister ResponsibleLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, ok := parseOrganizationId(w, r)
+		if !ok {
+			return
+		}
+
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, ok := parsePagination(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := responsibleLister.ListResponsibles(organizationId, username, limit, offset)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutResponsible(log *slog.Logger, responsibleAdder ResponsibleAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, targetUsername, ok := parseResponsibleParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := responsibleAdder.AddResponsible(r.Context(), organizationId, username, targetUsername)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteResponsible(log *slog.Logger, responsibleRemover ResponsibleRemover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, targetUsername, ok := parseResponsibleParams(w, r)
+		if !ok {
+			return
+		}
+
+		err := responsibleRemover.RemoveResponsible(r.Context(), organizationId, username, targetUsername)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func parseOrganizationId(w http.ResponseWriter, r *http.Request) (string, bool) {
+	organizationId := chi.URLParam(r, "organizationId")
+	if organizationId == "" || len(organizationId) > 100 {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The organization id is invalid"))
+		return "", false
+	}
+
+	return organizationId, true
+}
+
+func parseUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", false
+	}
+
+	return username, true
+}
+
+func parseResponsibleParams(w http.ResponseWriter, r *http.Request) (string, string, string, bool) {
+	organizationId, ok := parseOrganizationId(w, r)
+	if !ok {
+		return "", "", "", false
+	}
+
+	username, ok := parseUsername(w, r)
+	if !ok {
+		return "", "", "", false
+	}
+
+	targetUsername := r.URL.Query().Get("targetUsername")
+	if targetUsername == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The targetUsername is required"))
+		return "", "", "", false
+	}
+
+	return organizationId, username, targetUsername, true
+}
+
+func parsePagination(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	limit, offset := 5, 0
+	var err error
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit < 0 || limit > 50 {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("Incorrect limit value"))
+			return 0, 0, false
+		}
+	}
+	if r.URL.Query().Get("offset") != "" {
+		offset, err = strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("Incorrect offset value"))
+			return 0, 0, false
+		}
+	}
+
+	return limit, offset, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
@@ -0,0 +1,133 @@
+package roles
+
+import (
+	"context"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type RoleLister interface {
+	ListRoles(organizationId, username string) ([]user.Member, error)
+}
+
+type RoleGranter interface {
+	GrantRole(ctx context.Context, organizationId, username, targetUsername, role string) error
+}
+
+type RoleRevoker interface {
+	RevokeRole(ctx context.Context, organizationId, username, targetUsername, role string) error
+}
+
+func NewGetRoles(log *slog.Logger, roleLister RoleLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := roleLister.ListRoles(organizationId, username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutRole(log *slog.Logger, roleGranter RoleGranter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		targetUsername, role, ok := parseRoleParams(w, r)
+		if !ok {
+			return
+		}
+
+		err := roleGranter.GrantRole(r.Context(), organizationId, username, targetUsername, role)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func NewDeleteRole(log *slog.Logger, roleRevoker RoleRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		targetUsername, role, ok := parseRoleParams(w, r)
+		if !ok {
+			return
+		}
+
+		err := roleRevoker.RevokeRole(r.Context(), organizationId, username, targetUsername, role)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func parseParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	organizationId := chi.URLParam(r, "organizationId")
+	if organizationId == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The organization id is invalid"))
+		return "", "", false
+	}
+
+	return organizationId, username, true
+}
+
+func parseRoleParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	targetUsername := r.URL.Query().Get("targetUsername")
+	role := r.URL.Query().Get("role")
+	if targetUsername == "" || role == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The targetUsername and role are required"))
+		return "", "", false
+	}
+
+	return targetUsername, role, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
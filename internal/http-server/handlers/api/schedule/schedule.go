@@ -0,0 +1,168 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/schedule"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/storage/postgres"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type PublicationScheduler interface {
+	SchedulePublication(ctx context.Context, tenderId, username string, publishAt *time.Time) (tender.Tender, error)
+}
+
+type RecurrenceReader interface {
+	ReadRecurrence(tenderId, username string) (schedule.Recurrence, error)
+}
+
+type RecurrenceSetter interface {
+	SetRecurrence(ctx context.Context, tenderId, username string, req schedule.RecurrenceRequest) (schedule.Recurrence, error)
+}
+
+type RecurrenceDeleter interface {
+	DeleteRecurrence(ctx context.Context, tenderId, username string) error
+}
+
+// NewPutPublication schedules the tender's publication, or cancels it when
+// publishAt is null.
+func NewPutPublication(log *slog.Logger, publicationScheduler PublicationScheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req schedule.PublicationRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := publicationScheduler.SchedulePublication(r.Context(), chi.URLParam(r, "tenderId"), username, req.PublishAt)
+		if err != nil {
+			log.Error("Failed to schedule publication", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetRecurrence(log *slog.Logger, recurrenceReader RecurrenceReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := recurrenceReader.ReadRecurrence(chi.URLParam(r, "tenderId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutRecurrence(log *slog.Logger, recurrenceSetter RecurrenceSetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		var req schedule.RecurrenceRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := recurrenceSetter.SetRecurrence(r.Context(), chi.URLParam(r, "tenderId"), username, req)
+		if err != nil {
+			log.Error("Failed to set recurrence", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteRecurrence(log *slog.Logger, recurrenceDeleter RecurrenceDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := parseUsername(w, r)
+		if !ok {
+			return
+		}
+
+		err := recurrenceDeleter.DeleteRecurrence(r.Context(), chi.URLParam(r, "tenderId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func parseUsername(w http.ResponseWriter, r *http.Request) (string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", false
+	}
+
+	return username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
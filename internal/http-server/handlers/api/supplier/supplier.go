@@ -0,0 +1,61 @@
+package supplier
+
+import (
+	serrors "errors"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/supplier"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type ProfileReader interface {
+	ReadSupplierProfile(authorId, username string) (supplier.Profile, error)
+}
+
+// NewGetProfile shows the track record of a bid author, identified by the
+// organization or user id bids are placed under.
+func NewGetProfile(log *slog.Logger, profileReader ProfileReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := r.URL.Query().Get("username")
+		if username == "" {
+			render.Status(r, 401)
+			render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+			return
+		}
+
+		authorId := chi.URLParam(r, "authorId")
+		if authorId == "" {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The author id is invalid"))
+			return
+		}
+
+		resp, err := profileReader.ReadSupplierProfile(authorId, username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
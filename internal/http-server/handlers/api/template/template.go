@@ -0,0 +1,224 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	serrors "errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/template"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+type TemplateLister interface {
+	ListTemplates(organizationId, username string) ([]template.Template, error)
+}
+
+type TemplateReader interface {
+	GetTemplate(organizationId, templateId, username string) (template.Template, error)
+}
+
+type TemplateCreator interface {
+	CreateTemplate(ctx context.Context, organizationId, username string, req template.TemplateRequest) (template.Template, error)
+}
+
+type TemplateUpdater interface {
+	UpdateTemplate(ctx context.Context, organizationId, templateId, username string, req template.TemplateRequest) (template.Template, error)
+}
+
+type TemplateDeleter interface {
+	DeleteTemplate(ctx context.Context, organizationId, templateId, username string) error
+}
+
+type TemplateInstantiator interface {
+	Instantiate(ctx context.Context, organizationId, templateId, username string, overrides template.Overrides) (tender.TenderResponse, error)
+}
+
+func NewGetTemplates(log *slog.Logger, templateLister TemplateLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := templateLister.ListTemplates(organizationId, username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewGetTemplate(log *slog.Logger, templateReader TemplateReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := templateReader.GetTemplate(organizationId, chi.URLParam(r, "templateId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPostTemplate(log *slog.Logger, templateCreator TemplateCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req template.TemplateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := templateCreator.CreateTemplate(r.Context(), organizationId, username, req)
+		if err != nil {
+			log.Error("Failed to create template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutTemplate(log *slog.Logger, templateUpdater TemplateUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req template.TemplateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		resp, err := templateUpdater.UpdateTemplate(r.Context(), organizationId, chi.URLParam(r, "templateId"), username, req)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewDeleteTemplate(log *slog.Logger, templateDeleter TemplateDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		err := templateDeleter.DeleteTemplate(r.Context(), organizationId, chi.URLParam(r, "templateId"), username)
+		if err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		render.NoContent(w, r)
+	}
+}
+
+// NewPostTenderFromTemplate creates a tender from a template. The body holds
+// the overrides and may be left empty.
+func NewPostTenderFromTemplate(log *slog.Logger, templateInstantiator TemplateInstantiator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationId, username, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		var overrides template.Overrides
+		if !decodeBody(w, r, &overrides) {
+			return
+		}
+
+		resp, err := templateInstantiator.Instantiate(r.Context(), organizationId, chi.URLParam(r, "templateId"), username, overrides)
+		if err != nil {
+			log.Error("Failed to create tender from template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			renderError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// decodeBody reads and validates the JSON body. An empty body leaves req
+// as it is.
+func decodeBody(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(req)
+	if err != nil && err != io.EOF {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError(err.Error()))
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("One of the fields is invalid"))
+		return false
+	}
+
+	return true
+}
+
+func parseParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	organizationId := chi.URLParam(r, "organizationId")
+	if organizationId == "" {
+		render.Status(r, 400)
+		render.JSON(w, r, errors.NewHttpError("The organization id is invalid"))
+		return "", "", false
+	}
+
+	return organizationId, username, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	case serrors.Is(err, postgres.ErrConflict):
+		render.Status(r, 409)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
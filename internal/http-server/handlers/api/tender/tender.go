@@ -1,16 +1,21 @@
 package tender
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	serrors "errors"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/http-server/middleware/actingorg"
+	"tender_system/internal/lib/committee"
 	"tender_system/internal/lib/errors"
+	"tender_system/internal/lib/xlsx"
 	"tender_system/internal/models/tender"
-	"tender_system/internal/models/user"
 	"tender_system/internal/storage/postgres"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -19,56 +24,54 @@ import (
 
 var validate = validator.New()
 
-type TenderSaver interface {
-	SaveTender(ten tender.TenderRequest) (tender.TenderResponse, error)
-	CheckOrganizationResponsible(username, organization_id string) (bool, error)
+type TenderCreator interface {
+	CreateTender(ctx context.Context, ten tender.TenderRequest) (tender.TenderResponse, error)
 }
 
 type TenderGetter interface {
 	ReadTenders(limit, offset int, serviceType string) ([]tender.TenderResponse, error)
-	FetchUserOrganization(username string) (string, error)
 }
 
 type MyTenderGetter interface {
-	FetchUser(username string) (user.User, error)
-	ReadMyTenders(creator string, limit int, offset int) ([]tender.TenderResponse, error)
-	FetchUserOrganization(username string) (string, error)
+	ReadMyTenders(username, organizationId string, limit, offset int) ([]tender.TenderResponse, error)
 }
 
 type TenderStatusGetter interface {
-	FetchUser(username string) (user.User, error)
-	FetchUserOrganization(username string) (string, error)
 	ReadTenderStatus(tenderId, username string) (string, error)
 }
 
 type TenderStatusPutter interface {
-	FetchUser(username string) (user.User, error)
-	FetchUserOrganization(username string) (string, error)
-	UpdateTenderStatus(tenderId, status, username string) (tender.TenderResponse, error)
+	UpdateTenderStatus(ctx context.Context, tenderId, status, username, reason string) (tender.TenderResponse, error)
+}
+
+type TenderTransitionsReader interface {
+	ReadTenderTransitions(tenderId, username string) (tender.TenderTransitionsResponse, error)
+}
+
+type BudgetReportReader interface {
+	ReadBudgetReport(tenderId, username string) (tender.BudgetReport, error)
+}
+
+type CommitteeReportReader interface {
+	ReadCommitteeReport(tenderId, username string) (tender.CommitteeReport, error)
+}
+
+type TenderCloner interface {
+	CloneTender(ctx context.Context, tenderId, username, name string) (tender.TenderResponse, error)
 }
 
 type TenderPatcher interface {
-	FetchUser(username string) (user.User, error)
-	FetchUserOrganization(username string) (string, error)
-	PatchTender(tenderId, username, name, description, serviceType string) (tender.TenderResponse, error)
+	PatchTender(ctx context.Context, tenderId, username, name, description, serviceType string, deadline *time.Time) (tender.TenderResponse, error)
 }
 
 type TendetRollerBack interface {
-	FetchUser(username string) (user.User, error)
-	FetchUserOrganization(username string) (string, error)
-	RollbackTender(tenderId, username string, version int) (tender.TenderResponse, error)
+	RollbackTender(ctx context.Context, tenderId, username string, version int) (tender.TenderResponse, error)
 }
 
 func NewGetTenders(log *slog.Logger, tenderGetter TenderGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var limit, offset int
 		serviceType := r.URL.Query().Get("service_type")
-		if err := validateServiceType(serviceType); err != nil && serviceType != "" {
-			log.Error("Incorrect service type")
-			render.Status(r, 400)
-			render.JSON(w, r, errors.NewHttpError("Incorrect service type"))
-			return
-		}
 
 		var err error
 		if r.URL.Query().Get("limit") == "" {
@@ -107,7 +110,7 @@ func NewGetTenders(log *slog.Logger, tenderGetter TenderGetter) http.HandlerFunc
 	}
 }
 
-func NewPostTender(log *slog.Logger, tenderSaver TenderSaver) http.HandlerFunc {
+func NewPostTender(log *slog.Logger, tenderCreator TenderCreator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req tender.TenderRequest
 
@@ -122,8 +125,6 @@ func NewPostTender(log *slog.Logger, tenderSaver TenderSaver) http.HandlerFunc {
 			return
 		}
 
-		//TODO: Validate serviceType and other fields to be non-null
-
 		err = validate.Struct(req)
 		if err != nil {
 			render.Status(r, 400)
@@ -131,14 +132,7 @@ func NewPostTender(log *slog.Logger, tenderSaver TenderSaver) http.HandlerFunc {
 			return
 		}
 
-		err = validateServiceType(req.ServiceType)
-		if err != nil {
-			render.Status(r, 400)
-			render.JSON(w, r, errors.NewHttpError("Incorrect Service Type"))
-			return
-		}
-
-		resp, err := tenderSaver.SaveTender(req)
+		resp, err := tenderCreator.CreateTender(r.Context(), req)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -192,9 +186,16 @@ func NewGetMyTenders(log *slog.Logger, myTenderGetter MyTenderGetter) http.Handl
 			}
 		}
 
-		resp, err := myTenderGetter.ReadMyTenders(username, limit, offset)
+		resp, err := myTenderGetter.ReadMyTenders(username, actingorg.FromContext(r.Context()), limit, offset)
 		if err != nil {
-			render.Status(r, 401)
+			switch {
+			case serrors.Is(err, postgres.ErrForbidden):
+				render.Status(r, 403)
+			case serrors.Is(err, postgres.ErrUserNotFound):
+				render.Status(r, 401)
+			default:
+				render.Status(r, 500)
+			}
 			render.JSON(w, r, errors.NewHttpError(err.Error()))
 			return
 		}
@@ -236,7 +237,9 @@ func NewGetTenderStatus(log *slog.Logger, tenderStatusGetter TenderStatusGetter)
 	}
 }
 
-func NewPutTenderStatus(log *slog.Logger, tenderStatusPutter TenderStatusPutter) http.HandlerFunc {
+// NewGetBudgetReport shows reviewers how the bid prices compare with the
+// tender budget.
+func NewGetBudgetReport(log *slog.Logger, budgetReportReader BudgetReportReader) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		username := r.URL.Query().Get("username")
 		if username == "" {
@@ -252,25 +255,159 @@ func NewPutTenderStatus(log *slog.Logger, tenderStatusPutter TenderStatusPutter)
 			return
 		}
 
-		status := r.URL.Query().Get("status")
-		err := validateStatus(status)
+		resp, err := budgetReportReader.ReadBudgetReport(tenderId, username)
 		if err != nil {
+			switch {
+			case serrors.Is(err, postgres.ErrUserNotFound):
+				render.Status(r, 401)
+			case serrors.Is(err, postgres.ErrForbidden):
+				render.Status(r, 403)
+			case serrors.Is(err, postgres.ErrNotFound):
+				render.Status(r, 404)
+			default:
+				log.Error("Failed to read budget report", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Status(r, 500)
+			}
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// NewGetCommitteeReport downloads the bids, feedback and votes of a tender
+// as an XLSX workbook for the procurement committee.
+func NewGetCommitteeReport(log *slog.Logger, committeeReportReader CommitteeReportReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := r.URL.Query().Get("username")
+		if username == "" {
+			render.Status(r, 401)
+			render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+			return
+		}
+
+		tenderId := chi.URLParam(r, "tenderId")
+		if tenderId == "" {
 			render.Status(r, 400)
-			render.JSON(w, r, errors.NewHttpError("Invalid status"))
+			render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+			return
+		}
+
+		resp, err := committeeReportReader.ReadCommitteeReport(tenderId, username)
+		if err != nil {
+			switch {
+			case serrors.Is(err, postgres.ErrUserNotFound):
+				render.Status(r, 401)
+			case serrors.Is(err, postgres.ErrForbidden):
+				render.Status(r, 403)
+			case serrors.Is(err, postgres.ErrNotFound):
+				render.Status(r, 404)
+			default:
+				log.Error("Failed to read committee report", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Status(r, 500)
+			}
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
 			return
 		}
 
-		_, err = tenderStatusPutter.FetchUser(username)
+		var buf bytes.Buffer
+		err = committee.Render(&buf, resp)
 		if err != nil {
-			log.Error("Incorrect user information", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			log.Error("Failed to render committee report", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Status(r, 500)
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", xlsx.ContentType)
+		w.Header().Set("Content-Disposition", "attachment; filename=\"report-"+resp.Tender.Id+".xlsx\"")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		_, err = buf.WriteTo(w)
+		if err != nil {
+			log.Error("Failed to send committee report", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+	}
+}
+
+// NewPostCloneTender copies a tender into a new one in the Created status,
+// optionally under a new name.
+func NewPostCloneTender(log *slog.Logger, tenderCloner TenderCloner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := r.URL.Query().Get("username")
+		if username == "" {
 			render.Status(r, 401)
-			render.JSON(w, r, errors.NewHttpError("Incorrect user information"))
+			render.JSON(w, r, errors.NewHttpError("The Username is empty"))
 			return
 		}
 
-		resp, err := tenderStatusPutter.UpdateTenderStatus(tenderId, status, username)
+		tenderId := chi.URLParam(r, "tenderId")
+		if tenderId == "" {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+			return
+		}
+
+		name := r.URL.Query().Get("name")
+		if len(name) > 100 {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The name is too long"))
+			return
+		}
+
+		resp, err := tenderCloner.CloneTender(r.Context(), tenderId, username, name)
 		if err != nil {
 			switch {
+			case serrors.Is(err, postgres.ErrBadRequest):
+				render.Status(r, 400)
+			case serrors.Is(err, postgres.ErrUserNotFound):
+				render.Status(r, 401)
+			case serrors.Is(err, postgres.ErrForbidden):
+				render.Status(r, 403)
+			case serrors.Is(err, postgres.ErrNotFound):
+				render.Status(r, 404)
+			default:
+				log.Error("Failed to clone tender", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Status(r, 500)
+			}
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+func NewPutTenderStatus(log *slog.Logger, tenderStatusPutter TenderStatusPutter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := r.URL.Query().Get("username")
+		if username == "" {
+			render.Status(r, 401)
+			render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+			return
+		}
+
+		tenderId := chi.URLParam(r, "tenderId")
+		if tenderId == "" {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+			return
+		}
+
+		status := r.URL.Query().Get("status")
+
+		reason := r.URL.Query().Get("reason")
+		if len(reason) > 500 {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The reason is too long"))
+			return
+		}
+
+		resp, err := tenderStatusPutter.UpdateTenderStatus(r.Context(), tenderId, status, username, reason)
+		if err != nil {
+			switch {
+			case serrors.Is(err, tenderdomain.ErrInvalidTransition):
+				render.Status(r, 409)
 			case serrors.Is(err, postgres.ErrBadRequest):
 				render.Status(r, 400)
 			case serrors.Is(err, postgres.ErrUserNotFound):
@@ -316,23 +453,13 @@ func NewPatchTender(log *slog.Logger, tenderPatcher TenderPatcher) http.HandlerF
 			render.JSON(w, r, errors.NewHttpError(err.Error()))
 			return
 		}
-		if patchRequest.Name == "" && patchRequest.Description == "" && patchRequest.ServiceType == "" {
+		if patchRequest.Name == "" && patchRequest.Description == "" && patchRequest.ServiceType == "" && patchRequest.Deadline == nil {
 			render.Status(r, 400)
 			render.JSON(w, r, errors.NewHttpError("The request body is empty"))
 			return
 		}
 
-		if patchRequest.ServiceType != "" {
-			err := validateServiceType(patchRequest.ServiceType)
-			if err != nil {
-				log.Error("Value error", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
-				render.Status(r, 400)
-				render.JSON(w, r, errors.NewHttpError(err.Error()))
-				return
-			}
-		}
-
-		resp, err := tenderPatcher.PatchTender(tenderId, username, patchRequest.Name, patchRequest.Description, patchRequest.ServiceType)
+		resp, err := tenderPatcher.PatchTender(r.Context(), tenderId, username, patchRequest.Name, patchRequest.Description, patchRequest.ServiceType, patchRequest.Deadline)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -378,7 +505,7 @@ func NewRollbackTender(log *slog.Logger, tenderRollerBack TendetRollerBack) http
 			return
 		}
 
-		resp, err := tenderRollerBack.RollbackTender(tenderId, username, intVersion)
+		resp, err := tenderRollerBack.RollbackTender(r.Context(), tenderId, username, intVersion)
 		if err != nil {
 			switch {
 			case serrors.Is(err, postgres.ErrBadRequest):
@@ -400,17 +527,40 @@ func NewRollbackTender(log *slog.Logger, tenderRollerBack TendetRollerBack) http
 	}
 }
 
-func validateStatus(status string) error {
-	if status != "Created" && status != "Published" && status != "Closed" {
-		return fmt.Errorf("invalid status parameter")
-	}
+func NewGetTenderTransitions(log *slog.Logger, tenderTransitionsReader TenderTransitionsReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := r.URL.Query().Get("username")
+		if username == "" {
+			render.Status(r, 401)
+			render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+			return
+		}
 
-	return nil
-}
+		tenderId := chi.URLParam(r, "tenderId")
+		if tenderId == "" {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The tender id is invalid"))
+			return
+		}
 
-func validateServiceType(stype string) error {
-	if stype != "Delivery" && stype != "Manufacture" && stype != "Construction" {
-		return fmt.Errorf("invalid serviceType parameter")
+		resp, err := tenderTransitionsReader.ReadTenderTransitions(tenderId, username)
+		if err != nil {
+			switch {
+			case serrors.Is(err, postgres.ErrBadRequest):
+				render.Status(r, 400)
+			case serrors.Is(err, postgres.ErrUserNotFound):
+				render.Status(r, 401)
+			case serrors.Is(err, postgres.ErrForbidden):
+				render.Status(r, 403)
+			case serrors.Is(err, postgres.ErrNotFound):
+				render.Status(r, 404)
+			default:
+				render.Status(r, 400)
+			}
+			render.JSON(w, r, errors.NewHttpError(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, resp)
 	}
-	return nil
 }
@@ -0,0 +1,187 @@
+package transfer
+
+import (
+	"context"
+	serrors "errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_system/internal/http-server/middleware/actingorg"
+	"tender_system/internal/lib/errors"
+	transferlib "tender_system/internal/lib/transfer"
+	"tender_system/internal/models/transfer"
+	"tender_system/internal/storage/postgres"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// MaxImportSize bounds the body of an import request.
+const MaxImportSize = 64 << 20
+
+type TenderExporter interface {
+	ExportTenders(username, organizationId string, f transfer.TenderFilter, format transferlib.Format, w io.Writer) error
+}
+
+type BidExporter interface {
+	ExportBids(username, organizationId string, f transfer.BidFilter, format transferlib.Format, w io.Writer) error
+}
+
+type TenderImporter interface {
+	ImportTenders(ctx context.Context, username string, format transferlib.Format, r io.Reader) (transfer.Report, error)
+}
+
+type BidImporter interface {
+	ImportBids(ctx context.Context, username string, format transferlib.Format, r io.Reader) (transfer.Report, error)
+}
+
+// NewGetTenderExport streams the tenders visible to the user, filtered by
+// the service_type and status query parameters.
+func NewGetTenderExport(log *slog.Logger, tenderExporter TenderExporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, format, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		f := transfer.TenderFilter{
+			ServiceType: query.Get("service_type"),
+			Status:      query.Get("status"),
+		}
+
+		export(log, w, r, "tenders", format, func(w io.Writer) error {
+			return tenderExporter.ExportTenders(username, actingorg.FromContext(r.Context()), f, format, w)
+		})
+	}
+}
+
+// NewGetBidExport streams the bids visible to the user, filtered by the
+// tenderId and status query parameters.
+func NewGetBidExport(log *slog.Logger, bidExporter BidExporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, format, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		f := transfer.BidFilter{
+			TenderId: query.Get("tenderId"),
+			Status:   query.Get("status"),
+		}
+
+		export(log, w, r, "bids", format, func(w io.Writer) error {
+			return bidExporter.ExportBids(username, actingorg.FromContext(r.Context()), f, format, w)
+		})
+	}
+}
+
+// NewPostTenderImport creates tenders from a CSV or JSON Lines body. When a
+// row is invalid nothing is imported and the report lists every invalid row.
+func NewPostTenderImport(log *slog.Logger, tenderImporter TenderImporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, format, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := tenderImporter.ImportTenders(r.Context(), username, format, http.MaxBytesReader(w, r.Body, MaxImportSize))
+		renderReport(log, w, r, resp, err)
+	}
+}
+
+// NewPostBidImport creates bids from a CSV or JSON Lines body. When a row is
+// invalid nothing is imported and the report lists every invalid row.
+func NewPostBidImport(log *slog.Logger, bidImporter BidImporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, format, ok := parseParams(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := bidImporter.ImportBids(r.Context(), username, format, http.MaxBytesReader(w, r.Body, MaxImportSize))
+		renderReport(log, w, r, resp, err)
+	}
+}
+
+// export streams the file written by write. Errors are only reported as JSON
+// while nothing was sent yet; afterwards the response is cut short.
+func export(log *slog.Logger, w http.ResponseWriter, r *http.Request, name string, format transferlib.Format, write func(io.Writer) error) {
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	ww.Header().Set("Content-Type", format.ContentType())
+	ww.Header().Set("Content-Disposition", "attachment; filename=\""+name+"."+string(format)+"\"")
+
+	err := write(ww)
+	if err == nil {
+		return
+	}
+	log.Error("Failed to export "+name, slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+	if ww.BytesWritten() > 0 {
+		return
+	}
+
+	ww.Header().Del("Content-Disposition")
+	renderError(ww, r, err)
+}
+
+func renderReport(log *slog.Logger, w http.ResponseWriter, r *http.Request, resp transfer.Report, err error) {
+	if err != nil && len(resp.Errors) > 0 {
+		render.Status(r, 400)
+		render.JSON(w, r, resp)
+		return
+	}
+
+	var tooLarge *http.MaxBytesError
+	if serrors.As(err, &tooLarge) {
+		render.Status(r, 413)
+		render.JSON(w, r, errors.NewHttpError("The file is too large"))
+		return
+	}
+
+	if err != nil {
+		log.Error("Failed to import", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func parseParams(w http.ResponseWriter, r *http.Request) (string, transferlib.Format, bool) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		render.Status(r, 401)
+		render.JSON(w, r, errors.NewHttpError("The Username is empty"))
+		return "", "", false
+	}
+
+	format := transferlib.CSV
+	if r.URL.Query().Get("format") != "" {
+		var err error
+		format, err = transferlib.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			render.Status(r, 400)
+			render.JSON(w, r, errors.NewHttpError("The format must be csv or jsonl"))
+			return "", "", false
+		}
+	}
+
+	return username, format, true
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case serrors.Is(err, postgres.ErrBadRequest):
+		render.Status(r, 400)
+	case serrors.Is(err, postgres.ErrUserNotFound):
+		render.Status(r, 401)
+	case serrors.Is(err, postgres.ErrForbidden):
+		render.Status(r, 403)
+	case serrors.Is(err, postgres.ErrNotFound):
+		render.Status(r, 404)
+	default:
+		render.Status(r, 500)
+	}
+	render.JSON(w, r, errors.NewHttpError(err.Error()))
+}
@@ -0,0 +1,50 @@
+// Package actingorg lets a client that belongs to several organizations pick
+// the one it acts for, either with the X-Organization-Id header or with the
+// organizationId query parameter. The header wins when both are set. An id
+// that is not a UUID is answered with a 400.
+package actingorg
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"tender_system/internal/lib/errors"
+
+	"github.com/go-chi/render"
+)
+
+const Header = "X-Organization-Id"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type ctxKey struct{}
+
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			organizationId := r.Header.Get(Header)
+			if organizationId == "" {
+				organizationId = r.URL.Query().Get("organizationId")
+			}
+
+			if organizationId != "" && !uuidPattern.MatchString(organizationId) {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("The organization id is invalid"))
+				return
+			}
+
+			if organizationId != "" {
+				r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, organizationId))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromContext returns the selected organization or "" when the client did
+// not pick one.
+func FromContext(ctx context.Context) string {
+	organizationId, _ := ctx.Value(ctxKey{}).(string)
+	return organizationId
+}
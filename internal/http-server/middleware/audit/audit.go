@@ -0,0 +1,25 @@
+// Package audit tags every request with its origin, the client address and
+// request id, so that the services record them in the audit entries of the
+// changes the request makes.
+package audit
+
+import (
+	"net/http"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/http-server/middleware/clientip"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// New must run after the request id and client address middlewares.
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := auditdomain.WithOrigin(r.Context(), auditdomain.Origin{
+				IP:        clientip.FromContext(r.Context()),
+				RequestId: middleware.GetReqID(r.Context()),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package clientip works out the address of the client. The peer address is
+// used unless the peer is one of the configured trusted proxies, in which
+// case X-Forwarded-For is walked from the right past the trusted hops, so
+// clients cannot pick their own address by sending the header.
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// New stores the client address of the request, trusting X-Forwarded-For
+// only as far as the trusted proxies forwarded it.
+func New(trusted []netip.Prefix) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolve(r, trusted)
+			if ip != "" {
+				r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, ip))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromContext returns the client address or "" when the peer address could
+// not be read.
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+	return ip
+}
+
+// ParseTrusted reads a comma separated list of addresses and CIDR ranges.
+func ParseTrusted(s string) ([]netip.Prefix, error) {
+	var result []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if strings.Contains(part, "/") {
+			prefix, err := netip.ParsePrefix(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", part, err)
+			}
+			result = append(result, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", part, err)
+		}
+		result = append(result, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return result, nil
+}
+
+func resolve(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	peer = peer.Unmap()
+	if !isTrusted(peer, trusted) {
+		return peer.String()
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		hop = hop.Unmap()
+		if !isTrusted(hop, trusted) {
+			return hop.String()
+		}
+		peer = hop
+	}
+
+	return peer.String()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+// Package idempotency makes POST and PUT requests safe to retry. A client
+// sends an Idempotency-Key header; the first request with a key runs and its
+// response is kept for a while, later requests with the same key and the same
+// method, URL and body get that response again instead of running twice.
+// Keys belong to the user making the request, or to the client address when
+// the request names no user, and to the method and path: the same key sent by
+// someone else or to another route is a new request.
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	serrors "errors"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"tender_system/internal/http-server/middleware/clientip"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/idempotency"
+	"tender_system/internal/models/user"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed from an earlier request.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// DefaultTTL is how long a key and its response are kept.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultLease is how long a request in progress holds its key. A retry
+// after that takes the key over, the first request being presumed lost with
+// the replica that ran it.
+const DefaultLease = 5 * time.Minute
+
+const (
+	maxKey = 255
+	// maxBuffered is how much of a request body is kept in memory. Larger
+	// bodies, such as uploads and imports, are spooled to a temporary file.
+	maxBuffered = 1 << 20
+	// maxBody caps the request body of idempotent requests at what the
+	// largest route accepts, a bulk import, with room for multipart framing.
+	maxBody = 65 << 20
+	// maxResponse caps the responses kept for replay.
+	maxResponse = 1 << 20
+)
+
+var errTooLarge = serrors.New("body too large")
+
+// Resolver resolves the user a request is made as.
+type Resolver interface {
+	FetchUser(username string) (user.User, error)
+}
+
+type Store interface {
+	// ReserveIdempotencyKey claims the key for the request, in progress
+	// until lockedUntil, unless it holds an unexpired response or a
+	// reservation whose lease still runs, in which case it returns the
+	// existing record.
+	ReserveIdempotencyKey(key, fingerprint string, now, lockedUntil, expiresAt time.Time) (idempotency.Record, bool, error)
+	// SaveIdempotentResponse and ReleaseIdempotencyKey end the reservation.
+	// They do nothing once a retry took the key over.
+	SaveIdempotentResponse(rec idempotency.Record, status int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(rec idempotency.Record) error
+}
+
+// New must run after the client address middleware.
+func New(log *slog.Logger, store Store, resolver Resolver, ttl, lease time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxKey {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError("The Idempotency-Key is too long"))
+				return
+			}
+
+			h := sha256.New()
+			io.WriteString(h, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+			if r.Body != nil {
+				body, err := spool(r.Body, h)
+				if serrors.Is(err, errTooLarge) {
+					render.Status(r, 413)
+					render.JSON(w, r, errors.NewHttpError("The body is too large for an idempotent request"))
+					return
+				}
+				if err != nil {
+					render.Status(r, 400)
+					render.JSON(w, r, errors.NewHttpError(err.Error()))
+					return
+				}
+				defer body.Close()
+				r.Body = body
+			}
+			fp := hex.EncodeToString(h.Sum(nil))
+			key = scopedKey(resolver, r, key)
+
+			now := time.Now().UTC()
+			rec, reserved, err := store.ReserveIdempotencyKey(key, fp, now, now.Add(lease), now.Add(ttl))
+			if err != nil {
+				log.Error("Failed to reserve idempotency key", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Status(r, 500)
+				render.JSON(w, r, errors.NewHttpError("Failed to check the Idempotency-Key"))
+				return
+			}
+
+			if !reserved {
+				replay(w, r, rec, fp)
+				return
+			}
+
+			var respBody bytes.Buffer
+			tee := &limitedWriter{buf: &respBody, left: maxResponse}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(tee)
+
+			defer func() {
+				// Panics, throttled requests, server errors and responses too
+				// large to keep free the key so that the client can retry.
+				p := recover()
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				if p != nil || status == http.StatusTooManyRequests || status >= 500 || tee.overflow {
+					err := store.ReleaseIdempotencyKey(rec)
+					if err != nil {
+						log.Error("Failed to release idempotency key", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+					}
+					if p != nil {
+						panic(p)
+					}
+					return
+				}
+
+				err := store.SaveIdempotentResponse(rec, status, ww.Header().Get("Content-Type"), respBody.Bytes())
+				if err != nil {
+					log.Error("Failed to save idempotent response", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, rec idempotency.Record, fp string) {
+	if rec.Fingerprint != fp {
+		render.Status(r, 409)
+		render.JSON(w, r, errors.NewHttpError("The Idempotency-Key was already used for a different request"))
+		return
+	}
+	if rec.Status == 0 {
+		render.Status(r, 409)
+		render.JSON(w, r, errors.NewHttpError("A request with this Idempotency-Key is still in progress"))
+		return
+	}
+
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	w.Write(rec.Body)
+}
+
+// scopedKey is the key as stored: the client's key bound to the user, or the
+// client address, and to the method and path of the request.
+func scopedKey(resolver Resolver, r *http.Request, key string) string {
+	scope := "ip:" + clientip.FromContext(r.Context())
+	if username := r.URL.Query().Get("username"); username != "" {
+		if usr, err := resolver.FetchUser(username); err == nil {
+			scope = "user:" + usr.Id
+		}
+	}
+
+	sum := sha256.Sum256([]byte(scope + "\n" + r.Method + " " + r.URL.Path + "\n" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// spool reads the body into h, the fingerprint of what the request asks for,
+// and returns a copy to hand on. Bodies over maxBuffered are kept in a
+// temporary file removed when the copy is closed.
+func spool(body io.Reader, h hash.Hash) (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.TeeReader(io.LimitReader(body, maxBuffered+1), h))
+	if err != nil {
+		return nil, err
+	}
+	if n <= maxBuffered {
+		return io.NopCloser(&buf), nil
+	}
+
+	f, err := os.CreateTemp("", "idempotent-body-*")
+	if err != nil {
+		return nil, err
+	}
+	spooled := &tempFile{f}
+
+	_, err = buf.WriteTo(f)
+	if err == nil {
+		var rest int64
+		rest, err = io.Copy(f, io.TeeReader(io.LimitReader(body, maxBody-n+1), h))
+		if err == nil && n+rest > maxBody {
+			err = errTooLarge
+		}
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		spooled.Close()
+		return nil, err
+	}
+
+	return spooled, nil
+}
+
+// tempFile is a temporary file removed once closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
+}
+
+type limitedWriter struct {
+	buf      *bytes.Buffer
+	left     int
+	overflow bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.left {
+		w.overflow = true
+	}
+	n := min(len(p), w.left)
+	w.buf.Write(p[:n])
+	w.left -= n
+	return len(p), nil
+}
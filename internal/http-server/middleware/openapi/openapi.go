@@ -0,0 +1,97 @@
+// Package openapi rejects requests that violate the API document with a 400
+// naming the offending field, before they reach the handlers. Routes the
+// document does not describe pass unchecked.
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/lib/openapi"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// maxBody caps the request and response bodies that are checked.
+const maxBody = 1 << 20
+
+type Options struct {
+	// Prefix is the path the document's server URL is mounted at.
+	Prefix string
+	// Unchecked names required query parameters whose absence the handlers
+	// answer themselves, such as username with a 401.
+	Unchecked []string
+	// OnResponseViolation turns response checking on and is called for every
+	// response that violates the document. It is meant for tests.
+	OnResponseViolation func(r *http.Request, err error)
+}
+
+func New(log *slog.Logger, doc *openapi.Document, opts Options) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, opts.Prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			op, params, ok := doc.Find(r.Method, strings.TrimPrefix(r.URL.Path, opts.Prefix))
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err := op.ValidateParameters(params, r.URL.Query(), opts.Unchecked...)
+			if err != nil {
+				render.Status(r, 400)
+				render.JSON(w, r, errors.NewHttpError(err.Error()))
+				return
+			}
+
+			if op.Body != nil && r.Body != nil {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+				if err != nil || len(body) > maxBody {
+					render.Status(r, 400)
+					render.JSON(w, r, errors.NewHttpError("The request body could not be read"))
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+
+				err = op.ValidateBody(body)
+				if err != nil {
+					render.Status(r, 400)
+					render.JSON(w, r, errors.NewHttpError(err.Error()))
+					return
+				}
+			}
+
+			if opts.OnResponseViolation == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var respBody bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&respBody)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if !strings.HasPrefix(ww.Header().Get("Content-Type"), "application/json") {
+				return
+			}
+
+			err = op.ValidateResponse(status, respBody.Bytes())
+			if err != nil {
+				log.Error("Response violates the API document", slog.String("operation", r.Method+" "+op.Path), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				opts.OnResponseViolation(r, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"sync"
+	"tender_system/internal/domain/ratelimit"
+	"time"
+)
+
+// sweepEvery is how often the memory store drops buckets that refilled.
+const sweepEvery = time.Minute
+
+// maxBuckets caps the memory store. Once it is reached a new key first
+// sweeps the refilled buckets and, failing that, evicts the bucket idle the
+// longest.
+const maxBuckets = 100_000
+
+// Memory keeps the buckets in the process. Each replica limits on its own,
+// so it suits single replica deployments.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	ratelimit.Bucket
+	limit ratelimit.Limit
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]memoryBucket)}
+}
+
+func (m *Memory) Take(charges []ratelimit.Charge, now time.Time) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) >= sweepEvery {
+		m.sweep(now)
+	}
+
+	buckets := make([]ratelimit.Bucket, len(charges))
+	limits := make([]ratelimit.Limit, len(charges))
+	for i, c := range charges {
+		b, ok := m.buckets[c.Key]
+		if !ok {
+			if len(m.buckets) >= maxBuckets {
+				m.sweep(now)
+			}
+			if len(m.buckets) >= maxBuckets {
+				m.evictIdlest()
+			}
+			b.Bucket = ratelimit.Full(c.Limit, now)
+		}
+		buckets[i], limits[i] = b.Bucket, c.Limit
+	}
+
+	next, allowed, wait := ratelimit.TakeAll(buckets, limits, now)
+	for i, c := range charges {
+		m.buckets[c.Key] = memoryBucket{Bucket: next[i], limit: c.Limit}
+	}
+
+	return allowed, wait, nil
+}
+
+// sweep forgets the buckets that would be full by now, which is what a
+// missing bucket stands for anyway.
+func (m *Memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		refill := time.Duration((float64(b.limit.Burst) - b.Tokens) / b.limit.Rate * float64(time.Second))
+		if now.Sub(b.UpdatedAt) >= refill {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
+
+func (m *Memory) evictIdlest() {
+	var (
+		idlest string
+		since  time.Time
+	)
+	for key, b := range m.buckets {
+		if idlest == "" || b.UpdatedAt.Before(since) {
+			idlest, since = key, b.UpdatedAt
+		}
+	}
+	delete(m.buckets, idlest)
+}
@@ -0,0 +1,212 @@
+// Package ratelimit throttles clients with token buckets kept per user,
+// organization and IP address. Every route group gets its own buckets, so a
+// client flooding bids does not lose access to tenders.
+//
+// Buckets are keyed on what the request resolves to rather than on what the
+// client sends: the id of an existing user, the organizations that user
+// belongs to and the address clientip worked out. Naming another user or
+// organization therefore never yields a fresh bucket, and requests that
+// resolve to no user are still held to the IP limit. The user is named by
+// the username query parameter or, for the routes creating tenders and bids,
+// by the creator or author in the JSON body.
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"tender_system/internal/domain/ratelimit"
+	"tender_system/internal/http-server/middleware/actingorg"
+	"tender_system/internal/http-server/middleware/clientip"
+	"tender_system/internal/lib/errors"
+	"tender_system/internal/models/user"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// maxPeek caps how much of a JSON body is read to find who the request is
+// made by. Creating a tender or a bid takes far less.
+const maxPeek = 64 << 10
+
+type Store interface {
+	// Take takes a token from the bucket of every charge, or from none and
+	// reports how long until each has one.
+	Take(charges []ratelimit.Charge, now time.Time) (bool, time.Duration, error)
+}
+
+// Resolver looks up who a request is made by.
+type Resolver interface {
+	FetchUser(username string) (user.User, error)
+	EmployeeExists(userId string) (bool, error)
+	OrganizationExists(organizationId string) (bool, error)
+	ReadUserOrganizations(userId string) ([]string, error)
+}
+
+// Policy is the limits of a route group. Zero limits are not enforced.
+type Policy struct {
+	User         ratelimit.Limit
+	Organization ratelimit.Limit
+	IP           ratelimit.Limit
+}
+
+type bucket struct {
+	kind  string
+	id    string
+	limit ratelimit.Limit
+}
+
+// New limits the requests of the route group. A request is charged to the
+// user, to the organization it acts for, or without one to every
+// organization of the user, and to the client address. A token is taken
+// from all of these buckets or, when one is empty, from none. Requests are
+// let through when the store fails, since throttling is not worth an outage.
+func New(log *slog.Logger, resolver Resolver, store Store, group string, policy Policy) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buckets := []bucket{{"ip", clientip.FromContext(r.Context()), policy.IP}}
+			if policy.User.Enabled() || policy.Organization.Enabled() {
+				resolved, err := resolve(resolver, r, policy)
+				if err != nil {
+					log.Error("Failed to resolve rate limit keys", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				}
+				buckets = append(buckets, resolved...)
+			}
+
+			charges := make([]ratelimit.Charge, 0, len(buckets))
+			for _, b := range buckets {
+				if b.id == "" || !b.limit.Enabled() {
+					continue
+				}
+				charges = append(charges, ratelimit.Charge{Key: group + ":" + b.kind + ":" + b.id, Limit: b.limit})
+			}
+			if len(charges) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ok, wait, err := store.Take(charges, time.Now().UTC())
+			if err != nil {
+				log.Error("Failed to check rate limit", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+
+			if err == nil && !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				render.Status(r, 429)
+				render.JSON(w, r, errors.NewHttpError("Too many requests"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// subject is who a request names in its JSON body: the creator of a tender
+// or the author of a bid.
+type subject struct {
+	CreatorUsername string `json:"creatorUsername"`
+	OrganizationId  string `json:"organizationId"`
+	AuthorType      string `json:"authorType"`
+	AuthorId        string `json:"authorId"`
+}
+
+// resolve returns the user and organization buckets of the request. Unknown
+// users and organizations get none.
+func resolve(resolver Resolver, r *http.Request, policy Policy) ([]bucket, error) {
+	acting := actingorg.FromContext(r.Context())
+
+	var userId string
+	if username := r.URL.Query().Get("username"); username != "" {
+		usr, err := resolver.FetchUser(username)
+		if err != nil {
+			return nil, nil
+		}
+		userId = usr.Id
+	} else {
+		body, err := peek(r)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case body.CreatorUsername != "":
+			usr, err := resolver.FetchUser(body.CreatorUsername)
+			if err != nil {
+				return nil, nil
+			}
+			userId = usr.Id
+			if body.OrganizationId != "" {
+				acting = body.OrganizationId
+			}
+		case body.AuthorType == "User" && body.AuthorId != "":
+			ok, err := resolver.EmployeeExists(body.AuthorId)
+			if err != nil || !ok {
+				return nil, err
+			}
+			userId = body.AuthorId
+		case body.AuthorType == "Organization" && body.AuthorId != "":
+			// A bid on behalf of an organization is charged to it alone.
+			ok, err := resolver.OrganizationExists(body.AuthorId)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return []bucket{{"org", body.AuthorId, policy.Organization}}, nil
+		default:
+			return nil, nil
+		}
+	}
+
+	result := []bucket{{"user", userId, policy.User}}
+	if !policy.Organization.Enabled() {
+		return result, nil
+	}
+
+	organizations, err := resolver.ReadUserOrganizations(userId)
+	if err != nil {
+		return result, err
+	}
+	if acting != "" {
+		if !slices.Contains(organizations, acting) {
+			return result, nil
+		}
+		organizations = []string{acting}
+	}
+	for _, organizationId := range organizations {
+		result = append(result, bucket{"org", organizationId, policy.Organization})
+	}
+
+	return result, nil
+}
+
+// peek decodes who a JSON body names and puts the body back for the handler.
+// Other bodies and bodies too large to be creating a tender or a bid name no
+// one.
+func peek(r *http.Request) (subject, error) {
+	var body subject
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return body, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxPeek+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+	if err != nil {
+		return body, err
+	}
+	if len(data) > maxPeek {
+		return body, nil
+	}
+
+	// A body that does not decode is left for the handler to reject.
+	json.Unmarshal(data, &body)
+	return body, nil
+}
@@ -0,0 +1,95 @@
+package router_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// bidCases cover the bid routes beyond submitting and deciding bids: the
+// feedback threads, attachments and bulk transfers.
+var bidCases = []contractCase{
+	{name: "feedback thread", method: "GET", path: "/bids/" + submittedBid + "/feedback?username=" + alice, status: 200},
+	{name: "feedback thread as the bid author", method: "GET", path: "/bids/" + submittedBid + "/feedback?username=" + bob, status: 200},
+	{name: "feedback thread as an unknown user", method: "GET", path: "/bids/" + submittedBid + "/feedback?username=" + nobody, status: 401},
+	{name: "feedback thread as an outsider", method: "GET", path: "/bids/" + submittedBid + "/feedback?username=" + carol, status: 403},
+	{name: "feedback thread of a missing bid", method: "GET", path: "/bids/" + missing + "/feedback?username=" + alice, status: 404},
+
+	{name: "start feedback thread", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + alice, body: map[string]any{"description": "Good price", "rating": 5}, status: 200},
+	{name: "reply to feedback", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + bob, body: map[string]any{"description": "Yes, we can", "parentId": aliceComment}, status: 200},
+	{name: "reply to feedback with a rating", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + bob, body: map[string]any{"description": "Yes, we can", "parentId": aliceComment, "rating": 5}, status: 400},
+	{name: "reply to feedback by a malformed id", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + bob, body: map[string]any{"description": "Yes, we can", "parentId": "first"}, status: 400},
+	{name: "post feedback as an unknown user", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + nobody, body: map[string]any{"description": "Good price"}, status: 401},
+	{name: "start feedback thread as the bid author", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + bob, body: map[string]any{"description": "Please review"}, status: 403},
+	{name: "post feedback on a missing bid", method: "POST", path: "/bids/" + missing + "/feedback?username=" + alice, body: map[string]any{"description": "Good price"}, status: 404},
+	{name: "reply to deleted feedback", method: "POST", path: "/bids/" + submittedBid + "/feedback?username=" + bob, body: map[string]any{"description": "Yes, we can", "parentId": aliceComment}, status: 409, setup: deleteAliceComment},
+
+	{name: "edit comment", method: "PATCH", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + alice, body: map[string]any{"description": "Can you deliver on Tuesdays?"}, status: 200},
+	{name: "edit comment to nothing", method: "PATCH", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + alice, body: map[string]any{"description": ""}, status: 400},
+	{name: "edit comment as an unknown user", method: "PATCH", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + nobody, body: map[string]any{"description": "Edited"}, status: 401},
+	{name: "edit comment of someone else", method: "PATCH", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + bob, body: map[string]any{"description": "Edited"}, status: 403},
+	{name: "edit a missing comment", method: "PATCH", path: "/bids/" + submittedBid + "/feedback/" + missingComment + "?username=" + alice, body: map[string]any{"description": "Edited"}, status: 404},
+	{name: "edit comment too late", method: "PATCH", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + alice, body: map[string]any{"description": "Edited"}, status: 409, setup: ageAliceComment},
+
+	{name: "delete comment", method: "DELETE", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + alice, status: 204},
+	{name: "delete comment as an unknown user", method: "DELETE", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + nobody, status: 401},
+	{name: "delete comment of someone else", method: "DELETE", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + bob, status: 403},
+	{name: "delete a deleted comment", method: "DELETE", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + alice, status: 404, setup: deleteAliceComment},
+	{name: "delete comment too late", method: "DELETE", path: "/bids/" + submittedBid + "/feedback/" + aliceComment + "?username=" + alice, status: 409, setup: ageAliceComment},
+
+	{name: "bid attachments", method: "GET", path: "/bids/" + submittedBid + "/attachments?username=" + bob, status: 200},
+	{name: "bid attachments as a reviewer", method: "GET", path: "/bids/" + submittedBid + "/attachments?username=" + alice, status: 200},
+	{name: "bid attachments as an unknown user", method: "GET", path: "/bids/" + submittedBid + "/attachments?username=" + nobody, status: 401},
+	{name: "bid attachments as an outsider", method: "GET", path: "/bids/" + submittedBid + "/attachments?username=" + carol, status: 403},
+	{name: "attachments of a missing bid", method: "GET", path: "/bids/" + missing + "/attachments?username=" + bob, status: 404},
+
+	{name: "attach file to bid", method: "POST", path: "/bids/" + submittedBid + "/attachments?username=" + bob, file: "certificate.txt", status: 200},
+	{name: "attach nothing to bid", method: "POST", path: "/bids/" + submittedBid + "/attachments?username=" + bob, body: map[string]any{}, status: 400},
+	{name: "attach file to bid as an unknown user", method: "POST", path: "/bids/" + submittedBid + "/attachments?username=" + nobody, file: "certificate.txt", status: 401},
+	{name: "attach file to bid as a reviewer", method: "POST", path: "/bids/" + submittedBid + "/attachments?username=" + alice, file: "certificate.txt", status: 403},
+	{name: "attach file to a missing bid", method: "POST", path: "/bids/" + missing + "/attachments?username=" + bob, file: "certificate.txt", status: 404},
+
+	{name: "download bid attachment", method: "GET", path: "/bids/" + submittedBid + "/attachments/" + priceListAttachment + "?username=" + alice, status: 200},
+	{name: "download bid attachment as an unknown user", method: "GET", path: "/bids/" + submittedBid + "/attachments/" + priceListAttachment + "?username=" + nobody, status: 401},
+	{name: "download bid attachment as an outsider", method: "GET", path: "/bids/" + submittedBid + "/attachments/" + priceListAttachment + "?username=" + carol, status: 403},
+	{name: "download a missing bid attachment", method: "GET", path: "/bids/" + submittedBid + "/attachments/" + missing + "?username=" + bob, status: 404},
+
+	{name: "remove bid attachment", method: "DELETE", path: "/bids/" + submittedBid + "/attachments/" + priceListAttachment + "?username=" + bob, status: 204},
+	{name: "remove bid attachment as an unknown user", method: "DELETE", path: "/bids/" + submittedBid + "/attachments/" + priceListAttachment + "?username=" + nobody, status: 401},
+	{name: "remove bid attachment as a reviewer", method: "DELETE", path: "/bids/" + submittedBid + "/attachments/" + priceListAttachment + "?username=" + alice, status: 403},
+	{name: "remove a missing bid attachment", method: "DELETE", path: "/bids/" + submittedBid + "/attachments/" + missing + "?username=" + bob, status: 404},
+
+	{name: "export bids", method: "GET", path: "/bids/export?username=" + alice, status: 200},
+	{name: "export own bids", method: "GET", path: "/bids/export?format=jsonl&tenderId=" + publishedTender + "&username=" + bob, status: 200},
+	{name: "export bids in an unknown format", method: "GET", path: "/bids/export?format=xml&username=" + alice, status: 400},
+	{name: "export bids as an unknown user", method: "GET", path: "/bids/export?username=" + nobody, status: 401},
+	{name: "export bids of a foreign organization", method: "GET", path: "/bids/export?organizationId=" + buyerOrg + "&username=" + carol, status: 403},
+	{name: "export bids of a missing tender", method: "GET", path: "/bids/export?tenderId=" + missing + "&username=" + alice, status: 404},
+
+	{name: "import bids", method: "POST", path: "/bids/import?format=jsonl&username=" + bob, raw: importedBid(importOrg), contentType: "application/x-ndjson", status: 200, setup: addImportTargets},
+	{name: "import bids in the name of another organization", method: "POST", path: "/bids/import?format=jsonl&username=" + bob, raw: importedBid(importTender), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import bids already approved without the right to vote", method: "POST", path: "/bids/import?format=jsonl&username=" + bob, raw: strings.Replace(importedBid(importOrg), "{", `{"status":"Approved",`, 1), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import bids already edited", method: "POST", path: "/bids/import?format=jsonl&username=" + bob, raw: strings.Replace(importedBid(importOrg), "{", `{"version":2,`, 1), contentType: "application/x-ndjson", status: 200, setup: addImportTargets},
+	{name: "import bids in an unknown status", method: "POST", path: "/bids/import?format=jsonl&username=" + bob, raw: strings.Replace(importedBid(importOrg), "{", `{"status":"Awarded",`, 1), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import bids in the name of an organization the user is not in", method: "POST", path: "/bids/import?format=jsonl&username=" + carol, raw: importedBid(importOrg), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import bids as an unknown user", method: "POST", path: "/bids/import?format=jsonl&username=" + nobody, raw: importedBid(importOrg), contentType: "application/x-ndjson", status: 401, setup: addImportTargets},
+}
+
+// missingComment is a well-formed comment id no comment has.
+const missingComment = "00000000-0000-4000-8000-000000000000"
+
+func deleteAliceComment(f *fixture) {
+	f.comments[0].Deleted = true
+}
+
+// ageAliceComment moves aliceComment out of the window in which it may be
+// changed.
+func ageAliceComment(f *fixture) {
+	f.comments[0].CreatedAt = time.Now().UTC().Add(-time.Hour)
+}
+
+// importedBid is a JSON Lines file with one new bid of the organization on
+// importTender.
+func importedBid(authorId string) string {
+	return fmt.Sprintf(`{"name":"Spring cleaning","description":"Two cleaners for a week","tenderId":%q,"authorType":"Organization","authorId":%q,"price":900}`+"\n", importTender, authorId)
+}
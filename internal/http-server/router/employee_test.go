@@ -0,0 +1,85 @@
+package router_test
+
+import (
+	"strings"
+)
+
+// employeeCases cover the employees themselves: their accounts, profiles and
+// notifications, the audit log of their actions, the supplier profiles they
+// look up and the failed jobs administrators inspect.
+var employeeCases = []contractCase{
+	{name: "list employees", method: "GET", path: "/employees?limit=2&offset=1", status: 200},
+	{name: "list employees with a bad limit", method: "GET", path: "/employees?limit=many", status: 400},
+
+	{name: "create employee", method: "POST", path: "/employees", body: map[string]any{"username": "dave", "first_name": "Dave"}, status: 200},
+	{name: "create employee without a username", method: "POST", path: "/employees", body: map[string]any{"first_name": "Dave"}, status: 400},
+	{name: "create employee under a taken username", method: "POST", path: "/employees", body: map[string]any{"username": alice}, status: 409},
+
+	{name: "employee", method: "GET", path: "/employees/" + alice, status: 200},
+	{name: "missing employee", method: "GET", path: "/employees/" + missing, status: 404},
+
+	{name: "edit employee", method: "PATCH", path: "/employees/" + alice + "?username=" + alice, body: map[string]any{"last_name": "Smith"}, status: 200},
+	{name: "edit employee with a name too long", method: "PATCH", path: "/employees/" + alice + "?username=" + alice, body: map[string]any{"last_name": strings.Repeat("s", 51)}, status: 400},
+	{name: "edit employee as an unknown user", method: "PATCH", path: "/employees/" + alice + "?username=" + nobody, body: map[string]any{"last_name": "Smith"}, status: 401},
+	{name: "edit another employee", method: "PATCH", path: "/employees/" + alice + "?username=" + bob, body: map[string]any{"last_name": "Smith"}, status: 403},
+	{name: "edit a missing employee", method: "PATCH", path: "/employees/" + missing + "?username=" + alice, body: map[string]any{"last_name": "Smith"}, status: 404},
+
+	{name: "delete employee", method: "DELETE", path: "/employees/" + carol + "?username=" + carol, status: 204},
+	{name: "delete employee as an unknown user", method: "DELETE", path: "/employees/" + carol + "?username=" + nobody, status: 401},
+	{name: "delete another employee", method: "DELETE", path: "/employees/" + carol + "?username=" + bob, status: 403},
+	{name: "delete a missing employee", method: "DELETE", path: "/employees/" + missing + "?username=" + carol, status: 404},
+	{name: "delete the last responsible", method: "DELETE", path: "/employees/" + alice + "?username=" + alice, status: 409},
+
+	{name: "own profile", method: "GET", path: "/me?username=" + alice, status: 200},
+	{name: "own profile of an unknown user", method: "GET", path: "/me?username=" + nobody, status: 401},
+
+	{name: "notifications", method: "GET", path: "/notifications?username=" + bob, status: 200},
+	{name: "unread notifications", method: "GET", path: "/notifications?unread=true&limit=1&username=" + bob, status: 200},
+	{name: "notifications with a bad offset", method: "GET", path: "/notifications?offset=-1&username=" + bob, status: 400},
+	{name: "notifications of an unknown user", method: "GET", path: "/notifications?username=" + nobody, status: 401},
+
+	{name: "read all notifications", method: "PUT", path: "/notifications/read?username=" + bob, status: 204},
+	{name: "read all notifications as an unknown user", method: "PUT", path: "/notifications/read?username=" + nobody, status: 401},
+
+	{name: "read notification", method: "PUT", path: "/notifications/" + bobNotification + "/read?username=" + bob, status: 204},
+	{name: "read notification as an unknown user", method: "PUT", path: "/notifications/" + bobNotification + "/read?username=" + nobody, status: 401},
+	{name: "read a notification of someone else", method: "PUT", path: "/notifications/" + bobNotification + "/read?username=" + alice, status: 404},
+
+	{name: "notification settings", method: "GET", path: "/notifications/settings?username=" + bob, status: 200},
+	{name: "notification settings of an unknown user", method: "GET", path: "/notifications/settings?username=" + nobody, status: 401},
+
+	{name: "change notification settings", method: "PUT", path: "/notifications/settings?username=" + bob, body: emailSettings("bob@example.com"), status: 200},
+	{name: "enable email without an address", method: "PUT", path: "/notifications/settings?username=" + bob, body: emailSettings(""), status: 400},
+	{name: "change notification settings as an unknown user", method: "PUT", path: "/notifications/settings?username=" + nobody, body: emailSettings("bob@example.com"), status: 401},
+
+	{name: "audit log", method: "GET", path: "/audit?organizationId=" + buyerOrg + "&username=" + alice, status: 200},
+	{name: "audit log of own actions", method: "GET", path: "/audit?entityType=tender&username=" + alice, status: 200},
+	{name: "audit log from a bad time", method: "GET", path: "/audit?from=yesterday&username=" + alice, status: 400},
+	{name: "audit log as an unknown user", method: "GET", path: "/audit?username=" + nobody, status: 401},
+	{name: "audit log as an outsider", method: "GET", path: "/audit?organizationId=" + buyerOrg + "&username=" + carol, status: 403},
+	{name: "audit log of others without an organization", method: "GET", path: "/audit?actor=" + bob + "&username=" + alice, status: 403},
+
+	{name: "verify audit log", method: "GET", path: "/audit/verify?username=" + alice, status: 200},
+	{name: "verify audit log as an unknown user", method: "GET", path: "/audit/verify?username=" + nobody, status: 401},
+
+	{name: "supplier profile", method: "GET", path: "/suppliers/" + userId(bob) + "?username=" + alice, status: 200},
+	{name: "own supplier profile", method: "GET", path: "/suppliers/" + userId(bob) + "?username=" + bob, status: 200},
+	{name: "supplier profile as an unknown user", method: "GET", path: "/suppliers/" + userId(bob) + "?username=" + nobody, status: 401},
+	{name: "supplier profile as an outsider", method: "GET", path: "/suppliers/" + userId(bob) + "?username=" + carol, status: 403},
+	{name: "profile of a missing supplier", method: "GET", path: "/suppliers/" + missing + "?username=" + alice, status: 404},
+
+	{name: "failed jobs", method: "GET", path: "/admin/jobs/failed?kind=send_email&username=" + alice, status: 200},
+	{name: "failed jobs with a bad limit", method: "GET", path: "/admin/jobs/failed?limit=500&username=" + alice, status: 400},
+	{name: "failed jobs as an unknown user", method: "GET", path: "/admin/jobs/failed?username=" + nobody, status: 401},
+	{name: "failed jobs as a non-administrator", method: "GET", path: "/admin/jobs/failed?username=" + bob, status: 403},
+}
+
+func emailSettings(address string) map[string]any {
+	return map[string]any{
+		"language":     "ru",
+		"inApp":        true,
+		"email":        true,
+		"emailAddress": address,
+		"muted":        []string{"bid_decided"},
+	}
+}
@@ -0,0 +1,60 @@
+package router_test
+
+import (
+	"slices"
+	"tender_system/internal/models/attachment"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// The attachments of the fixture's tenders and bids. Their content lives in
+// the blob store newServer sets up.
+
+func (f *fixture) AddAttachment(att attachment.Attachment) (attachment.Attachment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	att.Id = f.nextId()
+	att.CreatedAt = time.Now().UTC()
+	f.attachments = append(f.attachments, att)
+	return att, nil
+}
+
+func (f *fixture) ListAttachments(entityType, entityId string) ([]attachment.Attachment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]attachment.Attachment, 0)
+	for _, att := range f.attachments {
+		if att.EntityType == entityType && att.EntityId == entityId {
+			result = append(result, att)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) GetAttachment(entityType, entityId, attachmentId string) (attachment.Attachment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, att := range f.attachments {
+		if att.EntityType == entityType && att.EntityId == entityId && att.Id == attachmentId {
+			return att, nil
+		}
+	}
+	return attachment.Attachment{}, storage.ErrNotFound
+}
+
+func (f *fixture) RemoveAttachment(entityType, entityId, attachmentId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.attachments, func(att attachment.Attachment) bool {
+		return att.EntityType == entityType && att.EntityId == entityId && att.Id == attachmentId
+	})
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.attachments = slices.Delete(f.attachments, i, i+1)
+	return nil
+}
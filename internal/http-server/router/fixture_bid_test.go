@@ -0,0 +1,201 @@
+package router_test
+
+import (
+	"slices"
+	biddomain "tender_system/internal/domain/bid"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/supplier"
+	"tender_system/internal/models/transfer"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// The feedback threads of the fixture's bids, the supplier statistics built
+// from them and bulk transfers of bids.
+
+func (f *fixture) GetComment(bidId, commentId string) (bids.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range f.comments {
+		if c.BidId == bidId && c.Id == commentId {
+			return c, nil
+		}
+	}
+	return bids.Comment{}, storage.ErrNotFound
+}
+
+func (f *fixture) ListComments(bidId string) ([]bids.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]bids.Comment, 0)
+	for _, c := range f.comments {
+		if c.BidId == bidId {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) UpdateComment(commentId, description string, rating *int) (bids.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.comments, func(c bids.Comment) bool { return c.Id == commentId })
+	if i < 0 {
+		return bids.Comment{}, storage.ErrNotFound
+	}
+	now := time.Now().UTC()
+	f.comments[i].Description = description
+	if rating != nil {
+		f.comments[i].Rating = rating
+	}
+	f.comments[i].EditedAt = &now
+	return f.comments[i], nil
+}
+
+func (f *fixture) DeleteComment(commentId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.comments, func(c bids.Comment) bool { return c.Id == commentId })
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.comments[i].Description = ""
+	f.comments[i].Rating = nil
+	f.comments[i].Deleted = true
+	return nil
+}
+
+func (f *fixture) ReadSupplierStats(authorType, authorId string) (supplier.Stats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := supplier.Stats{AuthorType: authorType, AuthorId: authorId}
+	if authorType == "Organization" {
+		stats.Name = f.organizations[authorId].Name
+	} else {
+		stats.Name = f.username(authorId)
+	}
+	if stats.Name == "" {
+		return supplier.Stats{}, storage.ErrNotFound
+	}
+
+	tenders := make(map[string]bool)
+	for _, row := range f.bids {
+		if row.AuthorType != authorType || row.AuthorId != authorId || row.Status == biddomain.Draft {
+			continue
+		}
+		stats.Bids++
+		tenders[row.TenderId] = true
+		if row.Status == biddomain.Approved || row.Status == biddomain.Rejected {
+			stats.Decided++
+		}
+		if row.Status == biddomain.Approved {
+			stats.Won++
+		}
+		for _, c := range f.comments {
+			if c.BidId == row.Id && !c.Deleted && c.Rating != nil {
+				stats.Ratings++
+				stats.RatingSum += *c.Rating
+			}
+		}
+		for _, aw := range f.awards {
+			if aw.BidId == row.Id && aw.Delivery != nil {
+				stats.Deliveries++
+				if aw.Delivery.OnTime {
+					stats.OnTimeDeliveries++
+				}
+			}
+		}
+	}
+	stats.Tenders = len(tenders)
+	return stats, nil
+}
+
+func (f *fixture) EachBid(filter transfer.BidFilter, fn func(transfer.Bid) error) error {
+	f.mu.Lock()
+	var result []transfer.Bid
+	for _, b := range f.sortedBids(func(row *bidRow) bool {
+		ten, ok := f.tenders[row.TenderId]
+		visible := ok && slices.Contains(filter.OrganizationIds, ten.OrganizationId) || slices.Contains(filter.AuthorIds, row.AuthorId)
+		return visible &&
+			(filter.TenderId == "" || row.TenderId == filter.TenderId) &&
+			(filter.Status == "" || row.Status == filter.Status)
+	}) {
+		result = append(result, transfer.Bid{
+			Id:          b.Id,
+			Name:        b.Name,
+			Description: b.Description,
+			TenderId:    b.TenderId,
+			AuthorType:  b.AuthorType,
+			AuthorId:    b.AuthorId,
+			Status:      b.Status,
+			Price:       b.Price,
+			Version:     b.Version,
+			CreatedAt:   b.CreatedAt,
+		})
+	}
+	f.mu.Unlock()
+
+	for _, b := range result {
+		err := fn(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fixture) ExistingBids(ids []string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]string, 0)
+	for _, id := range ids {
+		if _, ok := f.bids[id]; ok {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// ImportBids keeps the copied bids aside until fill succeeds, as the
+// storage's transaction does.
+func (f *fixture) ImportBids(fill func(copy func([]transfer.Bid) error) error) error {
+	var staged []transfer.Bid
+	err := fill(func(batch []transfer.Bid) error {
+		staged = append(staged, batch...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, b := range staged {
+		if b.Id == "" {
+			b.Id = f.nextId()
+		}
+		f.bids[b.Id] = &bidRow{
+			Bid: bids.Bid{
+				Id:          b.Id,
+				Name:        b.Name,
+				Status:      b.Status,
+				Description: b.Description,
+				TenderId:    b.TenderId,
+				AuthorType:  b.AuthorType,
+				AuthorId:    b.AuthorId,
+				Price:       b.Price,
+				Version:     b.Version,
+				CreatedAt:   b.CreatedAt,
+			},
+			Votes: make(map[string]string),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package router_test
+
+import (
+	"tender_system/internal/models/idempotency"
+	"time"
+)
+
+// The Idempotency-Key records, claimed, taken over and expired the way the
+// storage does.
+
+func (f *fixture) ReserveIdempotencyKey(key, fingerprint string, now, lockedUntil, expiresAt time.Time) (idempotency.Record, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if rec, ok := f.idempotency[key]; ok && rec.ExpiresAt.After(now) && (rec.Status != 0 || rec.LockedUntil.After(now)) {
+		return rec, false, nil
+	}
+	rec := idempotency.Record{Key: key, Fingerprint: fingerprint, CreatedAt: now, LockedUntil: lockedUntil, ExpiresAt: expiresAt}
+	f.idempotency[key] = rec
+	return rec, true, nil
+}
+
+func (f *fixture) SaveIdempotentResponse(reserved idempotency.Record, status int, contentType string, body []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, ok := f.idempotency[reserved.Key]
+	if !ok || !rec.CreatedAt.Equal(reserved.CreatedAt) {
+		return nil
+	}
+	rec.Status, rec.ContentType, rec.Body, rec.LockedUntil = status, contentType, body, time.Time{}
+	f.idempotency[rec.Key] = rec
+	return nil
+}
+
+func (f *fixture) ReleaseIdempotencyKey(reserved idempotency.Record) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if rec, ok := f.idempotency[reserved.Key]; ok && rec.CreatedAt.Equal(reserved.CreatedAt) {
+		delete(f.idempotency, rec.Key)
+	}
+	return nil
+}
+
+// holdIdempotencyKeys turns every key back into a reservation still in
+// progress, its lease ending at lockedUntil.
+func (f *fixture) holdIdempotencyKeys(lockedUntil time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key, rec := range f.idempotency {
+		rec.Status, rec.ContentType, rec.Body, rec.LockedUntil = 0, "", nil, lockedUntil
+		f.idempotency[key] = rec
+	}
+}
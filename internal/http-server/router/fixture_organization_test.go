@@ -0,0 +1,405 @@
+package router_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"tender_system/internal/authz"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/organization"
+	"tender_system/internal/models/template"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// The organization side of the fixture: organizations, their responsibles,
+// roles, conflict rules and templates, and the employees themselves.
+
+func (f *fixture) ListOrganizations(limit, offset int) ([]organization.Organization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]organization.Organization, 0, len(f.organizations))
+	for _, org := range f.organizations {
+		result = append(result, org)
+	}
+	slices.SortFunc(result, func(a, b organization.Organization) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) GetOrganization(organizationId string) (organization.Organization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	org, ok := f.organizations[organizationId]
+	if !ok {
+		return organization.Organization{}, storage.ErrNotFound
+	}
+	return org, nil
+}
+
+func (f *fixture) SaveOrganization(req organization.OrganizationRequest, creatorId string) (organization.Organization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now().UTC()
+	org := organization.Organization{
+		Id:          f.nextId(),
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	f.organizations[org.Id] = org
+	f.members[org.Id] = make(map[string][]string)
+	f.responsibles[org.Id] = []string{creatorId}
+	return org, nil
+}
+
+func (f *fixture) UpdateOrganization(org organization.Organization) (organization.Organization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.organizations[org.Id]; !ok {
+		return organization.Organization{}, storage.ErrNotFound
+	}
+	org.UpdatedAt = time.Now().UTC()
+	f.organizations[org.Id] = org
+	return org, nil
+}
+
+func (f *fixture) DeleteOrganization(organizationId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.organizations[organizationId]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(f.organizations, organizationId)
+	delete(f.members, organizationId)
+	delete(f.responsibles, organizationId)
+	for id, row := range f.tenders {
+		if row.OrganizationId == organizationId {
+			delete(f.tenders, id)
+		}
+	}
+	return nil
+}
+
+func (f *fixture) ListResponsibles(organizationId string, limit, offset int) ([]organization.Responsible, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]organization.Responsible, 0)
+	for _, userId := range f.responsibles[organizationId] {
+		result = append(result, organization.Responsible{
+			Id:             organizationId + "-" + userId,
+			OrganizationId: organizationId,
+			UserId:         userId,
+			Username:       f.username(userId),
+		})
+	}
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) AddResponsible(organizationId, userId string) (organization.Responsible, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !slices.Contains(f.responsibles[organizationId], userId) {
+		f.responsibles[organizationId] = append(f.responsibles[organizationId], userId)
+	}
+	return organization.Responsible{
+		Id:             organizationId + "-" + userId,
+		OrganizationId: organizationId,
+		UserId:         userId,
+		Username:       f.username(userId),
+	}, nil
+}
+
+func (f *fixture) RemoveResponsible(organizationId, userId string, guard func(organizationId string, responsibles, openTenders int) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.organizations[organizationId]; !ok {
+		return storage.ErrNotFound
+	}
+	err := guard(organizationId, len(f.responsibles[organizationId]), f.openTenders(organizationId))
+	if err != nil {
+		return err
+	}
+
+	i := slices.Index(f.responsibles[organizationId], userId)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.responsibles[organizationId] = slices.Delete(f.responsibles[organizationId], i, i+1)
+	return nil
+}
+
+func (f *fixture) IsOrganizationResponsible(organizationId, userId string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Contains(f.responsibles[organizationId], userId), nil
+}
+
+func (f *fixture) CountOpenTenders(organizationId string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.openTenders(organizationId), nil
+}
+
+func (f *fixture) openTenders(organizationId string) int {
+	open := 0
+	for _, row := range f.tenders {
+		if row.OrganizationId == organizationId && row.Status != tenderdomain.Cancelled && row.Status != tenderdomain.Awarded {
+			open++
+		}
+	}
+	return open
+}
+
+// username resolves a user id. The caller holds f.mu.
+func (f *fixture) username(userId string) string {
+	for _, usr := range f.users {
+		if usr.Id == userId {
+			return usr.Username
+		}
+	}
+	return ""
+}
+
+func (f *fixture) GrantRole(organizationId, userId string, role authz.Role) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !slices.Contains(f.members[organizationId][userId], string(role)) {
+		f.members[organizationId][userId] = append(f.members[organizationId][userId], string(role))
+	}
+	return nil
+}
+
+func (f *fixture) RevokeRole(organizationId, userId string, role authz.Role) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	roles := f.members[organizationId][userId]
+	i := slices.Index(roles, string(role))
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.members[organizationId][userId] = slices.Delete(roles, i, i+1)
+	return nil
+}
+
+func (f *fixture) ReadConflictRules(organizationId string) ([]conflict.Rule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.conflictRules[organizationId]), nil
+}
+
+func (f *fixture) SaveConflictRules(organizationId string, rules []conflict.Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, r := range rules {
+		i := slices.IndexFunc(f.conflictRules[organizationId], func(stored conflict.Rule) bool {
+			return stored.Rule == r.Rule
+		})
+		if i < 0 {
+			f.conflictRules[organizationId] = append(f.conflictRules[organizationId], r)
+		} else {
+			f.conflictRules[organizationId][i] = r
+		}
+	}
+	return nil
+}
+
+func (f *fixture) RecordConflicts(entityType, entityId, organizationId, actor string, findings []conflict.Finding) error {
+	for _, finding := range findings {
+		after, err := json.Marshal(finding)
+		if err != nil {
+			return err
+		}
+		_, err = f.AppendAudit(audit.Entry{
+			EntityType:     entityType,
+			EntityId:       entityId,
+			Action:         fmt.Sprintf("conflict of interest %s: %s", finding.Rule, finding.Action),
+			Actor:          actor,
+			OrganizationId: organizationId,
+			After:          after,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fixture) ListTemplates(organizationId string) ([]template.Template, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]template.Template, 0)
+	for _, t := range f.templates {
+		if t.OrganizationId == organizationId {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) GetTemplate(organizationId, templateId string) (template.Template, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t, ok := f.templates[templateId]
+	if !ok || t.OrganizationId != organizationId {
+		return template.Template{}, storage.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f *fixture) SaveTemplate(t template.Template) (template.Template, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now().UTC()
+	if t.Id == "" {
+		t.Id = f.nextId()
+		t.CreatedAt = now
+	}
+	t.UpdatedAt = now
+	f.templates[t.Id] = t
+	return t, nil
+}
+
+func (f *fixture) DeleteTemplate(organizationId, templateId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t, ok := f.templates[templateId]
+	if !ok || t.OrganizationId != organizationId {
+		return storage.ErrNotFound
+	}
+	delete(f.templates, templateId)
+	return nil
+}
+
+func (f *fixture) ReadUserMemberships(userId string) ([]user.Membership, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]user.Membership, 0)
+	for organizationId, members := range f.members {
+		if roles := members[userId]; len(roles) > 0 {
+			org := f.organizations[organizationId]
+			result = append(result, user.Membership{
+				OrganizationId:   org.Id,
+				OrganizationName: org.Name,
+				OrganizationType: org.Type,
+				Roles:            roles,
+			})
+		}
+	}
+	slices.SortFunc(result, func(a, b user.Membership) int {
+		if a.OrganizationId < b.OrganizationId {
+			return -1
+		}
+		if a.OrganizationId > b.OrganizationId {
+			return 1
+		}
+		return 0
+	})
+	return result, nil
+}
+
+func (f *fixture) ListEmployees(limit, offset int) ([]user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]user.User, 0, len(f.users))
+	for _, usr := range f.users {
+		result = append(result, usr)
+	}
+	slices.SortFunc(result, func(a, b user.User) int {
+		if a.Username < b.Username {
+			return -1
+		}
+		if a.Username > b.Username {
+			return 1
+		}
+		return 0
+	})
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) SaveEmployee(req user.EmployeeRequest) (user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.users[req.Username]; ok {
+		return user.User{}, fmt.Errorf("%w: username %s is taken", storage.ErrConflict, req.Username)
+	}
+	now := time.Now().UTC()
+	usr := user.User{
+		Id:        userId(req.Username),
+		Username:  req.Username,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	f.users[usr.Username] = usr
+	return usr, nil
+}
+
+func (f *fixture) UpdateEmployee(usr user.User) (user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.users[usr.Username]; !ok {
+		return user.User{}, storage.ErrNotFound
+	}
+	usr.UpdatedAt = time.Now().UTC()
+	f.users[usr.Username] = usr
+	return usr, nil
+}
+
+func (f *fixture) DeleteEmployee(userId string, guard func(organizationId string, responsibles, openTenders int) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for organizationId, userIds := range f.responsibles {
+		if !slices.Contains(userIds, userId) {
+			continue
+		}
+		err := guard(organizationId, len(userIds), f.openTenders(organizationId))
+		if err != nil {
+			return err
+		}
+	}
+
+	for username, usr := range f.users {
+		if usr.Id == userId {
+			delete(f.users, username)
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
@@ -0,0 +1,401 @@
+package router_test
+
+import (
+	"slices"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/award"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/job"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/schedule"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/transfer"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// The tender side of the fixture beyond the tenders themselves: lots,
+// auctions, schedules, recusals, reports, awards and bulk transfers.
+
+func (f *fixture) ListLots(tenderId string) ([]lot.Lot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]lot.Lot, 0)
+	for _, l := range f.lots {
+		if l.TenderId == tenderId {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) GetLot(tenderId, lotId string) (lot.Lot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, l := range f.lots {
+		if l.TenderId == tenderId && l.Id == lotId {
+			return l, nil
+		}
+	}
+	return lot.Lot{}, storage.ErrNotFound
+}
+
+func (f *fixture) AddLot(tenderId string, req lot.LotRequest) (lot.Lot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l := lot.Lot{
+		Id:          f.nextId(),
+		TenderId:    tenderId,
+		Name:        req.Name,
+		Description: req.Description,
+		ServiceType: req.ServiceType,
+		Budget:      req.Budget,
+		CreatedAt:   time.Now().UTC(),
+	}
+	f.lots = append(f.lots, l)
+	return l, nil
+}
+
+func (f *fixture) UpdateLot(l lot.Lot) (lot.Lot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.lots, func(stored lot.Lot) bool { return stored.Id == l.Id })
+	if i < 0 {
+		return lot.Lot{}, storage.ErrNotFound
+	}
+	f.lots[i] = l
+	return l, nil
+}
+
+func (f *fixture) RemoveLot(tenderId, lotId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.lots, func(l lot.Lot) bool { return l.TenderId == tenderId && l.Id == lotId })
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.lots = slices.Delete(f.lots, i, i+1)
+	return nil
+}
+
+func (f *fixture) GetAuction(tenderId string) (auction.Auction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	a, ok := f.auctions[tenderId]
+	if !ok {
+		return auction.Auction{}, storage.ErrNotFound
+	}
+	return a, nil
+}
+
+func (f *fixture) SetPublishAt(tenderId string, publishAt *time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.tenders[tenderId]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	row.PublishAt = publishAt
+	return nil
+}
+
+func (f *fixture) EnqueueJob(j job.Job) (job.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	j.Id = f.nextId()
+	j.Status = "Pending"
+	j.CreatedAt = time.Now().UTC()
+	j.UpdatedAt = j.CreatedAt
+	f.jobs = append(f.jobs, j)
+	return j, nil
+}
+
+func (f *fixture) CancelJobs(kind, subjectId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.jobs = slices.DeleteFunc(f.jobs, func(j job.Job) bool {
+		return j.Kind == kind && j.SubjectId == subjectId
+	})
+	return nil
+}
+
+func (f *fixture) GetRecurrence(tenderId string) (schedule.Recurrence, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, ok := f.recurrences[tenderId]
+	if !ok {
+		return schedule.Recurrence{}, storage.ErrNotFound
+	}
+	return rec, nil
+}
+
+func (f *fixture) SaveRecurrence(r schedule.Recurrence) (schedule.Recurrence, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r.CreatedAt = time.Now().UTC()
+	f.recurrences[r.TenderId] = r
+	return r, nil
+}
+
+func (f *fixture) DeleteRecurrence(tenderId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.recurrences[tenderId]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(f.recurrences, tenderId)
+	return nil
+}
+
+func (f *fixture) SaveRecusal(r conflict.Recusal) (conflict.Recusal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r.CreatedAt = time.Now().UTC()
+	f.recusals = append(f.recusals, r)
+	return r, nil
+}
+
+func (f *fixture) ListRecusals(tenderId string) ([]conflict.Recusal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]conflict.Recusal, 0)
+	for _, r := range f.recusals {
+		if r.TenderId == tenderId {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) HasVotedOnTender(tenderId, userId string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, row := range f.bids {
+		if _, ok := row.Votes[userId]; ok && row.TenderId == tenderId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fixture) ListBudgetLines(tenderId string) ([]tender.BudgetReportLine, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]tender.BudgetReportLine, 0)
+	for _, b := range f.sortedBids(func(row *bidRow) bool { return row.TenderId == tenderId }) {
+		result = append(result, tender.BudgetReportLine{BidId: b.Id, Name: b.Name, Status: b.Status, Price: b.Price})
+	}
+	return result, nil
+}
+
+func (f *fixture) ListCommitteeBids(tenderId string) ([]tender.CommitteeBid, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]tender.CommitteeBid, 0)
+	for _, row := range f.bids {
+		if row.TenderId == tenderId {
+			result = append(result, tender.CommitteeBid{
+				BidId:      row.Id,
+				Name:       row.Name,
+				AuthorType: row.AuthorType,
+				AuthorId:   row.AuthorId,
+				Author:     f.username(row.AuthorId),
+				Version:    row.Version,
+				Status:     row.Status,
+				Price:      row.Price,
+				VotesCast:  len(row.Votes),
+				CreatedAt:  row.CreatedAt,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) ListCommitteeFeedback(tenderId string) ([]tender.CommitteeFeedback, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]tender.CommitteeFeedback, 0)
+	for _, c := range f.comments {
+		if row := f.bids[c.BidId]; row.TenderId == tenderId && !c.Deleted {
+			result = append(result, tender.CommitteeFeedback{
+				BidId:       row.Id,
+				BidName:     row.Name,
+				Author:      c.Author,
+				Side:        c.Side,
+				Description: c.Description,
+				Rating:      c.Rating,
+				CreatedAt:   c.CreatedAt,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) ListCommitteeVotes(tenderId string) ([]tender.CommitteeVote, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]tender.CommitteeVote, 0)
+	for _, row := range f.bids {
+		if row.TenderId != tenderId {
+			continue
+		}
+		dec := f.decision(row)
+		for userId, vote := range row.Votes {
+			result = append(result, tender.CommitteeVote{
+				BidId:       row.Id,
+				BidName:     row.Name,
+				Username:    f.username(userId),
+				Vote:        vote,
+				Decision:    dec.Status,
+				NumApproved: dec.NumApproved,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) RecordDelivery(awardId string, d award.Delivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.awards, func(aw award.Award) bool { return aw.Id == awardId })
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.awards[i].Delivery = &d
+	return nil
+}
+
+func (f *fixture) GetContract(aw award.Award) (award.Contract, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ten, bid := f.tenders[aw.TenderId], f.bids[aw.BidId]
+	return award.Contract{
+		Award:             aw,
+		TenderName:        ten.Name,
+		TenderDescription: ten.Description,
+		ServiceType:       ten.ServiceType,
+		OrganizationName:  f.organizations[ten.OrganizationId].Name,
+		BidName:           bid.Name,
+		BidDescription:    bid.Description,
+		BidAuthorType:     bid.AuthorType,
+		BidAuthorName:     f.username(bid.AuthorId),
+		BidVersion:        bid.Version,
+	}, nil
+}
+
+// awardSubmittedBid awards the published tender to the submitted bid, as if
+// alice had approved it.
+func awardSubmittedBid(f *fixture) {
+	row := f.bids[submittedBid]
+	row.Status = "Approved"
+	row.Closed = true
+	f.tenders[publishedTender].Status = "Awarded"
+	f.awards = append(f.awards, award.Award{
+		Id:        f.nextId(),
+		TenderId:  publishedTender,
+		BidId:     submittedBid,
+		Approvers: []string{alice},
+		CreatedAt: row.CreatedAt,
+	})
+}
+
+func (f *fixture) EachTender(filter transfer.TenderFilter, fn func(transfer.Tender) error) error {
+	f.mu.Lock()
+	rows := f.sortedTenders()
+	f.mu.Unlock()
+
+	for _, row := range rows {
+		if !slices.Contains(filter.OrganizationIds, row.OrganizationId) ||
+			filter.ServiceType != "" && row.ServiceType != filter.ServiceType ||
+			filter.Status != "" && row.Status != filter.Status {
+			continue
+		}
+		err := fn(transfer.Tender{
+			Id:              row.Id,
+			Name:            row.Name,
+			Description:     row.Description,
+			ServiceType:     row.ServiceType,
+			Status:          row.Status,
+			OrganizationId:  row.OrganizationId,
+			CreatorUsername: row.Creator,
+			Version:         row.Version,
+			CreatedAt:       row.CreatedAt,
+			Deadline:        row.Deadline,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fixture) ExistingTenders(ids []string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]string, 0)
+	for _, id := range ids {
+		if _, ok := f.tenders[id]; ok {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// ImportTenders keeps the copied tenders aside until fill succeeds, as the
+// storage's transaction does.
+func (f *fixture) ImportTenders(fill func(copy func([]transfer.Tender) error) error) error {
+	var staged []transfer.Tender
+	err := fill(func(batch []transfer.Tender) error {
+		staged = append(staged, batch...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, t := range staged {
+		if t.Id == "" {
+			t.Id = f.nextId()
+		}
+		f.tenders[t.Id] = &tenderRow{
+			Tender: tender.Tender{
+				Id:             t.Id,
+				Name:           t.Name,
+				Description:    t.Description,
+				ServiceType:    t.ServiceType,
+				Status:         t.Status,
+				OrganizationId: t.OrganizationId,
+				Version:        t.Version,
+				CreatedAt:      t.CreatedAt,
+				Deadline:       t.Deadline,
+			},
+			Creator: t.CreatorUsername,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,940 @@
+package router_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/http-server/router"
+	"tender_system/internal/models/attachment"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/award"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/idempotency"
+	"tender_system/internal/models/job"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/models/organization"
+	"tender_system/internal/models/schedule"
+	"tender_system/internal/models/template"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/notify"
+	"tender_system/internal/service"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// The fixture is seeded with a buying organization owned by alice, a
+// supplier organization bob bids for, and carol, who belongs to neither.
+const (
+	buyerOrg    = "3b1e8c52-7d4a-4f0e-9a61-2c5d8e7f1a01"
+	supplierOrg = "3b1e8c52-7d4a-4f0e-9a61-2c5d8e7f1a02"
+
+	alice = "alice"
+	bob   = "bob"
+	carol = "carol"
+	// nobody is not an employee at all.
+	nobody = "nobody"
+
+	publishedTender = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b01"
+	draftTender     = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b02"
+	submittedBid    = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b03"
+	// aliceComment starts the feedback thread of the submitted bid. Replies
+	// name their parent by UUID.
+	aliceComment = "6f1c2d4e-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+	// foundationLot is a lot of the draft tender.
+	foundationLot = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b04"
+	// cleaningTemplate is a template of the buying organization.
+	cleaningTemplate = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b05"
+	// bobNotification tells bob about alice's feedback.
+	bobNotification = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b06"
+	// specAttachment is attached to the published tender and
+	// priceListAttachment to the submitted bid. Both hold attachmentContent.
+	specAttachment      = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b07"
+	priceListAttachment = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b08"
+	attachmentContent   = "Deliveries go to the loading dock."
+	// missing is a well-formed id nothing has, and malformedId an id that
+	// is no UUID at all.
+	missing     = "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2bff"
+	malformedId = "tender-1"
+)
+
+type store interface {
+	service.Repository
+	router.Storage
+}
+
+type tenderRow struct {
+	tender.Tender
+	Creator string
+	History []tender.Tender
+}
+
+type bidRow struct {
+	bids.Bid
+	History []bids.Bid
+	Votes   map[string]string
+	Closed  bool
+}
+
+// fixture is an in-memory stand-in for the Postgres storage covering what
+// the documented operations use, with the same error conventions. Like the
+// storage it only keeps data: permissions, versions and status rules are
+// left to the services, so the routes are tested against the real ones.
+// Anything else falls through to the nil store and panics.
+type fixture struct {
+	store
+
+	mu            sync.Mutex
+	users         map[string]user.User
+	members       map[string]map[string][]string
+	organizations map[string]organization.Organization
+	responsibles  map[string][]string
+	conflictRules map[string][]conflict.Rule
+	templates     map[string]template.Template
+	lots          []lot.Lot
+	auctions      map[string]auction.Auction
+	recurrences   map[string]schedule.Recurrence
+	jobs          []job.Job
+	failedJobs    []job.Job
+	recusals      []conflict.Recusal
+	tenders       map[string]*tenderRow
+	bids          map[string]*bidRow
+	comments      []bids.Comment
+	attachments   []attachment.Attachment
+	awards        []award.Award
+	notifications []notification.Notification
+	settings      map[string]notification.Settings
+	audit         []audit.Entry
+	idempotency   map[string]idempotency.Record
+	authz         *authz.Authorizer
+	seq           int
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		users:         make(map[string]user.User),
+		members:       make(map[string]map[string][]string),
+		organizations: make(map[string]organization.Organization),
+		responsibles:  make(map[string][]string),
+		conflictRules: make(map[string][]conflict.Rule),
+		templates:     make(map[string]template.Template),
+		auctions:      make(map[string]auction.Auction),
+		recurrences:   make(map[string]schedule.Recurrence),
+		settings:      make(map[string]notification.Settings),
+		tenders:       make(map[string]*tenderRow),
+		bids:          make(map[string]*bidRow),
+		idempotency:   make(map[string]idempotency.Record),
+	}
+	f.authz = authz.New(f)
+
+	created := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	for _, username := range []string{alice, bob, carol} {
+		f.users[username] = user.User{Id: userId(username), Username: username, CreatedAt: created, UpdatedAt: created}
+	}
+	f.organizations[buyerOrg] = organization.Organization{Id: buyerOrg, Name: "Buyer", Description: "Buys office services", Type: "LLC", CreatedAt: created, UpdatedAt: created}
+	f.organizations[supplierOrg] = organization.Organization{Id: supplierOrg, Name: "Supplier", Description: "Delivers office supplies", Type: "IE", CreatedAt: created, UpdatedAt: created}
+	f.members[buyerOrg] = map[string][]string{userId(alice): {string(authz.Owner)}}
+	f.members[supplierOrg] = map[string][]string{userId(bob): {string(authz.Bidder)}}
+	f.responsibles[buyerOrg] = []string{userId(alice)}
+
+	f.lots = append(f.lots, lot.Lot{
+		Id:          foundationLot,
+		TenderId:    draftTender,
+		Name:        "Foundation",
+		Description: "Lay the foundation",
+		ServiceType: "Construction",
+		CreatedAt:   created,
+	})
+	f.recurrences[publishedTender] = schedule.Recurrence{
+		TenderId:  publishedTender,
+		Frequency: "Monthly",
+		NextRunAt: created.AddDate(0, 1, 0),
+		CreatedBy: alice,
+		CreatedAt: created,
+	}
+
+	f.templates[cleaningTemplate] = template.Template{
+		Id:             cleaningTemplate,
+		OrganizationId: buyerOrg,
+		Title:          "Cleaning",
+		Spec: template.Spec{
+			Name:        "Office cleaning",
+			Description: "Clean the office every evening",
+			ServiceType: "Delivery",
+		},
+		CreatedBy: alice,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	f.tenders[publishedTender] = &tenderRow{
+		Tender: tender.Tender{
+			Id:             publishedTender,
+			Name:           "Office delivery",
+			Description:    "Deliver office supplies twice a month",
+			ServiceType:    "Delivery",
+			Status:         tenderdomain.Published,
+			Type:           "Standard",
+			OrganizationId: buyerOrg,
+			Version:        2,
+			CreatedAt:      created,
+			Budget:         tender.Budget{Visibility: "Public", Policy: "Flag"},
+		},
+		Creator: alice,
+		History: []tender.Tender{{
+			Id:             publishedTender,
+			Name:           "Delivery",
+			Description:    "Deliver office supplies",
+			ServiceType:    "Delivery",
+			Status:         tenderdomain.Created,
+			Type:           "Standard",
+			OrganizationId: buyerOrg,
+			Version:        1,
+			CreatedAt:      created,
+		}},
+	}
+	f.tenders[draftTender] = &tenderRow{
+		Tender: tender.Tender{
+			Id:             draftTender,
+			Name:           "Warehouse",
+			Description:    "Build a warehouse",
+			ServiceType:    "Construction",
+			Status:         tenderdomain.Created,
+			Type:           "Standard",
+			OrganizationId: buyerOrg,
+			Version:        1,
+			CreatedAt:      created,
+			Budget:         tender.Budget{Visibility: "Public", Policy: "Flag"},
+		},
+		Creator: alice,
+	}
+
+	price := 1200.0
+	f.bids[submittedBid] = &bidRow{
+		Bid: bids.Bid{
+			Id:          submittedBid,
+			Name:        "Weekly delivery",
+			Status:      biddomain.Submitted,
+			Description: "Delivery every Monday",
+			TenderId:    publishedTender,
+			AuthorType:  "User",
+			AuthorId:    userId(bob),
+			Price:       &price,
+			Version:     2,
+			CreatedAt:   created,
+		},
+		History: []bids.Bid{{
+			Id:          submittedBid,
+			Name:        "Delivery",
+			Status:      biddomain.Draft,
+			Description: "Delivery every Monday",
+			TenderId:    publishedTender,
+			AuthorType:  "User",
+			AuthorId:    userId(bob),
+			Price:       &price,
+			Version:     1,
+			CreatedAt:   created,
+		}},
+		Votes: make(map[string]string),
+	}
+	rating := 4
+	f.comments = append(f.comments, bids.Comment{
+		Id:          aliceComment,
+		BidId:       submittedBid,
+		AuthorId:    userId(alice),
+		Author:      alice,
+		Side:        biddomain.SideTender,
+		Description: "Can you deliver on Tuesdays too?",
+		Rating:      &rating,
+		CreatedAt:   time.Now().UTC(),
+	})
+	checksum := sha256.Sum256([]byte(attachmentContent))
+	f.attachments = append(f.attachments, attachment.Attachment{
+		Id:          specAttachment,
+		EntityType:  attachment.Tender,
+		EntityId:    publishedTender,
+		FileName:    "specification.txt",
+		ContentType: "text/plain",
+		Size:        int64(len(attachmentContent)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		StorageKey:  "tenders/" + publishedTender + "/spec",
+		UploadedBy:  alice,
+		CreatedAt:   created,
+	}, attachment.Attachment{
+		Id:          priceListAttachment,
+		EntityType:  attachment.Bid,
+		EntityId:    submittedBid,
+		FileName:    "prices.txt",
+		ContentType: "text/plain",
+		Size:        int64(len(attachmentContent)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		StorageKey:  "bids/" + submittedBid + "/prices",
+		UploadedBy:  bob,
+		CreatedAt:   created,
+	})
+	f.notifications = append(f.notifications, notification.Notification{
+		Id:        bobNotification,
+		UserId:    userId(bob),
+		Event:     notification.FeedbackLeft,
+		TenderId:  publishedTender,
+		BidId:     submittedBid,
+		Subject:   "New feedback on Weekly delivery",
+		Body:      "Can you deliver on Tuesdays too?",
+		CreatedAt: created,
+	})
+	f.AppendAudit(audit.Entry{
+		CreatedAt:      created,
+		EntityType:     "tender",
+		EntityId:       publishedTender,
+		Action:         "create",
+		Actor:          alice,
+		OrganizationId: buyerOrg,
+		After:          []byte(`{"status":"Created"}`),
+	})
+	f.failedJobs = append(f.failedJobs, job.Job{
+		Id:          "5c0a7e21-1b3d-4e8f-a2c4-6d9e0f1a2b09",
+		Kind:        notify.EmailJob,
+		Payload:     []byte(`{"to":"bob@example.com","subject":"New feedback","body":"Can you deliver on Tuesdays too?"}`),
+		Status:      "Failed",
+		Attempts:    5,
+		MaxAttempts: 5,
+		RunAt:       created,
+		LastError:   "connection refused",
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	})
+
+	return f
+}
+
+// nextId returns a new id, a UUID like the ones the database assigns.
+func (f *fixture) nextId() string {
+	f.seq++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", f.seq)
+}
+
+// userId is the id of the user with the username, derived from it so that
+// tests can name the seeded users' ids.
+func userId(username string) string {
+	sum := sha256.Sum256([]byte(username))
+	h := hex.EncodeToString(sum[:16])
+	return h[:8] + "-" + h[8:12] + "-4" + h[13:16] + "-8" + h[17:20] + "-" + h[20:32]
+}
+
+func tenderResponse(t tender.Tender) tender.TenderResponse {
+	return tender.TenderResponse{
+		Id:             t.Id,
+		Name:           t.Name,
+		Description:    t.Description,
+		ServiceType:    t.ServiceType,
+		Status:         t.Status,
+		OrganizationId: t.OrganizationId,
+		Type:           t.Type,
+		Version:        t.Version,
+		CreatedAt:      t.CreatedAt,
+		Deadline:       t.Deadline,
+		Criteria:       t.Criteria,
+	}
+}
+
+func bidResponse(b bids.Bid) bids.BidResponse {
+	return bids.BidResponse{
+		Id:          b.Id,
+		Name:        b.Name,
+		Description: b.Description,
+		TenderId:    b.TenderId,
+		Status:      b.Status,
+		AuthorType:  b.AuthorType,
+		AuthorId:    b.AuthorId,
+		Version:     b.Version,
+		CreatedAt:   b.CreatedAt,
+		Price:       b.Price,
+	}
+}
+
+func page[T any](items []T, limit, offset int) []T {
+	result := make([]T, 0)
+	for i := offset; i < len(items) && len(result) < limit; i++ {
+		result = append(result, items[i])
+	}
+	return result
+}
+
+func (f *fixture) sortedTenders() []*tenderRow {
+	rows := make([]*tenderRow, 0, len(f.tenders))
+	for _, row := range f.tenders {
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *tenderRow) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+	return rows
+}
+
+func (f *fixture) sortedBids(keep func(*bidRow) bool) []bids.BidResponse {
+	result := make([]bids.BidResponse, 0)
+	for _, row := range f.bids {
+		if keep(row) {
+			result = append(result, bidResponse(row.Bid))
+		}
+	}
+	slices.SortFunc(result, func(a, b bids.BidResponse) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+	return result
+}
+
+func (f *fixture) FetchUser(username string) (user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	usr, ok := f.users[username]
+	if !ok {
+		return user.User{}, storage.ErrUserNotFound
+	}
+	return usr, nil
+}
+
+func (f *fixture) ReadMemberRoles(organizationId, userId string) ([]string, error) {
+	return f.members[organizationId][userId], nil
+}
+
+func (f *fixture) ReadUserOrganizations(userId string) ([]string, error) {
+	result := make([]string, 0)
+	for organizationId, members := range f.members {
+		if len(members[userId]) > 0 {
+			result = append(result, organizationId)
+		}
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+func (f *fixture) ListOrganizationMembers(organizationId string) ([]user.Member, error) {
+	result := make([]user.Member, 0)
+	for _, usr := range f.users {
+		if roles := f.members[organizationId][usr.Id]; len(roles) > 0 {
+			result = append(result, user.Member{UserId: usr.Id, Username: usr.Username, Roles: roles})
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) ReadTenders(limit, offset int, serviceType string) ([]tender.TenderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]tender.TenderResponse, 0)
+	for _, row := range f.sortedTenders() {
+		if serviceType == "" || row.ServiceType == serviceType {
+			result = append(result, tenderResponse(row.Tender))
+		}
+	}
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) ListCreatorTenders(username, organizationId string, limit, offset int) ([]tender.TenderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]tender.TenderResponse, 0)
+	for _, row := range f.sortedTenders() {
+		if row.Creator == username && (organizationId == "" || row.OrganizationId == organizationId) {
+			result = append(result, tenderResponse(row.Tender))
+		}
+	}
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) GetTender(tenderId string) (tender.Tender, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.tenders[tenderId]
+	if !ok {
+		return tender.Tender{}, storage.ErrNotFound
+	}
+	return row.Tender, nil
+}
+
+func (f *fixture) OrganizationExists(organizationId string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.organizations[organizationId]
+	return ok, nil
+}
+
+func (f *fixture) EmployeeExists(userId string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, usr := range f.users {
+		if usr.Id == userId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fixture) ReadResponsibleOrganizations(userId string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]string, 0)
+	for organizationId, userIds := range f.responsibles {
+		if slices.Contains(userIds, userId) {
+			result = append(result, organizationId)
+		}
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+func (f *fixture) SaveTender(req tender.TenderRequest) (tender.TenderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row := &tenderRow{
+		Tender: tender.Tender{
+			Id:             f.nextId(),
+			Name:           req.Name,
+			Description:    req.Description,
+			ServiceType:    req.ServiceType,
+			Status:         tenderdomain.Created,
+			Type:           req.Type,
+			OrganizationId: req.OrganizationId,
+			Version:        1,
+			CreatedAt:      time.Now().UTC(),
+			Deadline:       req.Deadline,
+			Criteria:       req.Criteria,
+		},
+		Creator: req.CreatorUsername,
+	}
+	f.tenders[row.Id] = row
+	return tenderResponse(row.Tender), nil
+}
+
+func (f *fixture) UpdateTenderStatus(tenderId, status, actor, reason string, guard func(from string, facts tenderdomain.Facts) error) (tender.TenderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.tenders[tenderId]
+	if !ok {
+		return tender.TenderResponse{}, storage.ErrNotFound
+	}
+	err := guard(row.Status, tenderdomain.Facts{})
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	row.History = append(row.History, row.Tender)
+	row.Status = status
+	row.Version++
+	return tenderResponse(row.Tender), nil
+}
+
+func (f *fixture) ReadTenderFacts(tenderId string) (tenderdomain.Facts, error) {
+	return tenderdomain.Facts{}, nil
+}
+
+func (f *fixture) ListTenderTransitions(tenderId string) ([]tender.TenderTransition, error) {
+	return make([]tender.TenderTransition, 0), nil
+}
+
+func (f *fixture) ChangeTenderStatus(tenderId, to, actor, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.tenders[tenderId]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	row.History = append(row.History, row.Tender)
+	row.Status = to
+	row.Version++
+	return nil
+}
+
+func (f *fixture) PatchTender(tenderId, name, description, serviceType string, deadline *time.Time) (tender.TenderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.tenders[tenderId]
+	if !ok {
+		return tender.TenderResponse{}, storage.ErrNotFound
+	}
+
+	row.History = append(row.History, row.Tender)
+	if name != "" {
+		row.Name = name
+	}
+	if description != "" {
+		row.Description = description
+	}
+	if serviceType != "" {
+		row.ServiceType = serviceType
+	}
+	if deadline != nil {
+		row.Deadline = deadline
+	}
+	row.Version++
+	return tenderResponse(row.Tender), nil
+}
+
+func (f *fixture) RollbackTender(tenderId string, version int) (tender.TenderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.tenders[tenderId]
+	if !ok || version <= 0 || version > len(row.History) {
+		return tender.TenderResponse{}, storage.ErrNotFound
+	}
+
+	old := row.History[version-1]
+	row.History = append(row.History, row.Tender)
+	row.Name, row.Description, row.ServiceType = old.Name, old.Description, old.ServiceType
+	row.Version++
+	return tenderResponse(row.Tender), nil
+}
+
+func (f *fixture) GetBid(bidId string) (bids.Bid, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.bids[bidId]
+	if !ok {
+		return bids.Bid{}, storage.ErrNotFound
+	}
+	return row.Bid, nil
+}
+
+// LockBid reads the bid. The fixture runs no transactions, so there is
+// nothing to hold a lock for.
+func (f *fixture) LockBid(bidId string) (bids.Bid, error) {
+	return f.GetBid(bidId)
+}
+
+func (f *fixture) SaveBid(req bids.BidRequest) (bids.BidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row := &bidRow{
+		Bid: bids.Bid{
+			Id:          f.nextId(),
+			Name:        req.Name,
+			Status:      biddomain.Draft,
+			Description: req.Description,
+			TenderId:    req.TenderId,
+			AuthorType:  req.AuthorType,
+			AuthorId:    req.AuthorId,
+			Price:       req.Price,
+			Version:     1,
+			CreatedAt:   time.Now().UTC(),
+		},
+		Votes: make(map[string]string),
+	}
+	f.bids[row.Id] = row
+	return bidResponse(row.Bid), nil
+}
+
+func (f *fixture) ChangeBidStatus(bidId, status string, guard func(from string) error) (bids.BidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.bids[bidId]
+	if !ok {
+		return bids.BidResponse{}, storage.ErrNotFound
+	}
+	err := guard(row.Status)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	row.History = append(row.History, row.Bid)
+	row.Status = status
+	row.Version++
+	return bidResponse(row.Bid), nil
+}
+
+func (f *fixture) EditBid(bidId, name, desc string, price *float64, outOfBudget bool) (bids.BidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.bids[bidId]
+	if !ok {
+		return bids.BidResponse{}, storage.ErrNotFound
+	}
+
+	row.History = append(row.History, row.Bid)
+	if name != "" {
+		row.Name = name
+	}
+	if desc != "" {
+		row.Description = desc
+	}
+	if price != nil {
+		row.Price = price
+	}
+	row.Version++
+	return bidResponse(row.Bid), nil
+}
+
+func (f *fixture) RollbackBid(bidId string, version int, guard func(current, restored *float64) error) (bids.BidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.bids[bidId]
+	if !ok || version <= 0 || version > len(row.History) {
+		return bids.BidResponse{}, storage.ErrNotFound
+	}
+
+	old := row.History[version-1]
+	err := guard(row.Price, old.Price)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	row.History = append(row.History, row.Bid)
+	row.Name, row.Description, row.Price = old.Name, old.Description, old.Price
+	row.Version++
+	return bidResponse(row.Bid), nil
+}
+
+func (f *fixture) ListTenderBids(tenderId string, authorIds []string, lotId string, limit, offset int) ([]bids.BidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return page(f.sortedBids(func(row *bidRow) bool {
+		return row.TenderId == tenderId && (authorIds == nil || slices.Contains(authorIds, row.AuthorId))
+	}), limit, offset), nil
+}
+
+func (f *fixture) ListAuthorBids(authorIds []string, limit, offset int) ([]bids.BidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return page(f.sortedBids(func(row *bidRow) bool {
+		return slices.Contains(authorIds, row.AuthorId)
+	}), limit, offset), nil
+}
+
+func (f *fixture) ListTenderBidAuthors(tenderId string) ([]bids.Author, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]bids.Author, 0)
+	for _, row := range f.bids {
+		author := bids.Author{Type: row.AuthorType, Id: row.AuthorId}
+		if row.TenderId == tenderId && !slices.Contains(result, author) {
+			result = append(result, author)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) SaveComment(c bids.Comment) (bids.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c.Id = f.nextId()
+	c.Author = f.username(c.AuthorId)
+	c.CreatedAt = time.Now().UTC()
+	f.comments = append(f.comments, c)
+	return c, nil
+}
+
+func (f *fixture) ReadAuthorFeedback(tenderId, authorId string, limit, offset int) ([]bids.BidReviewResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]bids.BidReviewResponse, 0)
+	for _, c := range f.comments {
+		row := f.bids[c.BidId]
+		if row.TenderId == tenderId && row.AuthorId == authorId && c.Side == biddomain.SideTender {
+			result = append(result, bids.BidReviewResponse{Id: c.Id, Description: c.Description, Rating: c.Rating, CreatedAt: c.CreatedAt})
+		}
+	}
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) HasVoted(bidId, lotId, userId string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.bids[bidId].Votes[userId]
+	return ok, nil
+}
+
+func (f *fixture) ReadDecision(bidId, lotId string) (bids.Decision, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.decision(f.bids[bidId]), nil
+}
+
+func (f *fixture) decision(row *bidRow) bids.Decision {
+	dec := bids.Decision{BidId: row.Id, Status: "Open"}
+	if row.Closed {
+		dec.Status = "Closed"
+	}
+	for _, vote := range row.Votes {
+		if vote == biddomain.Approved {
+			dec.NumApproved++
+		}
+	}
+	return dec
+}
+
+func (f *fixture) SaveVote(bidId, lotId, userId, username, decision string) (bids.Decision, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row := f.bids[bidId]
+	row.Votes[userId] = decision
+	return f.decision(row), nil
+}
+
+func (f *fixture) CloseDecision(bidId, lotId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.bids[bidId].Closed = true
+	return nil
+}
+
+func (f *fixture) DecideBid(bid *bids.BidResponse, decision string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row := f.bids[bid.Id]
+	row.History = append(row.History, row.Bid)
+	row.Status = decision
+	row.Version++
+	bid.Status, bid.Version = row.Status, row.Version
+	return nil
+}
+
+func (f *fixture) AwardBid(bid *bids.BidResponse, lotId, actor string, guard func(status string) error) (award.Outcome, error) {
+	f.mu.Lock()
+	status := f.tenders[bid.TenderId].Status
+	f.mu.Unlock()
+
+	err := guard(status)
+	if err != nil {
+		return award.Outcome{}, err
+	}
+
+	f.mu.Lock()
+	f.awards = append(f.awards, award.Award{Id: f.nextId(), TenderId: bid.TenderId, BidId: bid.Id, LotId: lotId, Approvers: []string{actor}})
+	f.mu.Unlock()
+
+	var result award.Outcome
+	err = f.CloseDecision(bid.Id, lotId)
+	if err != nil {
+		return award.Outcome{}, err
+	}
+	if bid.Status != biddomain.Approved {
+		err = f.DecideBid(bid, biddomain.Approved)
+		if err != nil {
+			return award.Outcome{}, err
+		}
+		result.BidApproved = true
+	}
+
+	err = f.ChangeTenderStatus(bid.TenderId, tenderdomain.Awarded, actor, fmt.Sprintf("bid %s approved", bid.Id))
+	if err != nil {
+		return award.Outcome{}, err
+	}
+	result.TenderAwarded = true
+	return result, nil
+}
+
+func (f *fixture) GetAward(tenderId, lotId string) (award.Award, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, aw := range f.awards {
+		if aw.TenderId == tenderId && aw.LotId == lotId {
+			return aw, nil
+		}
+	}
+	return award.Award{}, storage.ErrNotFound
+}
+
+func (f *fixture) SaveNotification(n notification.Notification) (notification.Notification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n.Id = f.nextId()
+	f.notifications = append(f.notifications, n)
+	return n, nil
+}
+
+func (f *fixture) AuditSubject(entityType, entityId string) (audit.Subject, error) {
+	return audit.Subject{}, storage.ErrNotFound
+}
+
+func (f *fixture) AppendAudit(e audit.Entry) (audit.Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var last audit.Entry
+	if len(f.audit) > 0 {
+		last = f.audit[len(f.audit)-1]
+	}
+	e = auditdomain.Chain(last, e)
+	f.audit = append(f.audit, e)
+	return e, nil
+}
+
+func (f *fixture) ListAudit(filter audit.Filter) ([]audit.Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]audit.Entry, 0)
+	for _, e := range f.audit {
+		if filter.OrganizationId != "" && e.OrganizationId != filter.OrganizationId ||
+			filter.EntityType != "" && e.EntityType != filter.EntityType ||
+			filter.EntityId != "" && e.EntityId != filter.EntityId ||
+			filter.Actor != "" && e.Actor != filter.Actor {
+			continue
+		}
+		result = append(result, e)
+	}
+	return page(result, filter.Limit, filter.Offset), nil
+}
+
+func (f *fixture) ReadAuditChain(afterId int64, limit int) ([]audit.Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]audit.Entry, 0)
+	for _, e := range f.audit {
+		if e.Id > afterId && len(result) < limit {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
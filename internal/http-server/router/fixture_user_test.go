@@ -0,0 +1,85 @@
+package router_test
+
+import (
+	"slices"
+	"tender_system/internal/models/job"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/storage"
+)
+
+// The per-user side of the fixture: notifications and their settings, and
+// the failed background jobs administrators inspect.
+
+func (f *fixture) ReadNotificationSettings(userId string) (notification.Settings, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	settings, ok := f.settings[userId]
+	if !ok {
+		return notification.DefaultSettings(), nil
+	}
+	return settings, nil
+}
+
+func (f *fixture) SaveNotificationSettings(userId string, settings notification.Settings) (notification.Settings, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if settings.Muted == nil {
+		settings.Muted = []string{}
+	}
+	f.settings[userId] = settings
+	return settings, nil
+}
+
+func (f *fixture) ListNotifications(userId string, unreadOnly bool, limit, offset int) ([]notification.Notification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]notification.Notification, 0)
+	for _, n := range f.notifications {
+		if n.UserId == userId && !(unreadOnly && n.Read) {
+			result = append(result, n)
+		}
+	}
+	return page(result, limit, offset), nil
+}
+
+func (f *fixture) MarkNotificationRead(userId, notificationId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := slices.IndexFunc(f.notifications, func(n notification.Notification) bool {
+		return n.UserId == userId && n.Id == notificationId
+	})
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	f.notifications[i].Read = true
+	return nil
+}
+
+func (f *fixture) MarkAllNotificationsRead(userId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.notifications {
+		if f.notifications[i].UserId == userId {
+			f.notifications[i].Read = true
+		}
+	}
+	return nil
+}
+
+func (f *fixture) ListFailed(kind string, limit, offset int) ([]job.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]job.Job, 0)
+	for _, j := range f.failedJobs {
+		if kind == "" || j.Kind == kind {
+			result = append(result, j)
+		}
+	}
+	return page(result, limit, offset), nil
+}
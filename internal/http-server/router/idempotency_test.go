@@ -0,0 +1,148 @@
+package router_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"tender_system/api"
+	"tender_system/internal/http-server/middleware/idempotency"
+	"tender_system/internal/lib/openapi"
+	"testing"
+	"time"
+)
+
+// TestIdempotencyKeys checks that a key only replays the response to the same
+// client retrying the same route, that uploads may carry one, and that a
+// request left in progress holds its key until its lease runs out.
+func TestIdempotencyKeys(t *testing.T) {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		peer, path string
+		// file, when set, is uploaded as the "file" part of a multipart
+		// body instead of a new tender by alice.
+		file     string
+		status   int
+		replayed bool
+		// hold, when set, leaves the key reserved after the request as if
+		// it were still in progress, its lease ending hold from now.
+		hold time.Duration
+	}
+	draftUpload := "/api/tenders/" + draftTender + "/attachments?username=" + alice
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			name: "retry by the same user",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: draftUpload, file: "offer", status: 200},
+				{peer: "192.0.2.2:1000", path: draftUpload, file: "offer", status: 200, replayed: true},
+			},
+		},
+		{
+			name: "same key from another user",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: "/api/bids/" + submittedBid + "/attachments?username=" + bob, file: "offer", status: 200},
+				{peer: "192.0.2.1:1000", path: "/api/bids/" + submittedBid + "/attachments?username=" + alice, file: "offer", status: 403},
+			},
+		},
+		{
+			name: "same key on another route",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: draftUpload, file: "offer", status: 200},
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 200},
+			},
+		},
+		{
+			name: "same key from another address",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 200},
+				{peer: "192.0.2.2:1000", path: "/api/tenders/new", status: 200},
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 200, replayed: true},
+			},
+		},
+		{
+			name: "retry while in progress",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 200, hold: time.Minute},
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 409},
+			},
+		},
+		{
+			name: "retry after the lease ran out",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 200, hold: -time.Second},
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", status: 200},
+			},
+		},
+		{
+			name: "upload over the in-memory limit",
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: draftUpload, file: strings.Repeat("offer ", 400<<10), status: 200},
+				{peer: "192.0.2.1:1000", path: draftUpload, file: strings.Repeat("offer ", 400<<10), status: 200, replayed: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, f := newServer(t, doc)
+
+			var first []byte
+			for i, req := range tt.requests {
+				var body bytes.Buffer
+				contentType := "application/json"
+				if req.file != "" {
+					// A retry sends the same bytes, boundary included.
+					form := multipart.NewWriter(&body)
+					form.SetBoundary("retry-boundary")
+					part, err := form.CreateFormFile("file", "offer.txt")
+					if err != nil {
+						t.Fatal(err)
+					}
+					io.WriteString(part, req.file)
+					form.Close()
+					contentType = form.FormDataContentType()
+				} else {
+					err := json.NewEncoder(&body).Encode(newTender(alice))
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				r := httptest.NewRequest(http.MethodPost, req.path, &body)
+				r.RemoteAddr = req.peer
+				r.Header.Set("Content-Type", contentType)
+				r.Header.Set(idempotency.Header, "retry-1")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, r)
+
+				if rec.Code != req.status {
+					t.Fatalf("request %d: status %d, want %d: %s", i, rec.Code, req.status, rec.Body.String())
+				}
+				if req.hold != 0 {
+					f.holdIdempotencyKeys(time.Now().Add(req.hold))
+				}
+				replayed := rec.Header().Get(idempotency.ReplayedHeader) != ""
+				if replayed != req.replayed {
+					t.Fatalf("request %d: replayed %t, want %t", i, replayed, req.replayed)
+				}
+				if i == 0 {
+					first = rec.Body.Bytes()
+					continue
+				}
+				if req.replayed != bytes.Equal(rec.Body.Bytes(), first) {
+					t.Fatalf("request %d: body %s after %s", i, rec.Body.Bytes(), first)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package router_test
+
+// organizationCases cover the organizations with their responsibles,
+// roles, conflict rules and tender templates.
+var organizationCases = []contractCase{
+	{name: "list organizations", method: "GET", path: "/organizations?limit=1", status: 200},
+	{name: "list organizations with a bad offset", method: "GET", path: "/organizations?offset=-1", status: 400},
+
+	{name: "create organization", method: "POST", path: "/organizations?username=" + carol, body: newOrganization(), status: 200},
+	{name: "create organization of an unknown type", method: "POST", path: "/organizations?username=" + carol, body: map[string]any{"name": "Carol & Co", "type": "Cooperative"}, status: 400},
+	{name: "create organization as an unknown user", method: "POST", path: "/organizations?username=" + nobody, body: newOrganization(), status: 401},
+
+	{name: "organization", method: "GET", path: "/organizations/" + buyerOrg, status: 200},
+	{name: "missing organization", method: "GET", path: "/organizations/" + missing, status: 404},
+
+	{name: "edit organization", method: "PATCH", path: "/organizations/" + buyerOrg + "?username=" + alice, body: map[string]any{"name": "Buyer Group"}, status: 200},
+	{name: "edit organization with an unknown field", method: "PATCH", path: "/organizations/" + buyerOrg + "?username=" + alice, body: map[string]any{"title": "Buyer Group"}, status: 400},
+	{name: "edit organization as an unknown user", method: "PATCH", path: "/organizations/" + buyerOrg + "?username=" + nobody, body: map[string]any{"name": "Buyer Group"}, status: 401},
+	{name: "edit organization as an outsider", method: "PATCH", path: "/organizations/" + buyerOrg + "?username=" + carol, body: map[string]any{"name": "Buyer Group"}, status: 403},
+	{name: "edit a missing organization", method: "PATCH", path: "/organizations/" + missing + "?username=" + alice, body: map[string]any{"name": "Buyer Group"}, status: 404},
+
+	{name: "delete organization", method: "DELETE", path: "/organizations/" + buyerOrg + "?username=" + alice, status: 204, setup: closeTenders},
+	{name: "delete organization with open tenders", method: "DELETE", path: "/organizations/" + buyerOrg + "?username=" + alice, status: 409},
+	{name: "delete organization as an unknown user", method: "DELETE", path: "/organizations/" + buyerOrg + "?username=" + nobody, status: 401},
+	{name: "delete organization as a bidder", method: "DELETE", path: "/organizations/" + supplierOrg + "?username=" + bob, status: 403},
+	{name: "delete a missing organization", method: "DELETE", path: "/organizations/" + missing + "?username=" + alice, status: 404},
+
+	{name: "responsibles", method: "GET", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice, status: 200},
+	{name: "responsibles with a bad limit", method: "GET", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice + "&limit=100", status: 400},
+	{name: "responsibles as an unknown user", method: "GET", path: "/organizations/" + buyerOrg + "/responsibles?username=" + nobody, status: 401},
+	{name: "responsibles as an outsider", method: "GET", path: "/organizations/" + buyerOrg + "/responsibles?username=" + carol, status: 403},
+	{name: "responsibles of a missing organization", method: "GET", path: "/organizations/" + missing + "/responsibles?username=" + alice, status: 404},
+
+	{name: "add responsible", method: "PUT", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice + "&targetUsername=" + carol, status: 200},
+	{name: "add responsible without a target", method: "PUT", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice, status: 400},
+	{name: "add responsible as an unknown user", method: "PUT", path: "/organizations/" + buyerOrg + "/responsibles?username=" + nobody + "&targetUsername=" + carol, status: 401},
+	{name: "add responsible as an outsider", method: "PUT", path: "/organizations/" + buyerOrg + "/responsibles?username=" + carol + "&targetUsername=" + carol, status: 403},
+	{name: "add an unknown responsible", method: "PUT", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice + "&targetUsername=" + nobody, status: 404},
+
+	{name: "remove responsible", method: "DELETE", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice + "&targetUsername=" + carol, status: 204, setup: func(f *fixture) {
+		f.responsibles[buyerOrg] = append(f.responsibles[buyerOrg], userId(carol))
+	}},
+	{name: "remove the last responsible", method: "DELETE", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice + "&targetUsername=" + alice, status: 409},
+	{name: "remove responsible without a target", method: "DELETE", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice, status: 400},
+	{name: "remove responsible as an unknown user", method: "DELETE", path: "/organizations/" + buyerOrg + "/responsibles?username=" + nobody + "&targetUsername=" + alice, status: 401},
+	{name: "remove responsible as an outsider", method: "DELETE", path: "/organizations/" + buyerOrg + "/responsibles?username=" + carol + "&targetUsername=" + alice, status: 403},
+	{name: "remove a user who is not responsible", method: "DELETE", path: "/organizations/" + buyerOrg + "/responsibles?username=" + alice + "&targetUsername=" + carol, status: 404},
+
+	{name: "roles", method: "GET", path: "/organizations/" + buyerOrg + "/roles?username=" + alice, status: 200},
+	{name: "roles as an unknown user", method: "GET", path: "/organizations/" + buyerOrg + "/roles?username=" + nobody, status: 401},
+	{name: "roles as an outsider", method: "GET", path: "/organizations/" + buyerOrg + "/roles?username=" + carol, status: 403},
+	{name: "roles of a missing organization", method: "GET", path: "/organizations/" + missing + "/roles?username=" + alice, status: 404},
+
+	{name: "grant role", method: "PUT", path: "/organizations/" + buyerOrg + "/roles?username=" + alice + "&targetUsername=" + carol + "&role=Reviewer", status: 204},
+	{name: "grant an unknown role", method: "PUT", path: "/organizations/" + buyerOrg + "/roles?username=" + alice + "&targetUsername=" + carol + "&role=Auditor", status: 400},
+	{name: "grant role as an unknown user", method: "PUT", path: "/organizations/" + buyerOrg + "/roles?username=" + nobody + "&targetUsername=" + carol + "&role=Reviewer", status: 401},
+	{name: "grant role as an outsider", method: "PUT", path: "/organizations/" + buyerOrg + "/roles?username=" + carol + "&targetUsername=" + carol + "&role=Reviewer", status: 403},
+	{name: "grant role to an unknown user", method: "PUT", path: "/organizations/" + buyerOrg + "/roles?username=" + alice + "&targetUsername=" + nobody + "&role=Reviewer", status: 404},
+
+	{name: "revoke role", method: "DELETE", path: "/organizations/" + supplierOrg + "/roles?username=" + alice + "&targetUsername=" + bob + "&role=Bidder", status: 204, setup: func(f *fixture) {
+		f.members[supplierOrg][userId(alice)] = []string{"Owner"}
+	}},
+	{name: "revoke an unknown role", method: "DELETE", path: "/organizations/" + buyerOrg + "/roles?username=" + alice + "&targetUsername=" + alice + "&role=Auditor", status: 400},
+	{name: "revoke role as an unknown user", method: "DELETE", path: "/organizations/" + supplierOrg + "/roles?username=" + nobody + "&targetUsername=" + bob + "&role=Bidder", status: 401},
+	{name: "revoke role as a bidder", method: "DELETE", path: "/organizations/" + supplierOrg + "/roles?username=" + bob + "&targetUsername=" + bob + "&role=Bidder", status: 403},
+	{name: "revoke a role the user does not hold", method: "DELETE", path: "/organizations/" + buyerOrg + "/roles?username=" + alice + "&targetUsername=" + carol + "&role=Viewer", status: 404},
+
+	{name: "conflict rules", method: "GET", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + alice, status: 200},
+	{name: "conflict rules as an unknown user", method: "GET", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + nobody, status: 401},
+	{name: "conflict rules as an outsider", method: "GET", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + carol, status: 403},
+
+	{name: "change conflict rules", method: "PUT", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + alice, body: conflictRules("member_bid", "Block"), status: 200},
+	{name: "change an unknown conflict rule", method: "PUT", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + alice, body: conflictRules("late_bid", "Block"), status: 400},
+	{name: "change conflict rules as an unknown user", method: "PUT", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + nobody, body: conflictRules("member_bid", "Block"), status: 401},
+	{name: "change conflict rules as an outsider", method: "PUT", path: "/organizations/" + buyerOrg + "/conflict_rules?username=" + carol, body: conflictRules("member_bid", "Block"), status: 403},
+
+	{name: "templates", method: "GET", path: "/organizations/" + buyerOrg + "/templates?username=" + alice, status: 200},
+	{name: "templates as an unknown user", method: "GET", path: "/organizations/" + buyerOrg + "/templates?username=" + nobody, status: 401},
+	{name: "templates as an outsider", method: "GET", path: "/organizations/" + buyerOrg + "/templates?username=" + carol, status: 403},
+
+	{name: "create template", method: "POST", path: "/organizations/" + buyerOrg + "/templates?username=" + alice, body: newTemplate(), status: 200},
+	{name: "create template without a title", method: "POST", path: "/organizations/" + buyerOrg + "/templates?username=" + alice, body: map[string]any{"spec": newTemplate()["spec"]}, status: 400},
+	{name: "create template as an unknown user", method: "POST", path: "/organizations/" + buyerOrg + "/templates?username=" + nobody, body: newTemplate(), status: 401},
+	{name: "create template as an outsider", method: "POST", path: "/organizations/" + buyerOrg + "/templates?username=" + carol, body: newTemplate(), status: 403},
+
+	{name: "template", method: "GET", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + alice, status: 200},
+	{name: "template as an unknown user", method: "GET", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + nobody, status: 401},
+	{name: "template as an outsider", method: "GET", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + carol, status: 403},
+	{name: "missing template", method: "GET", path: "/organizations/" + buyerOrg + "/templates/" + missing + "?username=" + alice, status: 404},
+
+	{name: "replace template", method: "PUT", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + alice, body: newTemplate(), status: 200},
+	{name: "replace template with an unknown service type", method: "PUT", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + alice, body: map[string]any{"title": "Catering", "spec": map[string]any{"name": "Lunch", "description": "Daily lunch", "serviceType": "Catering"}}, status: 400},
+	{name: "replace template as an unknown user", method: "PUT", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + nobody, body: newTemplate(), status: 401},
+	{name: "replace template as an outsider", method: "PUT", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + carol, body: newTemplate(), status: 403},
+	{name: "replace a missing template", method: "PUT", path: "/organizations/" + buyerOrg + "/templates/" + missing + "?username=" + alice, body: newTemplate(), status: 404},
+
+	{name: "delete template", method: "DELETE", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + alice, status: 204},
+	{name: "delete template as an unknown user", method: "DELETE", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + nobody, status: 401},
+	{name: "delete template as an outsider", method: "DELETE", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "?username=" + carol, status: 403},
+	{name: "delete a missing template", method: "DELETE", path: "/organizations/" + buyerOrg + "/templates/" + missing + "?username=" + alice, status: 404},
+
+	{name: "tender from template", method: "POST", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "/tenders?username=" + alice, body: map[string]any{"name": "Night cleaning"}, status: 200},
+	{name: "tender from template without overrides", method: "POST", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "/tenders?username=" + alice, status: 200},
+	{name: "tender from template with a bad deadline offset", method: "POST", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "/tenders?username=" + alice, body: map[string]any{"deadlineOffsetHours": 0}, status: 400},
+	{name: "tender from template as an unknown user", method: "POST", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "/tenders?username=" + nobody, status: 401},
+	{name: "tender from template as an outsider", method: "POST", path: "/organizations/" + buyerOrg + "/templates/" + cleaningTemplate + "/tenders?username=" + carol, status: 403},
+	{name: "tender from a missing template", method: "POST", path: "/organizations/" + buyerOrg + "/templates/" + missing + "/tenders?username=" + alice, status: 404},
+}
+
+func newOrganization() map[string]any {
+	return map[string]any{
+		"name":        "Carol & Co",
+		"description": "Cleaning services",
+		"type":        "IE",
+	}
+}
+
+func conflictRules(rule, action string) map[string]any {
+	return map[string]any{
+		"rules": []map[string]any{{"rule": rule, "action": action}},
+	}
+}
+
+func newTemplate() map[string]any {
+	return map[string]any{
+		"title": "Evening cleaning",
+		"spec": map[string]any{
+			"name":                "Office cleaning",
+			"description":         "Clean the office every evening",
+			"serviceType":         "Delivery",
+			"deadlineOffsetHours": 72,
+		},
+	}
+}
+
+// closeTenders awards every seeded tender, leaving nothing open.
+func closeTenders(f *fixture) {
+	for _, row := range f.tenders {
+		row.Status = "Awarded"
+	}
+}
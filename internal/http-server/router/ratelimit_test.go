@@ -0,0 +1,157 @@
+package router_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"tender_system/api"
+	"tender_system/internal/authz"
+	ratelimitdomain "tender_system/internal/domain/ratelimit"
+	"tender_system/internal/http-server/middleware/ratelimit"
+	"tender_system/internal/http-server/router"
+	"tender_system/internal/lib/openapi"
+	"testing"
+)
+
+// TestRateLimitKeys checks that what a client sends about itself never
+// buys it a fresh bucket.
+func TestRateLimitKeys(t *testing.T) {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	once := ratelimitdomain.Limit{Rate: 0.001, Burst: 1}
+
+	twice := ratelimitdomain.Limit{Rate: 0.001, Burst: 2}
+	proxy := netip.MustParsePrefix("10.0.0.0/8")
+
+	// A request lists the tenders of username unless it posts body to path.
+	type request struct {
+		peer, forwardedFor, organization, username string
+		path                                       string
+		body                                       any
+		status                                     int
+	}
+	tests := []struct {
+		name     string
+		policy   ratelimit.Policy
+		setup    func(f *fixture)
+		requests []request
+	}{
+		{
+			name:   "forwarded address from an untrusted peer",
+			policy: ratelimit.Policy{IP: once},
+			requests: []request{
+				{peer: "192.0.2.1:1000", forwardedFor: "198.51.100.1", username: alice, status: 200},
+				{peer: "192.0.2.1:1000", forwardedFor: "198.51.100.2", username: alice, status: 429},
+			},
+		},
+		{
+			name:   "forwarded address from a trusted proxy",
+			policy: ratelimit.Policy{IP: once},
+			requests: []request{
+				{peer: "10.0.0.1:1000", forwardedFor: "198.51.100.1", username: alice, status: 200},
+				{peer: "10.0.0.1:1000", forwardedFor: "198.51.100.2", username: alice, status: 200},
+				{peer: "10.0.0.1:1000", forwardedFor: "203.0.113.9, 198.51.100.1", username: alice, status: 429},
+			},
+		},
+		{
+			name:   "acting organization of someone else",
+			policy: ratelimit.Policy{User: once},
+			requests: []request{
+				{peer: "192.0.2.1:1000", username: alice, status: 200},
+				{peer: "192.0.2.2:1000", organization: supplierOrg, username: alice, status: 429},
+			},
+		},
+		{
+			name:   "organizations of a user acting for none",
+			policy: ratelimit.Policy{Organization: once},
+			requests: []request{
+				{peer: "192.0.2.1:1000", organization: buyerOrg, username: alice, status: 200},
+				{peer: "192.0.2.2:1000", username: alice, status: 429},
+			},
+		},
+		{
+			name:   "unknown users",
+			policy: ratelimit.Policy{User: once, IP: once},
+			requests: []request{
+				{peer: "192.0.2.1:1000", username: nobody, status: 401},
+				{peer: "192.0.2.1:1000", username: nobody + "2", status: 429},
+			},
+		},
+		{
+			name:   "bids of one author from several addresses",
+			policy: ratelimit.Policy{User: once},
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: "/api/bids/new", body: newBid(publishedTender, userId(bob)), status: 200},
+				{peer: "192.0.2.2:1000", path: "/api/bids/new", body: newBid(publishedTender, userId(bob)), status: 429},
+				{peer: "192.0.2.3:1000", path: "/api/bids/new", body: newBid(draftTender, userId(bob)), status: 429},
+			},
+		},
+		{
+			name:   "tenders of one creator from several addresses",
+			policy: ratelimit.Policy{User: once},
+			requests: []request{
+				{peer: "192.0.2.1:1000", path: "/api/tenders/new", body: newTender(alice), status: 200},
+				{peer: "192.0.2.2:1000", path: "/api/tenders/new", body: newTender(alice), status: 429},
+			},
+		},
+		{
+			name:   "throttled user and the organization bucket",
+			policy: ratelimit.Policy{User: once, Organization: twice},
+			setup: func(f *fixture) {
+				f.members[buyerOrg][userId(carol)] = []string{string(authz.Viewer)}
+			},
+			requests: []request{
+				{peer: "192.0.2.1:1000", username: alice, status: 200},
+				{peer: "192.0.2.1:1000", username: alice, status: 429},
+				{peer: "192.0.2.1:1000", username: alice, status: 429},
+				{peer: "192.0.2.2:1000", username: carol, status: 200},
+				{peer: "192.0.2.2:1000", username: carol, status: 429},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, f := newServerWith(t, router.Options{
+				Doc:            doc,
+				Spec:           api.OpenAPI,
+				TrustedProxies: []netip.Prefix{proxy},
+				LimitStore:     ratelimit.NewMemory(),
+				TendersLimit:   tt.policy,
+				BidsLimit:      tt.policy,
+			})
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			for i, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodGet, "/api/tenders/my?username="+req.username, nil)
+				if req.path != "" {
+					data, err := json.Marshal(req.body)
+					if err != nil {
+						t.Fatal(err)
+					}
+					r = httptest.NewRequest(http.MethodPost, req.path, bytes.NewReader(data))
+					r.Header.Set("Content-Type", "application/json")
+				}
+				r.RemoteAddr = req.peer
+				if req.forwardedFor != "" {
+					r.Header.Set("X-Forwarded-For", req.forwardedFor)
+				}
+				if req.organization != "" {
+					r.Header.Set("X-Organization-Id", req.organization)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, r)
+
+				if rec.Code != req.status {
+					t.Fatalf("request %d: status %d, want %d: %s", i, rec.Code, req.status, rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package router assembles the HTTP API: the middleware stack and the route
+// table shared by the server and its tests.
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"net/netip"
+	ratelimitdomain "tender_system/internal/domain/ratelimit"
+	"tender_system/internal/http-server/handlers/api/admin"
+	"tender_system/internal/http-server/handlers/api/attachment"
+	"tender_system/internal/http-server/handlers/api/auction"
+	"tender_system/internal/http-server/handlers/api/audit"
+	"tender_system/internal/http-server/handlers/api/award"
+	"tender_system/internal/http-server/handlers/api/bids"
+	"tender_system/internal/http-server/handlers/api/conflict"
+	"tender_system/internal/http-server/handlers/api/docs"
+	"tender_system/internal/http-server/handlers/api/employee"
+	"tender_system/internal/http-server/handlers/api/lot"
+	"tender_system/internal/http-server/handlers/api/notification"
+	"tender_system/internal/http-server/handlers/api/organization"
+	"tender_system/internal/http-server/handlers/api/ping"
+	"tender_system/internal/http-server/handlers/api/roles"
+	"tender_system/internal/http-server/handlers/api/schedule"
+	"tender_system/internal/http-server/handlers/api/supplier"
+	"tender_system/internal/http-server/handlers/api/template"
+	"tender_system/internal/http-server/handlers/api/tender"
+	"tender_system/internal/http-server/handlers/api/transfer"
+	"tender_system/internal/http-server/middleware/actingorg"
+	auditmw "tender_system/internal/http-server/middleware/audit"
+	"tender_system/internal/http-server/middleware/clientip"
+	"tender_system/internal/http-server/middleware/idempotency"
+	openapimw "tender_system/internal/http-server/middleware/openapi"
+	"tender_system/internal/http-server/middleware/ratelimit"
+	"tender_system/internal/lib/openapi"
+	attachmentmodel "tender_system/internal/models/attachment"
+	"tender_system/internal/service"
+	"tender_system/internal/storage/blob"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Storage is everything the handlers and middleware read or write directly,
+// bypassing the services.
+type Storage interface {
+	tender.TenderGetter
+	idempotency.Store
+	ratelimit.Resolver
+}
+
+// Services are the application services behind the routes.
+type Services struct {
+	Tender       *service.TenderService
+	Bid          *service.BidService
+	Role         *service.RoleService
+	Organization *service.OrganizationService
+	Employee     *service.EmployeeService
+	Lot          *service.LotService
+	Auction      *service.AuctionService
+	Audit        *service.AuditService
+	Notification *service.NotificationService
+	Supplier     *service.SupplierService
+	Conflict     *service.ConflictService
+	Template     *service.TemplateService
+	Schedule     *service.ScheduleService
+	Job          *service.JobService
+	Transfer     *service.TransferService
+	Attachment   *service.AttachmentService
+}
+
+// Options configure request validation and rate limiting.
+type Options struct {
+	// Doc validates requests, Spec is served as is at /api/openapi.yml.
+	Doc  *openapi.Document
+	Spec []byte
+
+	// TrustedProxies are the peers whose X-Forwarded-For is believed.
+	TrustedProxies []netip.Prefix
+
+	LimitStore    ratelimit.Store
+	TendersLimit  ratelimit.Policy
+	BidsLimit     ratelimit.Policy
+	AccountsLimit ratelimit.Policy
+	BulkLimit     ratelimit.Policy
+
+	// OnResponseViolation, when set, is called for JSON responses that do
+	// not match the document.
+	OnResponseViolation func(r *http.Request, err error)
+}
+
+// DefaultTendersLimit and DefaultBidsLimit are the rate limits applied to
+// the tender and bid routes unless configured otherwise. DefaultAccountsLimit
+// covers the organization, employee and notification routes, and
+// DefaultBulkLimit is charged on top for imports, exports and reports.
+var (
+	DefaultTendersLimit = ratelimit.Policy{
+		User:         ratelimitdomain.Limit{Rate: 2, Burst: 120},
+		Organization: ratelimitdomain.Limit{Rate: 10, Burst: 600},
+		IP:           ratelimitdomain.Limit{Rate: 5, Burst: 300},
+	}
+	DefaultBidsLimit = ratelimit.Policy{
+		User:         ratelimitdomain.Limit{Rate: 1, Burst: 60},
+		Organization: ratelimitdomain.Limit{Rate: 5, Burst: 300},
+		IP:           ratelimitdomain.Limit{Rate: 2, Burst: 120},
+	}
+	DefaultAccountsLimit = ratelimit.Policy{
+		User:         ratelimitdomain.Limit{Rate: 1, Burst: 60},
+		Organization: ratelimitdomain.Limit{Rate: 5, Burst: 300},
+		IP:           ratelimitdomain.Limit{Rate: 2, Burst: 120},
+	}
+	DefaultBulkLimit = ratelimit.Policy{
+		User:         ratelimitdomain.Limit{Rate: 1.0 / 60, Burst: 10},
+		Organization: ratelimitdomain.Limit{Rate: 1.0 / 12, Burst: 30},
+		IP:           ratelimitdomain.Limit{Rate: 1.0 / 30, Burst: 20},
+	}
+)
+
+// New returns the handler serving the whole API.
+func New(log *slog.Logger, st Storage, blobs blob.BlobStore, svc Services, opts Options) http.Handler {
+	tendersLimit := ratelimit.New(log, st, opts.LimitStore, "tenders", opts.TendersLimit)
+	bidsLimit := ratelimit.New(log, st, opts.LimitStore, "bids", opts.BidsLimit)
+	accountsLimit := ratelimit.New(log, st, opts.LimitStore, "accounts", opts.AccountsLimit)
+	bulkLimit := ratelimit.New(log, st, opts.LimitStore, "bulk", opts.BulkLimit)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, clientip.New(opts.TrustedProxies))
+	r.Use(openapimw.New(log, opts.Doc, openapimw.Options{
+		Prefix:              "/api",
+		Unchecked:           []string{"username", "authorUsername", "requesterUsername"},
+		OnResponseViolation: opts.OnResponseViolation,
+	}))
+
+	r.With(tendersLimit).Get("/api/tenders", tender.NewGetTenders(log, st))
+	r.Route("/api", func(r chi.Router) {
+		// r.Post("/", )
+		r.Use(actingorg.New())
+		r.Use(idempotency.New(log, st, st, idempotency.DefaultTTL, idempotency.DefaultLease))
+		r.Use(auditmw.New())
+		r.Get("/ping", ping.New(log))
+		r.Get("/openapi.yml", docs.NewGetSpec(log, opts.Spec))
+		r.Get("/docs", docs.NewGetSwaggerUI(log, "/api/openapi.yml"))
+		r.With(accountsLimit).Get("/me", employee.NewGetMe(log, svc.Employee))
+		r.With(accountsLimit).Get("/audit", audit.NewGetAudit(log, svc.Audit))
+		r.With(accountsLimit, bulkLimit).Get("/audit/verify", audit.NewGetAuditVerification(log, svc.Audit))
+		r.With(accountsLimit).Get("/suppliers/{authorId}", supplier.NewGetProfile(log, svc.Supplier))
+		r.With(accountsLimit).Get("/admin/jobs/failed", admin.NewGetFailedJobs(log, svc.Job))
+		r.Route("/tenders", func(r chi.Router) {
+			r.Use(tendersLimit)
+			r.Post("/new", tender.NewPostTender(log, svc.Tender))
+			r.Get("/my", tender.NewGetMyTenders(log, svc.Tender))
+			r.With(bulkLimit).Get("/export", transfer.NewGetTenderExport(log, svc.Transfer))
+			r.With(bulkLimit).Post("/import", transfer.NewPostTenderImport(log, svc.Transfer))
+			r.Get("/{tenderId}/status", tender.NewGetTenderStatus(log, svc.Tender))
+			r.Put("/{tenderId}/status", tender.NewPutTenderStatus(log, svc.Tender))
+			r.Get("/{tenderId}/transitions", tender.NewGetTenderTransitions(log, svc.Tender))
+			r.Get("/{tenderId}/budget_report", tender.NewGetBudgetReport(log, svc.Tender))
+			r.With(bulkLimit).Get("/{tenderId}/report.xlsx", tender.NewGetCommitteeReport(log, svc.Tender))
+			r.Get("/{tenderId}/award", award.NewGetAward(log, svc.Supplier))
+			r.Get("/{tenderId}/award/contract", award.NewGetContract(log, svc.Supplier))
+			r.Put("/{tenderId}/award/delivery", award.NewPutDelivery(log, svc.Supplier))
+			r.Post("/{tenderId}/clone", tender.NewPostCloneTender(log, svc.Tender))
+			r.Put("/{tenderId}/publication", schedule.NewPutPublication(log, svc.Schedule))
+			r.Get("/{tenderId}/recurrence", schedule.NewGetRecurrence(log, svc.Schedule))
+			r.Put("/{tenderId}/recurrence", schedule.NewPutRecurrence(log, svc.Schedule))
+			r.Delete("/{tenderId}/recurrence", schedule.NewDeleteRecurrence(log, svc.Schedule))
+			r.Put("/{tenderId}/recusal", conflict.NewPutRecusal(log, svc.Conflict))
+			r.Get("/{tenderId}/recusals", conflict.NewGetRecusals(log, svc.Conflict))
+			r.Patch("/{tenderId}/edit", tender.NewPatchTender(log, svc.Tender))
+			r.Get("/{tenderId}/auction", auction.NewGetAuction(log, svc.Auction))
+			r.Get("/{tenderId}/lots", lot.NewGetLots(log, svc.Lot))
+			r.Post("/{tenderId}/lots", lot.NewPostLot(log, svc.Lot))
+			r.Patch("/{tenderId}/lots/{lotId}", lot.NewPatchLot(log, svc.Lot))
+			r.Delete("/{tenderId}/lots/{lotId}", lot.NewDeleteLot(log, svc.Lot))
+			r.Put("/{tenderId}/rollback/{version}", tender.NewRollbackTender(log, svc.Tender))
+			r.Post("/{tenderId}/attachments", attachment.NewPostAttachment(log, attachmentmodel.Tender, svc.Attachment, blobs))
+			r.Get("/{tenderId}/attachments", attachment.NewGetAttachments(log, attachmentmodel.Tender, svc.Attachment))
+			r.Get("/{tenderId}/attachments/{attachmentId}", attachment.NewDownloadAttachment(log, attachmentmodel.Tender, svc.Attachment, blobs))
+			r.Delete("/{tenderId}/attachments/{attachmentId}", attachment.NewDeleteAttachment(log, attachmentmodel.Tender, svc.Attachment))
+		})
+		r.Route("/bids", func(r chi.Router) {
+			r.Use(bidsLimit)
+			r.Post("/new", bids.NewPostBid(log, svc.Bid))
+			r.Get("/my", bids.NewGetMyBids(log, svc.Bid))
+			r.With(bulkLimit).Get("/export", transfer.NewGetBidExport(log, svc.Transfer))
+			r.With(bulkLimit).Post("/import", transfer.NewPostBidImport(log, svc.Transfer))
+			r.Get("/{tenderId}/list", bids.NewGetTenderBids(log, svc.Bid))
+			r.Get("/{bidId}/status", bids.NewGetBidStatus(log, svc.Bid))
+			r.Put("/{bidId}/status", bids.NewPutBidStatus(log, svc.Bid))
+			r.Patch("/{bidId}/edit", bids.NewPatchBid(log, svc.Bid))
+			r.Put("/{bidId}/feedback", bids.NewPutBidFeedback(log, svc.Bid))
+			r.Get("/{bidId}/feedback", bids.NewGetBidThread(log, svc.Bid))
+			r.Post("/{bidId}/feedback", bids.NewPostBidComment(log, svc.Bid))
+			r.Patch("/{bidId}/feedback/{commentId}", bids.NewPatchBidComment(log, svc.Bid))
+			r.Delete("/{bidId}/feedback/{commentId}", bids.NewDeleteBidComment(log, svc.Bid))
+			r.Put("/{bidId}/rollback/{version}", bids.NewRollbackBid(log, svc.Bid))
+			r.Get("/{tenderId}/reviews", bids.NewReadBidFeedback(log, svc.Bid))
+			r.Put("/{bidId}/submit_decision", bids.NewPutBidDecision(log, svc.Bid))
+			r.Post("/{bidId}/attachments", attachment.NewPostAttachment(log, attachmentmodel.Bid, svc.Attachment, blobs))
+			r.Get("/{bidId}/attachments", attachment.NewGetAttachments(log, attachmentmodel.Bid, svc.Attachment))
+			r.Get("/{bidId}/attachments/{attachmentId}", attachment.NewDownloadAttachment(log, attachmentmodel.Bid, svc.Attachment, blobs))
+			r.Delete("/{bidId}/attachments/{attachmentId}", attachment.NewDeleteAttachment(log, attachmentmodel.Bid, svc.Attachment))
+		})
+		r.Route("/organizations", func(r chi.Router) {
+			r.Use(accountsLimit)
+			r.Get("/", organization.NewGetOrganizations(log, svc.Organization))
+			r.Post("/", organization.NewPostOrganization(log, svc.Organization))
+			r.Get("/{organizationId}", organization.NewGetOrganization(log, svc.Organization))
+			r.Patch("/{organizationId}", organization.NewPatchOrganization(log, svc.Organization))
+			r.Delete("/{organizationId}", organization.NewDeleteOrganization(log, svc.Organization))
+			r.Get("/{organizationId}/responsibles", organization.NewGetResponsibles(log, svc.Organization))
+			r.Put("/{organizationId}/responsibles", organization.NewPutResponsible(log, svc.Organization))
+			r.Delete("/{organizationId}/responsibles", organization.NewDeleteResponsible(log, svc.Organization))
+			r.Get("/{organizationId}/roles", roles.NewGetRoles(log, svc.Role))
+			r.Put("/{organizationId}/roles", roles.NewPutRole(log, svc.Role))
+			r.Delete("/{organizationId}/roles", roles.NewDeleteRole(log, svc.Role))
+			r.Get("/{organizationId}/conflict_rules", conflict.NewGetRules(log, svc.Conflict))
+			r.Put("/{organizationId}/conflict_rules", conflict.NewPutRules(log, svc.Conflict))
+			r.Get("/{organizationId}/templates", template.NewGetTemplates(log, svc.Template))
+			r.Post("/{organizationId}/templates", template.NewPostTemplate(log, svc.Template))
+			r.Get("/{organizationId}/templates/{templateId}", template.NewGetTemplate(log, svc.Template))
+			r.Put("/{organizationId}/templates/{templateId}", template.NewPutTemplate(log, svc.Template))
+			r.Delete("/{organizationId}/templates/{templateId}", template.NewDeleteTemplate(log, svc.Template))
+			r.Post("/{organizationId}/templates/{templateId}/tenders", template.NewPostTenderFromTemplate(log, svc.Template))
+		})
+		r.Route("/notifications", func(r chi.Router) {
+			r.Use(accountsLimit)
+			r.Get("/", notification.NewGetNotifications(log, svc.Notification))
+			r.Put("/read", notification.NewPutReadAll(log, svc.Notification))
+			r.Put("/{notificationId}/read", notification.NewPutRead(log, svc.Notification))
+			r.Get("/settings", notification.NewGetSettings(log, svc.Notification))
+			r.Put("/settings", notification.NewPutSettings(log, svc.Notification))
+		})
+		r.Route("/employees", func(r chi.Router) {
+			r.Use(accountsLimit)
+			r.Get("/", employee.NewGetEmployees(log, svc.Employee))
+			r.Post("/", employee.NewPostEmployee(log, svc.Employee))
+			r.Get("/{employeeUsername}", employee.NewGetEmployee(log, svc.Employee))
+			r.Patch("/{employeeUsername}", employee.NewPatchEmployee(log, svc.Employee))
+			r.Delete("/{employeeUsername}", employee.NewDeleteEmployee(log, svc.Employee))
+		})
+	})
+
+	return r
+}
@@ -0,0 +1,439 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"tender_system/api"
+	"tender_system/internal/http-server/middleware/actingorg"
+	"tender_system/internal/http-server/middleware/ratelimit"
+	"tender_system/internal/http-server/router"
+	"tender_system/internal/lib/openapi"
+	"tender_system/internal/notify"
+	"tender_system/internal/service"
+	"tender_system/internal/storage/blob"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// contractCase is one request against a documented operation and the
+// status it must be answered with.
+type contractCase struct {
+	name   string
+	method string
+	// path is relative to the server URL and may carry a query.
+	path   string
+	body   any
+	status int
+	// file, when set, is the name attachmentContent is uploaded under as the
+	// "file" part of a multipart body instead of body.
+	file string
+	// raw, when set, is sent as is with contentType instead of body.
+	raw         string
+	contentType string
+	// organization, when set, is sent as the X-Organization-Id header.
+	organization string
+	// setup, when set, changes the seeded fixture before the request.
+	setup func(f *fixture)
+}
+
+// requiredStatuses are the documented outcomes every operation must have a
+// case for. 400 is left out since most of it is the request validation
+// shared by all operations.
+var requiredStatuses = []int{http.StatusOK, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}
+
+var cases = []contractCase{
+	{name: "ping", method: "GET", path: "/ping", status: 200},
+	{name: "API document", method: "GET", path: "/openapi.yml", status: 200},
+	{name: "API docs", method: "GET", path: "/docs", status: 200},
+
+	{name: "list tenders", method: "GET", path: "/tenders?limit=1&offset=1", status: 200},
+	{name: "list tenders by service type", method: "GET", path: "/tenders?service_type=Construction", status: 200},
+	{name: "list tenders with a bad limit", method: "GET", path: "/tenders?limit=many", status: 400},
+	{name: "list tenders with an unknown service type", method: "GET", path: "/tenders?service_type=Catering", status: 400},
+
+	{name: "create tender", method: "POST", path: "/tenders/new", body: newTender(alice), status: 200},
+	{name: "create tender as an unknown user", method: "POST", path: "/tenders/new", body: newTender(nobody), status: 401},
+	{name: "create tender as an outsider", method: "POST", path: "/tenders/new", body: newTender(carol), status: 403},
+	{name: "create tender without a name", method: "POST", path: "/tenders/new", body: map[string]any{"description": "x", "serviceType": "Delivery", "organizationId": buyerOrg, "creatorUsername": alice}, status: 400},
+
+	{name: "my tenders", method: "GET", path: "/tenders/my?username=" + alice, status: 200},
+	{name: "my tenders without a username", method: "GET", path: "/tenders/my", status: 401},
+	{name: "my tenders as an unknown user", method: "GET", path: "/tenders/my?username=" + nobody, status: 401},
+	{name: "my tenders for an organization of others", method: "GET", path: "/tenders/my?username=" + alice + "&organizationId=" + supplierOrg, status: 403},
+	{name: "my tenders for the acting organization", method: "GET", path: "/tenders/my?username=" + alice, organization: buyerOrg, status: 200},
+	{name: "my tenders for a malformed acting organization", method: "GET", path: "/tenders/my?username=" + alice, organization: malformedId, status: 400},
+
+	{name: "published tender status", method: "GET", path: "/tenders/" + publishedTender + "/status", status: 200},
+	{name: "draft tender status", method: "GET", path: "/tenders/" + draftTender + "/status?username=" + alice, status: 200},
+	{name: "draft tender status as an unknown user", method: "GET", path: "/tenders/" + draftTender + "/status?username=" + nobody, status: 401},
+	{name: "draft tender status as an outsider", method: "GET", path: "/tenders/" + draftTender + "/status?username=" + carol, status: 403},
+	{name: "status of a missing tender", method: "GET", path: "/tenders/" + missing + "/status?username=" + alice, status: 404},
+	{name: "status of a malformed tender id", method: "GET", path: "/tenders/" + malformedId + "/status?username=" + alice, status: 400},
+
+	{name: "publish tender", method: "PUT", path: "/tenders/" + draftTender + "/status?status=Published&username=" + alice, status: 200},
+	{name: "set an unknown tender status", method: "PUT", path: "/tenders/" + draftTender + "/status?status=Done&username=" + alice, status: 400},
+	{name: "publish tender as an unknown user", method: "PUT", path: "/tenders/" + draftTender + "/status?status=Published&username=" + nobody, status: 401},
+	{name: "publish tender as an outsider", method: "PUT", path: "/tenders/" + draftTender + "/status?status=Published&username=" + carol, status: 403},
+	{name: "publish a missing tender", method: "PUT", path: "/tenders/" + missing + "/status?status=Published&username=" + alice, status: 404},
+
+	{name: "edit tender", method: "PATCH", path: "/tenders/" + draftTender + "/edit?username=" + alice, body: map[string]any{"name": "Large warehouse"}, status: 200},
+	{name: "edit tender with nothing to change", method: "PATCH", path: "/tenders/" + draftTender + "/edit?username=" + alice, body: map[string]any{}, status: 400},
+	{name: "edit tender with an unknown field", method: "PATCH", path: "/tenders/" + draftTender + "/edit?username=" + alice, body: map[string]any{"title": "Warehouse"}, status: 400},
+	{name: "edit tender as an unknown user", method: "PATCH", path: "/tenders/" + draftTender + "/edit?username=" + nobody, body: map[string]any{"name": "Large warehouse"}, status: 401},
+	{name: "edit tender as an outsider", method: "PATCH", path: "/tenders/" + draftTender + "/edit?username=" + carol, body: map[string]any{"name": "Large warehouse"}, status: 403},
+	{name: "edit a missing tender", method: "PATCH", path: "/tenders/" + missing + "/edit?username=" + alice, body: map[string]any{"name": "Large warehouse"}, status: 404},
+
+	{name: "roll back tender", method: "PUT", path: "/tenders/" + publishedTender + "/rollback/1?username=" + alice, status: 200},
+	{name: "roll back tender to version zero", method: "PUT", path: "/tenders/" + publishedTender + "/rollback/0?username=" + alice, status: 400},
+	{name: "roll back tender to a future version", method: "PUT", path: "/tenders/" + publishedTender + "/rollback/7?username=" + alice, status: 400},
+	{name: "roll back tender as an unknown user", method: "PUT", path: "/tenders/" + publishedTender + "/rollback/1?username=" + nobody, status: 401},
+	{name: "roll back tender as an outsider", method: "PUT", path: "/tenders/" + publishedTender + "/rollback/1?username=" + carol, status: 403},
+	{name: "roll back a missing tender", method: "PUT", path: "/tenders/" + missing + "/rollback/1?username=" + alice, status: 404},
+
+	{name: "create bid", method: "POST", path: "/bids/new", body: newBid(publishedTender, userId(bob)), status: 200},
+	{name: "create bid with an unknown author type", method: "POST", path: "/bids/new", body: map[string]any{"name": "Bid", "description": "x", "tenderId": publishedTender, "authorType": "Team", "authorId": userId(bob)}, status: 400},
+	{name: "create bid as an unknown user", method: "POST", path: "/bids/new", body: newBid(publishedTender, userId(nobody)), status: 401},
+	{name: "create bid as a user without organization", method: "POST", path: "/bids/new", body: newBid(publishedTender, userId(carol)), status: 403},
+	{name: "create bid on a missing tender", method: "POST", path: "/bids/new", body: newBid(missing, userId(bob)), status: 404},
+
+	{name: "my bids", method: "GET", path: "/bids/my?username=" + bob, status: 200},
+	{name: "my bids as an unknown user", method: "GET", path: "/bids/my?username=" + nobody, status: 401},
+	{name: "my bids for an organization of others", method: "GET", path: "/bids/my?username=" + bob + "&organizationId=" + buyerOrg, status: 403},
+
+	{name: "tender bids", method: "GET", path: "/bids/" + publishedTender + "/list?username=" + alice, status: 200},
+	{name: "tender bids as the bidder", method: "GET", path: "/bids/" + publishedTender + "/list?username=" + bob, status: 200},
+	{name: "tender bids with a negative offset", method: "GET", path: "/bids/" + publishedTender + "/list?username=" + alice + "&offset=-1", status: 400},
+	{name: "tender bids as an unknown user", method: "GET", path: "/bids/" + publishedTender + "/list?username=" + nobody, status: 401},
+	{name: "tender bids as an outsider", method: "GET", path: "/bids/" + publishedTender + "/list?username=" + carol, status: 403},
+	{name: "bids of a missing tender", method: "GET", path: "/bids/" + missing + "/list?username=" + alice, status: 404},
+
+	{name: "bid status", method: "GET", path: "/bids/" + submittedBid + "/status?username=" + bob, status: 200},
+	{name: "bid status as the tender creator", method: "GET", path: "/bids/" + submittedBid + "/status?username=" + alice, status: 200},
+	{name: "bid status as an unknown user", method: "GET", path: "/bids/" + submittedBid + "/status?username=" + nobody, status: 401},
+	{name: "bid status as an outsider", method: "GET", path: "/bids/" + submittedBid + "/status?username=" + carol, status: 403},
+	{name: "status of a missing bid", method: "GET", path: "/bids/" + missing + "/status?username=" + bob, status: 404},
+
+	{name: "withdraw bid", method: "PUT", path: "/bids/" + submittedBid + "/status?status=Withdrawn&username=" + bob, status: 200},
+	{name: "move bid back to draft", method: "PUT", path: "/bids/" + submittedBid + "/status?status=Draft&username=" + bob, status: 409},
+	{name: "set an unknown bid status", method: "PUT", path: "/bids/" + submittedBid + "/status?status=Lost&username=" + bob, status: 400},
+	{name: "withdraw bid as an unknown user", method: "PUT", path: "/bids/" + submittedBid + "/status?status=Withdrawn&username=" + nobody, status: 401},
+	{name: "withdraw bid as an outsider", method: "PUT", path: "/bids/" + submittedBid + "/status?status=Withdrawn&username=" + carol, status: 403},
+	{name: "withdraw a missing bid", method: "PUT", path: "/bids/" + missing + "/status?status=Withdrawn&username=" + bob, status: 404},
+
+	{name: "edit bid", method: "PATCH", path: "/bids/" + submittedBid + "/edit?username=" + bob, body: map[string]any{"name": "Daily delivery"}, status: 200},
+	{name: "edit bid with nothing to change", method: "PATCH", path: "/bids/" + submittedBid + "/edit?username=" + bob, body: map[string]any{}, status: 400},
+	{name: "edit bid as an unknown user", method: "PATCH", path: "/bids/" + submittedBid + "/edit?username=" + nobody, body: map[string]any{"name": "Daily delivery"}, status: 401},
+	{name: "edit bid as the tender creator", method: "PATCH", path: "/bids/" + submittedBid + "/edit?username=" + alice, body: map[string]any{"name": "Daily delivery"}, status: 403},
+	{name: "edit bid as an outsider", method: "PATCH", path: "/bids/" + submittedBid + "/edit?username=" + carol, body: map[string]any{"name": "Daily delivery"}, status: 403},
+	{name: "edit a missing bid", method: "PATCH", path: "/bids/" + missing + "/edit?username=" + bob, body: map[string]any{"name": "Daily delivery"}, status: 404},
+
+	{name: "leave feedback", method: "PUT", path: "/bids/" + submittedBid + "/feedback?bidFeedback=On+time&username=" + alice, status: 200},
+	{name: "leave empty feedback", method: "PUT", path: "/bids/" + submittedBid + "/feedback?username=" + alice, status: 400},
+	{name: "leave feedback as an unknown user", method: "PUT", path: "/bids/" + submittedBid + "/feedback?bidFeedback=On+time&username=" + nobody, status: 401},
+	{name: "leave feedback as an outsider", method: "PUT", path: "/bids/" + submittedBid + "/feedback?bidFeedback=On+time&username=" + carol, status: 403},
+	{name: "leave feedback on a missing bid", method: "PUT", path: "/bids/" + missing + "/feedback?bidFeedback=On+time&username=" + alice, status: 404},
+
+	{name: "roll back bid", method: "PUT", path: "/bids/" + submittedBid + "/rollback/1?username=" + bob, status: 200},
+	{name: "roll back bid to the current version", method: "PUT", path: "/bids/" + submittedBid + "/rollback/2?username=" + bob, status: 400},
+	{name: "roll back bid as an unknown user", method: "PUT", path: "/bids/" + submittedBid + "/rollback/1?username=" + nobody, status: 401},
+	{name: "roll back bid to a future version", method: "PUT", path: "/bids/" + submittedBid + "/rollback/3?username=" + bob, status: 400},
+	{name: "roll back bid as an outsider", method: "PUT", path: "/bids/" + submittedBid + "/rollback/1?username=" + carol, status: 403},
+	{name: "roll back a missing bid", method: "PUT", path: "/bids/" + missing + "/rollback/1?username=" + bob, status: 404},
+
+	{name: "approve bid", method: "PUT", path: "/bids/" + submittedBid + "/submit_decision?decision=Approved&username=" + alice, status: 200},
+	{name: "reject bid", method: "PUT", path: "/bids/" + submittedBid + "/submit_decision?decision=Rejected&username=" + alice, status: 200},
+	{name: "submit an unknown decision", method: "PUT", path: "/bids/" + submittedBid + "/submit_decision?decision=Maybe&username=" + alice, status: 400},
+	{name: "decide as an unknown user", method: "PUT", path: "/bids/" + submittedBid + "/submit_decision?decision=Approved&username=" + nobody, status: 401},
+	{name: "decide as an outsider", method: "PUT", path: "/bids/" + submittedBid + "/submit_decision?decision=Approved&username=" + carol, status: 403},
+	{name: "decide on a missing bid", method: "PUT", path: "/bids/" + missing + "/submit_decision?decision=Approved&username=" + alice, status: 404},
+
+	{name: "reviews", method: "GET", path: "/bids/" + publishedTender + "/reviews?authorUsername=" + bob + "&requesterUsername=" + alice, status: 200},
+	{name: "own reviews", method: "GET", path: "/bids/" + publishedTender + "/reviews?authorUsername=" + bob + "&requesterUsername=" + bob, status: 200},
+	{name: "reviews of an unknown author", method: "GET", path: "/bids/" + publishedTender + "/reviews?authorUsername=" + nobody + "&requesterUsername=" + alice, status: 401},
+	{name: "reviews as an outsider", method: "GET", path: "/bids/" + publishedTender + "/reviews?authorUsername=" + bob + "&requesterUsername=" + carol, status: 403},
+	{name: "reviews of a missing tender", method: "GET", path: "/bids/" + missing + "/reviews?authorUsername=" + bob + "&requesterUsername=" + alice, status: 404},
+}
+
+func newTender(creator string) map[string]any {
+	return map[string]any{
+		"name":            "Office cleaning",
+		"description":     "Clean the office every evening",
+		"serviceType":     "Delivery",
+		"organizationId":  buyerOrg,
+		"creatorUsername": creator,
+	}
+}
+
+func newBid(tenderId, authorId string) map[string]any {
+	return map[string]any{
+		"name":        "Evening cleaning",
+		"description": "Two cleaners from 7 pm",
+		"tenderId":    tenderId,
+		"authorType":  "User",
+		"authorId":    authorId,
+	}
+}
+
+// newServer serves the API from a freshly seeded fixture.
+func newServer(t *testing.T, doc *openapi.Document) (http.Handler, *fixture) {
+	t.Helper()
+
+	return newServerWith(t, router.Options{
+		Doc:        doc,
+		Spec:       api.OpenAPI,
+		LimitStore: ratelimit.NewMemory(),
+	})
+}
+
+// newServerWith is newServer with the options spelled out.
+func newServerWith(t *testing.T, opts router.Options) (http.Handler, *fixture) {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f := newFixture()
+	blobs, err := blob.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, att := range f.attachments {
+		err = blobs.Put(context.Background(), att.StorageKey, strings.NewReader(attachmentContent), att.Size, att.ContentType)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	notifier := notify.New(log, f, nil)
+	tenders := service.NewTenderService(f, f.authz, notifier)
+	handler := router.New(log, f, blobs, router.Services{
+		Tender:       tenders,
+		Bid:          service.NewBidService(f, f.authz, notifier),
+		Role:         service.NewRoleService(f, f.authz),
+		Organization: service.NewOrganizationService(f, f.authz),
+		Employee:     service.NewEmployeeService(f),
+		Lot:          service.NewLotService(f, f.authz),
+		Auction:      service.NewAuctionService(f, f.authz, notifier),
+		Audit:        service.NewAuditService(f, f.authz),
+		Notification: service.NewNotificationService(f),
+		Supplier:     service.NewSupplierService(f, f.authz),
+		Conflict:     service.NewConflictService(f, f.authz),
+		Template:     service.NewTemplateService(f, f.authz),
+		Schedule:     service.NewScheduleService(f, f.authz, tenders),
+		Job:          service.NewJobService(f, f, []string{alice}),
+		Transfer:     service.NewTransferService(f, f.authz, f),
+		Attachment:   service.NewAttachmentService(f, f.authz),
+	}, opts)
+
+	return handler, f
+}
+
+func TestContract(t *testing.T) {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	covered := make(map[*openapi.Operation][]int)
+	for _, c := range slices.Concat(cases, tenderCases, bidCases, organizationCases, employeeCases) {
+		target, err := url.Parse(c.path)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		op, _, ok := doc.Find(c.method, target.Path)
+		if !ok {
+			t.Errorf("%s: %s %s is not a documented operation", c.name, c.method, target.Path)
+			continue
+		}
+		if _, ok := op.Responses[strconv.Itoa(c.status)]; !ok && c.status != http.StatusBadRequest {
+			t.Errorf("%s: %d is not documented for %s %s", c.name, c.status, op.Method, op.Path)
+		}
+		covered[op] = append(covered[op], c.status)
+
+		t.Run(c.name, func(t *testing.T) {
+			handler, f := newServer(t, doc)
+			if c.setup != nil {
+				c.setup(f)
+			}
+
+			var body io.Reader
+			contentType := "application/json"
+			switch {
+			case c.file != "":
+				var buf bytes.Buffer
+				form := multipart.NewWriter(&buf)
+				part, err := form.CreateFormFile("file", c.file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				io.WriteString(part, attachmentContent)
+				form.Close()
+				body, contentType = &buf, form.FormDataContentType()
+			case c.raw != "":
+				body, contentType = strings.NewReader(c.raw), c.contentType
+			case c.body != nil:
+				data, err := json.Marshal(c.body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = bytes.NewReader(data)
+			}
+
+			req := httptest.NewRequest(c.method, "/api"+c.path, body)
+			if body != nil {
+				req.Header.Set("Content-Type", contentType)
+			}
+			if c.organization != "" {
+				req.Header.Set(actingorg.Header, c.organization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != c.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, c.status, rec.Body.String())
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+				if rec.Code >= 400 {
+					t.Fatalf("error response is %q, not JSON", rec.Header().Get("Content-Type"))
+				}
+				return
+			}
+
+			err := op.ValidateResponse(rec.Code, rec.Body.Bytes())
+			if err != nil {
+				t.Errorf("response violates the document: %v\n%s", err, rec.Body.String())
+			}
+
+			if rec.Code >= 400 {
+				var resp struct {
+					Reason *string `json:"reason"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &resp)
+				if err != nil || resp.Reason == nil || len(*resp.Reason) < 5 {
+					t.Errorf("error response has no reason: %s", rec.Body.String())
+				}
+			}
+		})
+	}
+
+	for _, op := range doc.Operations() {
+		if len(covered[op]) == 0 {
+			t.Errorf("%s %s has no contract cases", op.Method, op.Path)
+			continue
+		}
+		for _, status := range requiredStatuses {
+			_, documented := op.Responses[strconv.Itoa(status)]
+			if documented && !slices.Contains(covered[op], status) {
+				t.Errorf("%s %s has no case for its documented %d response", op.Method, op.Path, status)
+			}
+		}
+	}
+}
+
+// TestRoutesDocumented checks that every API route the router serves is an
+// operation of the document, so TestContract cannot miss one.
+func TestRoutesDocumented(t *testing.T) {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, _ := newServer(t, doc)
+	routes, ok := handler.(chi.Routes)
+	if !ok {
+		t.Fatalf("router is a %T, not a chi router", handler)
+	}
+
+	served := 0
+	err = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path, ok := strings.CutPrefix(route, "/api")
+		if !ok {
+			return nil
+		}
+		served++
+		if _, _, ok := doc.Find(method, path); !ok {
+			t.Errorf("%s %s is not documented", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if served != len(doc.Operations()) {
+		t.Errorf("the router serves %d API routes, the document has %d operations", served, len(doc.Operations()))
+	}
+}
+
+// TestContractStateChanges checks that successful writes are visible to the
+// documented reads that follow them.
+func TestContractStateChanges(t *testing.T) {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, f := newServer(t, doc)
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewReader(data)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/api"+path, reader))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s %s: status %d: %s", method, path, rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	rec := do("POST", "/tenders/new", newTender(alice))
+	var created struct {
+		Id             string `json:"id"`
+		Status         string `json:"status"`
+		OrganizationId string `json:"organizationId"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != "Created" || created.OrganizationId != buyerOrg {
+		t.Fatalf("created tender %+v", created)
+	}
+
+	do("PUT", "/tenders/"+created.Id+"/status?status=Published&username="+alice, nil)
+	rec = do("GET", "/tenders/"+created.Id+"/status", nil)
+	if got := strings.TrimSpace(rec.Body.String()); got != `"Published"` {
+		t.Fatalf("status after publishing is %s", got)
+	}
+
+	do("PUT", "/bids/"+submittedBid+"/submit_decision?decision=Approved&username="+alice, nil)
+	rec = do("GET", "/bids/"+submittedBid+"/status?username="+bob, nil)
+	if got := strings.TrimSpace(rec.Body.String()); got != `"Approved"` {
+		t.Fatalf("bid status after approval is %s", got)
+	}
+	if got := f.tenders[publishedTender].Status; got != "Awarded" {
+		t.Fatalf("tender status after approval is %s", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("PUT", "/api/bids/"+submittedBid+"/submit_decision?decision=Rejected&username="+alice, nil))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("deciding on a bid of an awarded tender: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(f.notifications) == 0 {
+		t.Fatal("approving the bid notified nobody")
+	}
+	if len(f.audit) == 0 {
+		t.Fatal("no audit entries were written")
+	}
+}
@@ -0,0 +1,214 @@
+package router_test
+
+import (
+	"fmt"
+	"strings"
+	"tender_system/internal/authz"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/organization"
+	"time"
+)
+
+// tenderCases cover the tender routes beyond creating and editing tenders:
+// reports, awards, schedules, recusals, auctions, lots, attachments and bulk
+// transfers.
+var tenderCases = []contractCase{
+	{name: "tender transitions", method: "GET", path: "/tenders/" + publishedTender + "/transitions?username=" + alice, status: 200},
+	{name: "tender transitions as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/transitions?username=" + nobody, status: 401},
+	{name: "tender transitions as an outsider", method: "GET", path: "/tenders/" + publishedTender + "/transitions?username=" + carol, status: 403},
+	{name: "transitions of a missing tender", method: "GET", path: "/tenders/" + missing + "/transitions?username=" + alice, status: 404},
+
+	{name: "budget report", method: "GET", path: "/tenders/" + publishedTender + "/budget_report?username=" + alice, status: 200},
+	{name: "budget report as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/budget_report?username=" + nobody, status: 401},
+	{name: "budget report as the bidder", method: "GET", path: "/tenders/" + publishedTender + "/budget_report?username=" + bob, status: 403},
+	{name: "budget report of a missing tender", method: "GET", path: "/tenders/" + missing + "/budget_report?username=" + alice, status: 404},
+
+	{name: "committee report", method: "GET", path: "/tenders/" + publishedTender + "/report.xlsx?username=" + alice, status: 200},
+	{name: "committee report as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/report.xlsx?username=" + nobody, status: 401},
+	{name: "committee report as an outsider", method: "GET", path: "/tenders/" + publishedTender + "/report.xlsx?username=" + carol, status: 403},
+	{name: "committee report of a missing tender", method: "GET", path: "/tenders/" + missing + "/report.xlsx?username=" + alice, status: 404},
+
+	{name: "award", method: "GET", path: "/tenders/" + publishedTender + "/award?username=" + alice, status: 200, setup: awardSubmittedBid},
+	{name: "award as the winner", method: "GET", path: "/tenders/" + publishedTender + "/award?username=" + bob, status: 200, setup: awardSubmittedBid},
+	{name: "award as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/award?username=" + nobody, status: 401, setup: awardSubmittedBid},
+	{name: "award as an outsider", method: "GET", path: "/tenders/" + publishedTender + "/award?username=" + carol, status: 403, setup: awardSubmittedBid},
+	{name: "award of a tender without one", method: "GET", path: "/tenders/" + publishedTender + "/award?username=" + alice, status: 404},
+
+	{name: "contract", method: "GET", path: "/tenders/" + publishedTender + "/award/contract?username=" + alice, status: 200, setup: awardSubmittedBid},
+	{name: "contract in HTML", method: "GET", path: "/tenders/" + publishedTender + "/award/contract?format=html&username=" + bob, status: 200, setup: awardSubmittedBid},
+	{name: "contract in an unknown format", method: "GET", path: "/tenders/" + publishedTender + "/award/contract?format=pdf&username=" + alice, status: 400, setup: awardSubmittedBid},
+	{name: "contract as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/award/contract?username=" + nobody, status: 401, setup: awardSubmittedBid},
+	{name: "contract as an outsider", method: "GET", path: "/tenders/" + publishedTender + "/award/contract?username=" + carol, status: 403, setup: awardSubmittedBid},
+	{name: "contract of a tender without an award", method: "GET", path: "/tenders/" + publishedTender + "/award/contract?username=" + alice, status: 404},
+
+	{name: "record delivery", method: "PUT", path: "/tenders/" + publishedTender + "/award/delivery?username=" + alice, body: map[string]any{"onTime": true}, status: 200, setup: awardSubmittedBid},
+	{name: "record delivery without the outcome", method: "PUT", path: "/tenders/" + publishedTender + "/award/delivery?username=" + alice, body: map[string]any{}, status: 400, setup: awardSubmittedBid},
+	{name: "record delivery as an unknown user", method: "PUT", path: "/tenders/" + publishedTender + "/award/delivery?username=" + nobody, body: map[string]any{"onTime": true}, status: 401, setup: awardSubmittedBid},
+	{name: "record delivery as the winner", method: "PUT", path: "/tenders/" + publishedTender + "/award/delivery?username=" + bob, body: map[string]any{"onTime": true}, status: 403, setup: awardSubmittedBid},
+	{name: "record delivery of a tender without an award", method: "PUT", path: "/tenders/" + publishedTender + "/award/delivery?username=" + alice, body: map[string]any{"onTime": false}, status: 404},
+
+	{name: "clone tender", method: "POST", path: "/tenders/" + publishedTender + "/clone?username=" + alice, status: 200},
+	{name: "clone tender under a new name", method: "POST", path: "/tenders/" + publishedTender + "/clone?name=Office+delivery+2025&username=" + alice, status: 200},
+	{name: "clone tender under a name too long", method: "POST", path: "/tenders/" + publishedTender + "/clone?name=" + longName + "&username=" + alice, status: 400},
+	{name: "clone tender as an unknown user", method: "POST", path: "/tenders/" + publishedTender + "/clone?username=" + nobody, status: 401},
+	{name: "clone tender as an outsider", method: "POST", path: "/tenders/" + publishedTender + "/clone?username=" + carol, status: 403},
+	{name: "clone a missing tender", method: "POST", path: "/tenders/" + missing + "/clone?username=" + alice, status: 404},
+
+	{name: "schedule publication", method: "PUT", path: "/tenders/" + draftTender + "/publication?username=" + alice, body: map[string]any{"publishAt": tomorrow()}, status: 200},
+	{name: "cancel publication", method: "PUT", path: "/tenders/" + draftTender + "/publication?username=" + alice, body: map[string]any{"publishAt": nil}, status: 200},
+	{name: "schedule publication in the past", method: "PUT", path: "/tenders/" + draftTender + "/publication?username=" + alice, body: map[string]any{"publishAt": "2020-01-01T00:00:00Z"}, status: 400},
+	{name: "schedule publication as an unknown user", method: "PUT", path: "/tenders/" + draftTender + "/publication?username=" + nobody, body: map[string]any{"publishAt": tomorrow()}, status: 401},
+	{name: "schedule publication as an outsider", method: "PUT", path: "/tenders/" + draftTender + "/publication?username=" + carol, body: map[string]any{"publishAt": tomorrow()}, status: 403},
+	{name: "schedule publication of a missing tender", method: "PUT", path: "/tenders/" + missing + "/publication?username=" + alice, body: map[string]any{"publishAt": tomorrow()}, status: 404},
+	{name: "schedule publication of a published tender", method: "PUT", path: "/tenders/" + publishedTender + "/publication?username=" + alice, body: map[string]any{"publishAt": tomorrow()}, status: 409},
+
+	{name: "recurrence", method: "GET", path: "/tenders/" + publishedTender + "/recurrence?username=" + alice, status: 200},
+	{name: "recurrence as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/recurrence?username=" + nobody, status: 401},
+	{name: "recurrence as an outsider", method: "GET", path: "/tenders/" + publishedTender + "/recurrence?username=" + carol, status: 403},
+	{name: "recurrence of a tender without one", method: "GET", path: "/tenders/" + draftTender + "/recurrence?username=" + alice, status: 404},
+
+	{name: "set recurrence", method: "PUT", path: "/tenders/" + draftTender + "/recurrence?username=" + alice, body: map[string]any{"frequency": "Quarterly", "publish": true}, status: 200},
+	{name: "set recurrence with an unknown frequency", method: "PUT", path: "/tenders/" + draftTender + "/recurrence?username=" + alice, body: map[string]any{"frequency": "Weekly"}, status: 400},
+	{name: "set recurrence as an unknown user", method: "PUT", path: "/tenders/" + draftTender + "/recurrence?username=" + nobody, body: map[string]any{"frequency": "Monthly"}, status: 401},
+	{name: "set recurrence as an outsider", method: "PUT", path: "/tenders/" + draftTender + "/recurrence?username=" + carol, body: map[string]any{"frequency": "Monthly"}, status: 403},
+	{name: "set recurrence of a missing tender", method: "PUT", path: "/tenders/" + missing + "/recurrence?username=" + alice, body: map[string]any{"frequency": "Monthly"}, status: 404},
+
+	{name: "stop recurrence", method: "DELETE", path: "/tenders/" + publishedTender + "/recurrence?username=" + alice, status: 204},
+	{name: "stop recurrence as an unknown user", method: "DELETE", path: "/tenders/" + publishedTender + "/recurrence?username=" + nobody, status: 401},
+	{name: "stop recurrence as an outsider", method: "DELETE", path: "/tenders/" + publishedTender + "/recurrence?username=" + carol, status: 403},
+	{name: "stop a recurrence that was never set", method: "DELETE", path: "/tenders/" + draftTender + "/recurrence?username=" + alice, status: 404},
+
+	{name: "recuse", method: "PUT", path: "/tenders/" + publishedTender + "/recusal?username=" + alice, body: map[string]any{"reason": "My brother works for the bidder"}, status: 200},
+	{name: "recuse without a reason", method: "PUT", path: "/tenders/" + publishedTender + "/recusal?username=" + alice, body: map[string]any{}, status: 400},
+	{name: "recuse as an unknown user", method: "PUT", path: "/tenders/" + publishedTender + "/recusal?username=" + nobody, body: map[string]any{"reason": "Conflict"}, status: 401},
+	{name: "recuse as the bidder", method: "PUT", path: "/tenders/" + publishedTender + "/recusal?username=" + bob, body: map[string]any{"reason": "Conflict"}, status: 403},
+	{name: "recuse from a missing tender", method: "PUT", path: "/tenders/" + missing + "/recusal?username=" + alice, body: map[string]any{"reason": "Conflict"}, status: 404},
+	{name: "recuse after voting", method: "PUT", path: "/tenders/" + publishedTender + "/recusal?username=" + alice, body: map[string]any{"reason": "Conflict"}, status: 409, setup: func(f *fixture) {
+		f.bids[submittedBid].Votes[userId(alice)] = "Approved"
+	}},
+
+	{name: "recusals", method: "GET", path: "/tenders/" + publishedTender + "/recusals?username=" + alice, status: 200},
+	{name: "recusals as an unknown user", method: "GET", path: "/tenders/" + publishedTender + "/recusals?username=" + nobody, status: 401},
+	{name: "recusals as the bidder", method: "GET", path: "/tenders/" + publishedTender + "/recusals?username=" + bob, status: 403},
+	{name: "recusals of a missing tender", method: "GET", path: "/tenders/" + missing + "/recusals?username=" + alice, status: 404},
+
+	{name: "auction", method: "GET", path: "/tenders/" + publishedTender + "/auction", status: 200, setup: startAuction},
+	{name: "auction of a draft as an unknown user", method: "GET", path: "/tenders/" + draftTender + "/auction?username=" + nobody, status: 401},
+	{name: "auction of a draft as an outsider", method: "GET", path: "/tenders/" + draftTender + "/auction?username=" + carol, status: 403},
+	{name: "auction of a standard tender", method: "GET", path: "/tenders/" + publishedTender + "/auction", status: 404},
+
+	{name: "lots", method: "GET", path: "/tenders/" + draftTender + "/lots?username=" + alice, status: 200},
+	{name: "lots as an unknown user", method: "GET", path: "/tenders/" + draftTender + "/lots?username=" + nobody, status: 401},
+	{name: "lots of a draft as an outsider", method: "GET", path: "/tenders/" + draftTender + "/lots?username=" + carol, status: 403},
+	{name: "lots of a missing tender", method: "GET", path: "/tenders/" + missing + "/lots?username=" + alice, status: 404},
+
+	{name: "add lot", method: "POST", path: "/tenders/" + draftTender + "/lots?username=" + alice, body: newLot(), status: 200},
+	{name: "add lot without a name", method: "POST", path: "/tenders/" + draftTender + "/lots?username=" + alice, body: map[string]any{"description": "Walls", "serviceType": "Construction"}, status: 400},
+	{name: "add lot as an unknown user", method: "POST", path: "/tenders/" + draftTender + "/lots?username=" + nobody, body: newLot(), status: 401},
+	{name: "add lot as an outsider", method: "POST", path: "/tenders/" + draftTender + "/lots?username=" + carol, body: newLot(), status: 403},
+	{name: "add lot to a missing tender", method: "POST", path: "/tenders/" + missing + "/lots?username=" + alice, body: newLot(), status: 404},
+	{name: "add lot to a published tender", method: "POST", path: "/tenders/" + publishedTender + "/lots?username=" + alice, body: newLot(), status: 409},
+
+	{name: "edit lot", method: "PATCH", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + alice, body: map[string]any{"budget": 50000}, status: 200},
+	{name: "edit lot with a negative budget", method: "PATCH", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + alice, body: map[string]any{"budget": -1}, status: 400},
+	{name: "edit lot as an unknown user", method: "PATCH", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + nobody, body: map[string]any{"budget": 50000}, status: 401},
+	{name: "edit lot as an outsider", method: "PATCH", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + carol, body: map[string]any{"budget": 50000}, status: 403},
+	{name: "edit a missing lot", method: "PATCH", path: "/tenders/" + draftTender + "/lots/" + missing + "?username=" + alice, body: map[string]any{"budget": 50000}, status: 404},
+	{name: "edit a lot of a published tender", method: "PATCH", path: "/tenders/" + publishedTender + "/lots/" + foundationLot + "?username=" + alice, body: map[string]any{"budget": 50000}, status: 409},
+
+	{name: "remove lot", method: "DELETE", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + alice, status: 204},
+	{name: "remove lot as an unknown user", method: "DELETE", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + nobody, status: 401},
+	{name: "remove lot as an outsider", method: "DELETE", path: "/tenders/" + draftTender + "/lots/" + foundationLot + "?username=" + carol, status: 403},
+	{name: "remove a missing lot", method: "DELETE", path: "/tenders/" + draftTender + "/lots/" + missing + "?username=" + alice, status: 404},
+	{name: "remove a lot of a published tender", method: "DELETE", path: "/tenders/" + publishedTender + "/lots/" + foundationLot + "?username=" + alice, status: 409},
+
+	{name: "tender attachments", method: "GET", path: "/tenders/" + publishedTender + "/attachments?username=" + alice, status: 200},
+	{name: "attachments of a published tender as an outsider", method: "GET", path: "/tenders/" + publishedTender + "/attachments?username=" + carol, status: 200},
+	{name: "tender attachments as an unknown user", method: "GET", path: "/tenders/" + draftTender + "/attachments?username=" + nobody, status: 401},
+	{name: "attachments of a draft tender as an outsider", method: "GET", path: "/tenders/" + draftTender + "/attachments?username=" + carol, status: 403},
+	{name: "attachments of a missing tender", method: "GET", path: "/tenders/" + missing + "/attachments?username=" + alice, status: 404},
+
+	{name: "attach file to tender", method: "POST", path: "/tenders/" + publishedTender + "/attachments?username=" + alice, file: "drawings.txt", status: 200},
+	{name: "attach nothing to tender", method: "POST", path: "/tenders/" + publishedTender + "/attachments?username=" + alice, body: map[string]any{}, status: 400},
+	{name: "attach file to tender as an unknown user", method: "POST", path: "/tenders/" + publishedTender + "/attachments?username=" + nobody, file: "drawings.txt", status: 401},
+	{name: "attach file to tender as an outsider", method: "POST", path: "/tenders/" + publishedTender + "/attachments?username=" + carol, file: "drawings.txt", status: 403},
+	{name: "attach file to a missing tender", method: "POST", path: "/tenders/" + missing + "/attachments?username=" + alice, file: "drawings.txt", status: 404},
+
+	{name: "download tender attachment", method: "GET", path: "/tenders/" + publishedTender + "/attachments/" + specAttachment + "?username=" + carol, status: 200},
+	{name: "download tender attachment as an unknown user", method: "GET", path: "/tenders/" + draftTender + "/attachments/" + specAttachment + "?username=" + nobody, status: 401},
+	{name: "download attachment of a draft tender as an outsider", method: "GET", path: "/tenders/" + draftTender + "/attachments/" + specAttachment + "?username=" + carol, status: 403},
+	{name: "download a missing tender attachment", method: "GET", path: "/tenders/" + publishedTender + "/attachments/" + missing + "?username=" + alice, status: 404},
+
+	{name: "remove tender attachment", method: "DELETE", path: "/tenders/" + publishedTender + "/attachments/" + specAttachment + "?username=" + alice, status: 204},
+	{name: "remove tender attachment as an unknown user", method: "DELETE", path: "/tenders/" + publishedTender + "/attachments/" + specAttachment + "?username=" + nobody, status: 401},
+	{name: "remove tender attachment as an outsider", method: "DELETE", path: "/tenders/" + publishedTender + "/attachments/" + specAttachment + "?username=" + carol, status: 403},
+	{name: "remove a missing tender attachment", method: "DELETE", path: "/tenders/" + publishedTender + "/attachments/" + missing + "?username=" + alice, status: 404},
+
+	{name: "export tenders", method: "GET", path: "/tenders/export?username=" + alice, status: 200},
+	{name: "export tenders of an organization", method: "GET", path: "/tenders/export?format=jsonl&organizationId=" + buyerOrg + "&username=" + alice, status: 200},
+	{name: "export tenders in an unknown format", method: "GET", path: "/tenders/export?format=xml&username=" + alice, status: 400},
+	{name: "export tenders as an unknown user", method: "GET", path: "/tenders/export?username=" + nobody, status: 401},
+	{name: "export tenders of a foreign organization", method: "GET", path: "/tenders/export?organizationId=" + supplierOrg + "&username=" + alice, status: 403},
+
+	{name: "import tenders", method: "POST", path: "/tenders/import?format=jsonl&username=" + alice, raw: importedTender(importOrg), contentType: "application/x-ndjson", status: 200, setup: addImportTargets},
+	{name: "import tenders with an invalid row", method: "POST", path: "/tenders/import?format=jsonl&username=" + alice, raw: importedTender("org-buyer"), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import tenders already awarded", method: "POST", path: "/tenders/import?format=jsonl&username=" + alice, raw: strings.Replace(importedTender(importOrg), "{", `{"status":"Awarded","version":3,`, 1), contentType: "application/x-ndjson", status: 200, setup: addImportTargets},
+	{name: "import tenders in an unknown status", method: "POST", path: "/tenders/import?format=jsonl&username=" + alice, raw: strings.Replace(importedTender(importOrg), "{", `{"status":"Archived",`, 1), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import tenders with an invalid version", method: "POST", path: "/tenders/import?format=jsonl&username=" + alice, raw: strings.Replace(importedTender(importOrg), "{", `{"version":-1,`, 1), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import tenders created by an outsider", method: "POST", path: "/tenders/import?format=jsonl&username=" + alice, raw: strings.Replace(importedTender(importOrg), "{", `{"creatorUsername":"`+carol+`",`, 1), contentType: "application/x-ndjson", status: 400, setup: addImportTargets},
+	{name: "import tenders as an unknown user", method: "POST", path: "/tenders/import?format=jsonl&username=" + nobody, raw: importedTender(importOrg), contentType: "application/x-ndjson", status: 401, setup: addImportTargets},
+}
+
+// longName is one character over the name limit of tenders and bids.
+const longName = "Lorem-ipsum-dolor-sit-amet-consectetur-adipiscing-elit-sed-do-eiusmod-tempor-incididunt-ut-labore-etc"
+
+func tomorrow() string {
+	return time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+}
+
+func newLot() map[string]any {
+	return map[string]any{
+		"name":        "Walls",
+		"description": "Raise the walls",
+		"serviceType": "Construction",
+		"budget":      120000,
+	}
+}
+
+// startAuction turns the published tender into a reverse auction that has
+// not started yet.
+func startAuction(f *fixture) {
+	f.tenders[publishedTender].Type = "ReverseAuction"
+	f.auctions[publishedTender] = auction.Auction{
+		TenderId: publishedTender,
+		Settings: auction.Settings{StartPrice: 1500, MinDecrement: 50, RoundSeconds: 60, MaxRounds: 3},
+		State:    auction.State{Status: "Pending"},
+	}
+}
+
+// Imported rows name organizations and tenders by UUID, which the seeded ones
+// are not, so addImportTargets adds importOrg, owned by alice and bidding
+// through bob, and importTender, a published tender of the buying
+// organization.
+const (
+	importOrg    = "8d5b1c7e-2f4a-4b6e-9c3d-1a2b3c4d5e6f"
+	importTender = "4e3d2c1b-6a5f-4e8d-b7c6-5f4e3d2c1b0a"
+)
+
+func addImportTargets(f *fixture) {
+	f.organizations[importOrg] = organization.Organization{Id: importOrg, Name: "Importer", Type: "LLC"}
+	f.members[importOrg] = map[string][]string{
+		userId(alice): {string(authz.Owner)},
+		userId(bob):   {string(authz.Bidder)},
+	}
+	row := *f.tenders[publishedTender]
+	row.Tender.Id = importTender
+	row.History = nil
+	f.tenders[importTender] = &row
+}
+
+// importedTender is a JSON Lines file with one new tender of the
+// organization.
+func importedTender(organizationId string) string {
+	return fmt.Sprintf(`{"name":"Window cleaning","description":"Clean the windows every spring","serviceType":"Delivery","organizationId":%q}`+"\n", organizationId)
+}
@@ -0,0 +1,103 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"tender_system/api"
+	"tender_system/internal/authz"
+	"tender_system/internal/lib/openapi"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/organization"
+	"tender_system/internal/models/tender"
+	"testing"
+	"time"
+)
+
+// The history moved by TestTransferRoundTrip: a closed and an awarded tender
+// of historyOrg and the decided and withdrawn bids importOrg placed on them.
+const (
+	historyOrg    = "2c9a7e51-8b3d-4f60-a1e2-6d5c4b3a2f10"
+	closedTender  = "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	awardedTender = "0f9e8d7c-6b5a-4c3d-9e2f-1a0b9c8d7e6f"
+	rejectedBid   = "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a"
+	withdrawnBid  = "9b8a7f6e-5d4c-4b3a-9f8e-7d6c5b4a3f2e"
+	approvedBid   = "3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b"
+)
+
+// addHistoryOrg adds the organization the history belongs to, owned by alice,
+// next to importOrg, which alice and bob bid for.
+func addHistoryOrg(f *fixture) {
+	addImportTargets(f)
+	f.organizations[historyOrg] = organization.Organization{Id: historyOrg, Name: "History", Type: "LLC"}
+	f.members[historyOrg] = map[string][]string{userId(alice): {string(authz.Owner)}}
+}
+
+func addHistory(f *fixture) {
+	created := time.Date(2023, 3, 1, 9, 30, 0, 0, time.UTC)
+	price := 1250.5
+	for _, t := range []tender.Tender{
+		{Id: closedTender, Name: "Roof repair", Description: "Fix the roof", ServiceType: "Construction", Status: "Closed", Version: 2},
+		{Id: awardedTender, Name: "Paper supply", Description: "A year of paper", ServiceType: "Delivery", Status: "Awarded", Version: 3},
+	} {
+		t.OrganizationId, t.CreatedAt = historyOrg, created
+		f.tenders[t.Id] = &tenderRow{Tender: t, Creator: alice}
+	}
+	for _, b := range []bids.Bid{
+		{Id: rejectedBid, Name: "Cheap roof", TenderId: closedTender, Status: "Rejected", Version: 2},
+		{Id: withdrawnBid, Name: "Late roof", TenderId: closedTender, Status: "Withdrawn", Version: 1},
+		{Id: approvedBid, Name: "Paper", TenderId: awardedTender, Status: "Approved", Version: 1, Price: &price},
+	} {
+		b.Description, b.AuthorType, b.AuthorId, b.CreatedAt = "Offer", "Organization", importOrg, created
+		f.bids[b.Id] = &bidRow{Bid: b, Votes: make(map[string]string)}
+	}
+}
+
+// TestTransferRoundTrip checks that tenders and bids past their initial
+// status import from their own export into another installation unchanged.
+func TestTransferRoundTrip(t *testing.T) {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(handler http.Handler, method, path, body string) string {
+		t.Helper()
+
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/x-ndjson")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s %s: status %d: %s", method, path, rec.Code, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+	exportTenders := "/api/tenders/export?format=jsonl&organizationId=" + historyOrg + "&username=" + alice
+	exportBids := "/api/bids/export?format=jsonl&organizationId=" + historyOrg + "&username=" + alice
+
+	source, f := newServer(t, doc)
+	addHistoryOrg(f)
+	addHistory(f)
+	tenders := call(source, http.MethodGet, exportTenders, "")
+	bidRows := call(source, http.MethodGet, exportBids, "")
+	for _, status := range []string{"Closed", "Awarded", "Rejected", "Withdrawn", "Approved"} {
+		if !strings.Contains(tenders+bidRows, `"status":"`+status+`"`) {
+			t.Fatalf("no %s row exported:\n%s%s", status, tenders, bidRows)
+		}
+	}
+
+	target, f := newServer(t, doc)
+	addHistoryOrg(f)
+	call(target, http.MethodPost, "/api/tenders/import?format=jsonl&username="+alice, tenders)
+	call(target, http.MethodPost, "/api/bids/import?format=jsonl&username="+alice, bidRows)
+
+	if got := call(target, http.MethodGet, exportTenders, ""); got != tenders {
+		t.Fatalf("tenders after the round trip:\n%s\nwant:\n%s", got, tenders)
+	}
+	if got := call(target, http.MethodGet, exportBids, ""); got != bidRows {
+		t.Fatalf("bids after the round trip:\n%s\nwant:\n%s", got, bidRows)
+	}
+}
@@ -0,0 +1,253 @@
+// Package jobs runs background work from a queue shared by all server
+// replicas. Every replica polls the queue and claims due jobs, so a job runs
+// on one replica at a time; a replica that dies mid-job loses its claim once
+// the job's timeout passes and the job is retried elsewhere.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"tender_system/internal/models/job"
+	"time"
+)
+
+const (
+	Pending = "Pending"
+	Running = "Running"
+	Done    = "Done"
+	Failed  = "Failed"
+	// Cancelled jobs were withdrawn before they ran.
+	Cancelled = "Cancelled"
+)
+
+const (
+	DefaultMaxAttempts = 8
+	DefaultTimeout     = 5 * time.Minute
+	DefaultConcurrency = 1
+)
+
+// maxBackoff caps the delay between two attempts.
+const maxBackoff = time.Hour
+
+type Store interface {
+	Enqueue(j job.Job) (job.Job, error)
+	// Claim marks up to limit due jobs of the kind as running until
+	// lockedUntil and returns them, skipping jobs claimed concurrently.
+	Claim(kind string, limit int, now, lockedUntil time.Time) ([]job.Job, error)
+	// Complete, Retry and Fail record the outcome of a claimed job. They fail
+	// once the job was claimed again, so that a run whose claim expired
+	// cannot overwrite the outcome of the run that took over.
+	Complete(j job.Job) error
+	Retry(j job.Job, lastError string, runAt time.Time) error
+	Fail(j job.Job, lastError string) error
+}
+
+// Options tune how the jobs of a kind run. Zero fields take the defaults.
+type Options struct {
+	// Concurrency is how many jobs of the kind a replica runs at once.
+	Concurrency int
+	// MaxAttempts is how often a job runs before it is marked failed.
+	MaxAttempts int
+	// Timeout bounds a single attempt and is how long the claim lasts.
+	Timeout time.Duration
+}
+
+type handler struct {
+	run     func(ctx context.Context, payload json.RawMessage) error
+	opts    Options
+	running int
+}
+
+type Runner struct {
+	log      *slog.Logger
+	store    Store
+	workers  int
+	interval time.Duration
+
+	mu       sync.Mutex
+	handlers map[string]*handler
+	running  int
+	wg       sync.WaitGroup
+}
+
+// New returns a runner polling the store every interval and running at most
+// workers jobs at once.
+func New(log *slog.Logger, store Store, workers int, interval time.Duration) *Runner {
+	return &Runner{
+		log:      log,
+		store:    store,
+		workers:  workers,
+		interval: interval,
+		handlers: make(map[string]*handler),
+	}
+}
+
+// Handle registers the handler for jobs of the kind. Payloads are decoded
+// into T before the handler is called.
+func Handle[T any](r *Runner, kind string, opts Options, h func(ctx context.Context, payload T) error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[kind] = &handler{
+		opts: opts,
+		run: func(ctx context.Context, raw json.RawMessage) error {
+			var payload T
+			err := json.Unmarshal(raw, &payload)
+			if err != nil {
+				return fmt.Errorf("decode payload: %w", err)
+			}
+			return h(ctx, payload)
+		},
+	}
+}
+
+// Enqueue adds a job of the kind to the queue. It runs at runAt, or as soon
+// as possible when runAt is zero.
+func (r *Runner) Enqueue(kind string, payload any, runAt time.Time) (job.Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return job.Job{}, fmt.Errorf("jobs.Enqueue: %w", err)
+	}
+
+	maxAttempts := DefaultMaxAttempts
+	r.mu.Lock()
+	if h, ok := r.handlers[kind]; ok {
+		maxAttempts = h.opts.MaxAttempts
+	}
+	r.mu.Unlock()
+
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+
+	return r.store.Enqueue(job.Job{
+		Kind:        kind,
+		Payload:     raw,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt.UTC(),
+	})
+}
+
+// Run polls the queue until the context is done, then stops claiming jobs and
+// waits for the running ones to finish.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.wg.Wait()
+			return
+		case <-ticker.C:
+			r.poll(ctx)
+		}
+	}
+}
+
+// Backoff returns how long to wait before the attempt following the given
+// one: 10 seconds doubling per attempt, at most an hour.
+func Backoff(attempt int) time.Duration {
+	if attempt > 12 {
+		return maxBackoff
+	}
+	return min(10*time.Second<<(attempt-1), maxBackoff)
+}
+
+func (r *Runner) poll(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for kind, h := range r.handlers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		free := min(h.opts.Concurrency-h.running, r.workers-r.running)
+		if free <= 0 {
+			continue
+		}
+
+		now := time.Now().UTC()
+		claimed, err := r.store.Claim(kind, free, now, now.Add(h.opts.Timeout))
+		if err != nil {
+			r.log.Error("Failed to claim jobs", slog.String("kind", kind), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			continue
+		}
+
+		for _, j := range claimed {
+			h.running++
+			r.running++
+			r.wg.Add(1)
+			go r.run(ctx, h, j)
+		}
+	}
+}
+
+// run executes one claimed job. The attempt is not cut short by shutdown, only
+// by the kind's timeout.
+func (r *Runner) run(ctx context.Context, h *handler, j job.Job) {
+	defer func() {
+		r.mu.Lock()
+		h.running--
+		r.running--
+		r.mu.Unlock()
+		r.wg.Done()
+	}()
+
+	// A job claimed again after its runner died may already be out of
+	// attempts.
+	if j.Attempts > j.MaxAttempts {
+		err := r.store.Fail(j, "claim expired on the last attempt")
+		if err != nil {
+			r.log.Error("Failed to record job failure", slog.String("job", j.Id), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+		return
+	}
+
+	err := r.attempt(ctx, h, j)
+	if err == nil {
+		err = r.store.Complete(j)
+		if err != nil {
+			r.log.Error("Failed to complete job", slog.String("job", j.Id), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+		return
+	}
+
+	r.log.Error("Failed to run job", slog.String("job", j.Id), slog.String("kind", j.Kind), slog.Int("attempt", j.Attempts), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+
+	if j.Attempts >= j.MaxAttempts {
+		err = r.store.Fail(j, err.Error())
+	} else {
+		err = r.store.Retry(j, err.Error(), time.Now().UTC().Add(Backoff(j.Attempts)))
+	}
+	if err != nil {
+		r.log.Error("Failed to record job failure", slog.String("job", j.Id), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+	}
+}
+
+func (r *Runner) attempt(ctx context.Context, h *handler, j job.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.opts.Timeout)
+	defer cancel()
+
+	return h.run(ctx, j.Payload)
+}
@@ -0,0 +1,36 @@
+// Package committee renders the workbook procurement committees review a
+// tender's bids in.
+package committee
+
+import (
+	"io"
+	"tender_system/internal/lib/xlsx"
+	"tender_system/internal/models/tender"
+)
+
+// Render writes the report as an XLSX workbook with one sheet each for the
+// bids, the feedback and the decisions.
+func Render(w io.Writer, r tender.CommitteeReport) error {
+	wb := xlsx.New()
+
+	bids := wb.AddSheet("Bids", "Bid", "Author type", "Author", "Version", "Status", "Price", "Votes cast", "Created", "Bid ID")
+	for _, b := range r.Bids {
+		author := b.Author
+		if author == "" {
+			author = b.AuthorId
+		}
+		bids.AddRow(b.Name, b.AuthorType, author, b.Version, b.Status, b.Price, b.VotesCast, b.CreatedAt, b.BidId)
+	}
+
+	feedback := wb.AddSheet("Feedback", "Bid", "Author", "Side", "Rating", "Comment", "Created")
+	for _, f := range r.Feedback {
+		feedback.AddRow(f.BidName, f.Author, f.Side, f.Rating, f.Description, f.CreatedAt)
+	}
+
+	decisions := wb.AddSheet("Decisions", "Bid", "Lot", "Voter", "Vote", "Decision", "Approvals")
+	for _, v := range r.Votes {
+		decisions.AddRow(v.BidName, v.LotId, v.Username, v.Vote, v.Decision, v.NumApproved)
+	}
+
+	return wb.Write(w)
+}
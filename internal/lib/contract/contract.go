@@ -0,0 +1,46 @@
+package contract
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"tender_system/internal/models/award"
+	texttemplate "text/template"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+//go:embed templates/*
+var templates embed.FS
+
+var (
+	markdown = texttemplate.Must(texttemplate.New("contract.md.tmpl").Funcs(funcs).ParseFS(templates, "templates/contract.md.tmpl"))
+	html     = htmltemplate.Must(htmltemplate.New("contract.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/contract.html.tmpl"))
+)
+
+var funcs = map[string]any{
+	"date": func(c award.Contract) string { return c.Award.CreatedAt.UTC().Format("2006-01-02 15:04 MST") },
+}
+
+func ContentType(format string) string {
+	if format == FormatHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// Render writes the contract summary in the requested format.
+func Render(w io.Writer, format string, c award.Contract) error {
+	switch format {
+	case FormatMarkdown:
+		return markdown.Execute(w, c)
+	case FormatHTML:
+		return html.Execute(w, c)
+	default:
+		return fmt.Errorf("unknown contract format %q", format)
+	}
+}
@@ -0,0 +1,554 @@
+// Package openapi checks requests and responses against an OpenAPI 3.0
+// document. It covers the part of the standard the API document uses:
+// path, query and body parameters, $ref, the primitive types, enums, length
+// and range limits, required properties and the uuid and date-time formats.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Document struct {
+	root       map[string]any
+	operations []*Operation
+}
+
+type Operation struct {
+	Method string
+	// Path is the templated path relative to the server URL, such as
+	// /tenders/{tenderId}/status.
+	Path         string
+	Parameters   []Parameter
+	Body         map[string]any
+	BodyRequired bool
+	// Responses maps status codes to the schema of the JSON body.
+	Responses map[string]map[string]any
+
+	doc      *Document
+	segments []string
+}
+
+type Parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Schema   map[string]any
+}
+
+// Error is a violation of the document. Field names the offending parameter
+// or body property.
+type Error struct {
+	Field  string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+var methods = []string{"get", "put", "post", "patch", "delete"}
+
+func Load(data []byte) (*Document, error) {
+	var raw any
+	err := yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("openapi.Load: %w", err)
+	}
+
+	root, ok := normalize(raw).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("openapi.Load: the document is not an object")
+	}
+
+	d := &Document{root: root}
+	paths, _ := root["paths"].(map[string]any)
+	for path, item := range paths {
+		item := d.resolve(item)
+		shared := d.parameters(item["parameters"])
+		for _, method := range methods {
+			node, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			d.operations = append(d.operations, d.operation(strings.ToUpper(method), path, node, shared))
+		}
+	}
+
+	// Templates with more literal segments win, so /tenders/my is preferred
+	// over /tenders/{tenderId}.
+	sort.SliceStable(d.operations, func(i, j int) bool {
+		return literals(d.operations[i].segments) > literals(d.operations[j].segments)
+	})
+
+	return d, nil
+}
+
+// Find returns the operation serving the method and path, which is relative
+// to the server URL, with the values of the path parameters.
+func (d *Document) Find(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, op := range d.operations {
+		if op.Method != method || len(op.segments) != len(segments) {
+			continue
+		}
+		params, ok := match(op.segments, segments)
+		if ok {
+			return op, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// Operations returns every operation of the document.
+func (d *Document) Operations() []*Operation {
+	return slices.Clone(d.operations)
+}
+
+// ValidateParameters checks the path and query parameters of a request.
+// Missing required parameters named in skip are left to the handler.
+func (op *Operation) ValidateParameters(pathParams map[string]string, query url.Values, skip ...string) error {
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.Name]
+		default:
+			continue
+		}
+
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			if p.Required && !slices.Contains(skip, p.Name) {
+				return &Error{Field: p.Name, Reason: "is required"}
+			}
+			continue
+		}
+
+		err := op.doc.validateParameter(p, values)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateBody checks a JSON request body. An empty body is only allowed
+// when the operation does not require one.
+func (op *Operation) ValidateBody(body []byte) error {
+	if op.Body == nil {
+		return nil
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		if op.BodyRequired {
+			return &Error{Reason: "the request body is required"}
+		}
+		return nil
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return &Error{Reason: "the request body is not valid JSON"}
+	}
+
+	return op.doc.validate(op.Body, value, "")
+}
+
+// ValidateResponse checks a JSON response body against the schema documented
+// for the status. Undocumented statuses and bodies without a schema pass.
+func (op *Operation) ValidateResponse(status int, body []byte) error {
+	schema, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		schema, ok = op.Responses["default"]
+	}
+	if !ok || schema == nil {
+		return nil
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return &Error{Reason: "the response body is not valid JSON"}
+	}
+
+	return op.doc.validate(schema, value, "")
+}
+
+func (d *Document) operation(method, path string, node map[string]any, shared []Parameter) *Operation {
+	op := &Operation{
+		Method:    method,
+		Path:      path,
+		Responses: make(map[string]map[string]any),
+		doc:       d,
+		segments:  strings.Split(strings.Trim(path, "/"), "/"),
+	}
+
+	// Operation parameters override the path item's ones of the same name.
+	own := d.parameters(node["parameters"])
+	for _, p := range shared {
+		overridden := false
+		for _, o := range own {
+			if o.Name == p.Name && o.In == p.In {
+				overridden = true
+			}
+		}
+		if !overridden {
+			op.Parameters = append(op.Parameters, p)
+		}
+	}
+	op.Parameters = append(op.Parameters, own...)
+
+	if body := d.resolve(node["requestBody"]); body != nil {
+		op.Body = jsonSchema(body)
+		op.BodyRequired, _ = body["required"].(bool)
+	}
+
+	responses, _ := node["responses"].(map[string]any)
+	for status, resp := range responses {
+		op.Responses[status] = jsonSchema(d.resolve(resp))
+	}
+
+	return op
+}
+
+func (d *Document) parameters(node any) []Parameter {
+	list, _ := node.([]any)
+	result := make([]Parameter, 0, len(list))
+	for _, item := range list {
+		p := d.resolve(item)
+		if p == nil {
+			continue
+		}
+		name, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+		required, _ := p["required"].(bool)
+		schema, _ := p["schema"].(map[string]any)
+		result = append(result, Parameter{Name: name, In: in, Required: required || in == "path", Schema: schema})
+	}
+	return result
+}
+
+// resolve follows $ref until it reaches an object defined in place.
+func (d *Document) resolve(node any) map[string]any {
+	for i := 0; i < 32; i++ {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m
+		}
+		node = d.lookup(ref)
+	}
+	return nil
+}
+
+func (d *Document) lookup(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var node any = d.root
+	for _, part := range strings.Split(ref[2:], "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		node = m[part]
+	}
+	return node
+}
+
+func (d *Document) validateParameter(p Parameter, values []string) error {
+	schema := d.resolve(p.Schema)
+	if schema == nil {
+		return nil
+	}
+
+	if schema["type"] == "array" {
+		items := make([]any, 0, len(values))
+		for _, v := range values {
+			for _, part := range strings.Split(v, ",") {
+				value, err := d.parseScalar(d.resolve(schema["items"]), part)
+				if err != nil {
+					return &Error{Field: p.Name, Reason: err.Error()}
+				}
+				items = append(items, value)
+			}
+		}
+		return d.validate(schema, items, p.Name)
+	}
+
+	value, err := d.parseScalar(schema, values[0])
+	if err != nil {
+		return &Error{Field: p.Name, Reason: err.Error()}
+	}
+	return d.validate(schema, value, p.Name)
+}
+
+// parseScalar converts a parameter from its text form to the JSON value the
+// schema describes.
+func (d *Document) parseScalar(schema map[string]any, s string) (any, error) {
+	switch schema["type"] {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return json.Number(s), nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	default:
+		return s, nil
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func (d *Document) validate(node any, value any, field string) error {
+	schema := d.resolve(node)
+	if schema == nil {
+		return nil
+	}
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			return nil
+		}
+		if _, typed := schema["type"]; typed {
+			return &Error{Field: field, Reason: "must not be null"}
+		}
+		return nil
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			if err := d.validate(sub, value, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(enum, value) {
+		return &Error{Field: field, Reason: fmt.Sprintf("must be one of %s", enumList(enum))}
+	}
+
+	switch schema["type"] {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return &Error{Field: field, Reason: "must be a string"}
+		}
+		length := len([]rune(s))
+		if max, ok := number(schema["maxLength"]); ok && float64(length) > max {
+			return &Error{Field: field, Reason: fmt.Sprintf("must be at most %v characters long", max)}
+		}
+		if min, ok := number(schema["minLength"]); ok && float64(length) < min {
+			return &Error{Field: field, Reason: fmt.Sprintf("must be at least %v characters long", min)}
+		}
+		switch schema["format"] {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return &Error{Field: field, Reason: "must be an RFC 3339 date-time"}
+			}
+		case "uuid":
+			if !uuidPattern.MatchString(s) {
+				return &Error{Field: field, Reason: "must be a UUID"}
+			}
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return &Error{Field: field, Reason: fmt.Sprintf("must be a %s", schema["type"])}
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return &Error{Field: field, Reason: fmt.Sprintf("must be a %s", schema["type"])}
+		}
+		if schema["type"] == "integer" {
+			if _, err := n.Int64(); err != nil {
+				return &Error{Field: field, Reason: "must be an integer"}
+			}
+		}
+		if max, ok := number(schema["maximum"]); ok && f > max {
+			return &Error{Field: field, Reason: fmt.Sprintf("must be at most %v", max)}
+		}
+		if min, ok := number(schema["minimum"]); ok && f < min {
+			return &Error{Field: field, Reason: fmt.Sprintf("must be at least %v", min)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &Error{Field: field, Reason: "must be a boolean"}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return &Error{Field: field, Reason: "must be an array"}
+		}
+		for i, item := range items {
+			if err := d.validate(schema["items"], item, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return &Error{Field: field, Reason: "must be an object"}
+		}
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			name, _ := name.(string)
+			if _, ok := obj[name]; !ok {
+				return &Error{Field: join(field, name), Reason: "is required"}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := properties[name]
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return &Error{Field: join(field, name), Reason: "is not allowed"}
+				}
+				continue
+			}
+			if err := d.validate(prop, obj[name], join(field, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonSchema returns the schema of the application/json content of a request
+// body or response.
+func jsonSchema(node map[string]any) map[string]any {
+	content, _ := node["content"].(map[string]any)
+	media, _ := content["application/json"].(map[string]any)
+	schema, _ := media["schema"].(map[string]any)
+	return schema
+}
+
+func decode(body []byte) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	return value, err
+}
+
+// normalize turns the maps with non-string keys YAML may produce into maps
+// keyed by strings.
+func normalize(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			n[k] = normalize(v)
+		}
+		return n
+	case map[any]any:
+		m := make(map[string]any, len(n))
+		for k, v := range n {
+			m[fmt.Sprint(k)] = normalize(v)
+		}
+		return m
+	case []any:
+		for i, v := range n {
+			n[i] = normalize(v)
+		}
+		return n
+	default:
+		return n
+	}
+}
+
+func match(template, segments []string) (map[string]string, bool) {
+	params := make(map[string]string)
+	for i, t := range template {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			params[t[1:len(t)-1]] = value
+			continue
+		}
+		if t != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func literals(segments []string) int {
+	n := 0
+	for _, s := range segments {
+		if !strings.HasPrefix(s, "{") {
+			n++
+		}
+	}
+	return n
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		parts[i] = fmt.Sprint(e)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
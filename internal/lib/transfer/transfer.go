@@ -0,0 +1,226 @@
+// Package transfer reads and writes records in bulk, either as CSV with a
+// header row or as JSON Lines with one object per line. JSON Lines use the
+// JSON form of the record, CSV the columns of its Codec.
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+// maxLine bounds a single JSON Lines record.
+const maxLine = 1 << 20
+
+var (
+	ErrFormat = errors.New("the format must be csv or jsonl")
+	ErrHeader = errors.New("invalid CSV header")
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case CSV, JSONL:
+		return Format(s), nil
+	}
+	return "", ErrFormat
+}
+
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Codec maps a record to the CSV columns listed in Columns. Parse receives
+// the cells of a row by column name; columns missing from the file are
+// absent from the map.
+type Codec[T any] struct {
+	Columns []string
+	Values  func(T) []string
+	Parse   func(map[string]string) (T, error)
+}
+
+// RowError is a record that could not be read. Reading may go on with the
+// next one.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+type Writer[T any] struct {
+	format Format
+	codec  Codec[T]
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+func NewWriter[T any](w io.Writer, format Format, codec Codec[T]) *Writer[T] {
+	buf := bufio.NewWriterSize(w, 32<<10)
+	return &Writer[T]{format: format, codec: codec, buf: buf, csv: csv.NewWriter(buf)}
+}
+
+func (w *Writer[T]) Write(v T) error {
+	if w.format == JSONL {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.buf.Write(append(data, '\n'))
+		return err
+	}
+
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+	return w.csv.Write(w.codec.Values(v))
+}
+
+// Flush writes out buffered records. A CSV file without records still gets
+// its header.
+func (w *Writer[T]) Flush() error {
+	if w.format == CSV {
+		err := w.writeHeader()
+		if err != nil {
+			return err
+		}
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.buf.Flush()
+}
+
+func (w *Writer[T]) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.csv.Write(w.codec.Columns)
+}
+
+type Reader[T any] struct {
+	format  Format
+	codec   Codec[T]
+	csv     *csv.Reader
+	lines   *bufio.Scanner
+	columns []string
+	row     int
+}
+
+// NewReader starts reading records. For CSV it reads the header, which may
+// name the codec's columns in any order and leave some out.
+func NewReader[T any](r io.Reader, format Format, codec Codec[T]) (*Reader[T], error) {
+	reader := &Reader[T]{format: format, codec: codec}
+	if format == JSONL {
+		reader.lines = bufio.NewScanner(r)
+		reader.lines.Buffer(make([]byte, 0, 64<<10), maxLine)
+		return reader, nil
+	}
+
+	reader.csv = csv.NewReader(r)
+	header, err := reader.csv.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrHeader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHeader, err)
+	}
+
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		if !slices.Contains(codec.Columns, column) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrHeader, column)
+		}
+		if slices.Contains(reader.columns, column) {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrHeader, column)
+		}
+		reader.columns = append(reader.columns, column)
+	}
+	reader.csv.FieldsPerRecord = len(header)
+
+	return reader, nil
+}
+
+// Row is the number of the record read last, counting from 1 and without
+// the CSV header. Blank JSON lines are not counted.
+func (r *Reader[T]) Row() int {
+	return r.row
+}
+
+// Read returns the next record, a *RowError for a malformed one or io.EOF
+// once the input is exhausted.
+func (r *Reader[T]) Read() (T, error) {
+	var v T
+	if r.format == JSONL {
+		for r.lines.Scan() {
+			line := bytes.TrimSpace(r.lines.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			r.row++
+
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.DisallowUnknownFields()
+			err := decoder.Decode(&v)
+			if err != nil {
+				return v, &RowError{Row: r.row, Err: err}
+			}
+			return v, nil
+		}
+		if err := r.lines.Err(); err != nil {
+			return v, err
+		}
+		return v, io.EOF
+	}
+
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		return v, io.EOF
+	}
+	r.row++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return v, &RowError{Row: r.row, Err: parseErr.Err}
+	}
+	if err != nil {
+		return v, err
+	}
+
+	cells := make(map[string]string, len(record))
+	for i, cell := range record {
+		cells[r.columns[i]] = cell
+	}
+	v, err = r.codec.Parse(cells)
+	if err != nil {
+		return v, &RowError{Row: r.row, Err: err}
+	}
+	return v, nil
+}
@@ -0,0 +1,304 @@
+// Package xlsx writes simple Office Open XML workbooks: plain sheets of rows
+// with a bold header, holding text, numbers and date-times. Strings are
+// stored inline, so no shared string table is needed.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Cell styles defined in styles.xml.
+const (
+	styleDefault = 0
+	styleHeader  = 1
+	styleTime    = 2
+)
+
+// maxSheetName is the length Excel allows sheet names.
+const maxSheetName = 31
+
+// excelEpoch is day zero of the 1900 date system, shifted by the leap day
+// Excel wrongly assumes in 1900.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type Workbook struct {
+	sheets []*Sheet
+}
+
+type Sheet struct {
+	name   string
+	header []string
+	rows   [][]any
+}
+
+func New() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet appends a sheet whose first row is the bold header. Names are cut
+// to the 31 characters Excel allows, and a name another sheet has already,
+// compared regardless of case as Excel does, is numbered: Bids, Bids (2).
+func (wb *Workbook) AddSheet(name string, header ...string) *Sheet {
+	sheet := &Sheet{name: wb.uniqueName(sheetName(name)), header: header}
+	wb.sheets = append(wb.sheets, sheet)
+	return sheet
+}
+
+// AddRow appends a row. Cells may be strings, integers, floats, bools,
+// time.Time or pointers to those; nil pointers and zero times leave the cell
+// empty.
+func (s *Sheet) AddRow(cells ...any) {
+	s.rows = append(s.rows, cells)
+}
+
+// Write stores the workbook as a zip archive.
+func (wb *Workbook) Write(w io.Writer) error {
+	z := zip.NewWriter(w)
+
+	parts := []struct {
+		name string
+		data string
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		f, err := z.Create(part.name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, part.data)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range wb.sheets {
+		f, err := z.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		err = sheet.write(f)
+		if err != nil {
+			return err
+		}
+	}
+
+	return z.Close()
+}
+
+func (s *Sheet) write(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(s.header) > 0 {
+		buf.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" state="frozen"/></sheetView></sheetViews>`)
+	}
+	buf.WriteString(`<sheetData>`)
+
+	row := 1
+	if len(s.header) > 0 {
+		cells := make([]any, len(s.header))
+		for i, h := range s.header {
+			cells[i] = h
+		}
+		writeRow(buf, row, cells, styleHeader)
+		row++
+	}
+	for _, cells := range s.rows {
+		writeRow(buf, row, cells, styleDefault)
+		row++
+	}
+
+	buf.WriteString(`</sheetData></worksheet>`)
+	return buf.Flush()
+}
+
+func writeRow(buf *bufio.Writer, row int, cells []any, style int) {
+	fmt.Fprintf(buf, `<row r="%d">`, row)
+	for i, v := range cells {
+		ref := column(i) + strconv.Itoa(row)
+		switch v := deref(v).(type) {
+		case nil:
+		case string:
+			fmt.Fprintf(buf, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">`, ref, styleAttr(style))
+			xml.EscapeText(buf, []byte(v))
+			buf.WriteString(`</t></is></c>`)
+		case bool:
+			b := 0
+			if v {
+				b = 1
+			}
+			fmt.Fprintf(buf, `<c r="%s" t="b"%s><v>%d</v></c>`, ref, styleAttr(style), b)
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			days := v.UTC().Sub(excelEpoch).Hours() / 24
+			fmt.Fprintf(buf, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr(styleTime), strconv.FormatFloat(days, 'f', -1, 64))
+		case int:
+			fmt.Fprintf(buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr(style), v)
+		case int32:
+			fmt.Fprintf(buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr(style), v)
+		case int64:
+			fmt.Fprintf(buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr(style), v)
+		case float64:
+			fmt.Fprintf(buf, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr(style), strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			fmt.Fprintf(buf, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">`, ref, styleAttr(style))
+			xml.EscapeText(buf, []byte(fmt.Sprint(v)))
+			buf.WriteString(`</t></is></c>`)
+		}
+	}
+	buf.WriteString(`</row>`)
+}
+
+// deref unwraps the pointer cell types, turning nil pointers into nil.
+func deref(v any) any {
+	switch p := v.(type) {
+	case *string:
+		if p != nil {
+			return *p
+		}
+	case *int:
+		if p != nil {
+			return *p
+		}
+	case *float64:
+		if p != nil {
+			return *p
+		}
+	case *time.Time:
+		if p != nil {
+			return *p
+		}
+	default:
+		return v
+	}
+	return nil
+}
+
+func styleAttr(style int) string {
+	if style == styleDefault {
+		return ""
+	}
+	return fmt.Sprintf(` s="%d"`, style)
+}
+
+// column converts a zero based index into a column name: A, B, ..., Z, AA.
+func column(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func (wb *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (wb *Workbook) workbook() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range wb.sheets {
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(sheet.name))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (wb *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// uniqueName cuts the name to maxSheetName characters, making room for a
+// numeric suffix while the name is taken.
+func (wb *Workbook) uniqueName(name string) string {
+	unique := truncate(name, maxSheetName)
+	for n := 2; wb.hasSheet(unique); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		unique = truncate(name, maxSheetName-len(suffix)) + suffix
+	}
+	return unique
+}
+
+func (wb *Workbook) hasSheet(name string) bool {
+	for _, sheet := range wb.sheets {
+		if strings.EqualFold(sheet.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+// sheetName replaces the characters Excel rejects in sheet names.
+func sheetName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles holds the default style, a bold one for headers and one showing
+// date-times as yyyy-mm-dd hh:mm.
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,109 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"slices"
+	"tender_system/internal/lib/xlsx"
+	"testing"
+	"time"
+)
+
+type workbookPart struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type worksheetPart struct {
+	Rows []struct {
+		Cells []cell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type cell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Style  string `xml:"s,attr"`
+	Value  string `xml:"v"`
+	Inline string `xml:"is>t"`
+}
+
+// TestWorkbook writes a workbook and reads the archive back: sheet names are
+// cleaned, cut and numbered into unique ones, and cells keep their types.
+func TestWorkbook(t *testing.T) {
+	wb := xlsx.New()
+	sheet := wb.AddSheet("Tenders", "Name", "Budget", "Share", "Open", "Deadline", "Note")
+	wb.AddSheet("tenders")
+	wb.AddSheet("Bids placed by Example Supplier LLC")
+	wb.AddSheet("Bids placed by Example Supplier LLC")
+	wb.AddSheet("Q1/Q2")
+
+	var note *string
+	sheet.AddRow("Walls", 120000, 0.5, true, time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC), note)
+
+	var buf bytes.Buffer
+	err := wb.Write(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var book workbookPart
+	readPart(t, archive, "xl/workbook.xml", &book)
+	var names []string
+	for _, s := range book.Sheets {
+		names = append(names, s.Name)
+	}
+	want := []string{"Tenders", "tenders (2)", "Bids placed by Example Supplier", "Bids placed by Example Supp (2)", "Q1_Q2"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("sheet names %q, want %q", names, want)
+	}
+
+	var tenders worksheetPart
+	readPart(t, archive, "xl/worksheets/sheet1.xml", &tenders)
+	if len(tenders.Rows) != 2 {
+		t.Fatalf("%d rows, want the header and one row", len(tenders.Rows))
+	}
+	for _, c := range tenders.Rows[0].Cells {
+		if c.Type != "inlineStr" || c.Style != "1" {
+			t.Errorf("header cell %s: type %q style %q, want bold inline text", c.Ref, c.Type, c.Style)
+		}
+	}
+
+	wantCells := []cell{
+		{Ref: "A2", Type: "inlineStr", Inline: "Walls"},
+		{Ref: "B2", Value: "120000"},
+		{Ref: "C2", Value: "0.5"},
+		{Ref: "D2", Type: "b", Value: "1"},
+		// 2024-09-01 is day 45536 of the 1900 date system, noon half a day.
+		{Ref: "E2", Style: "2", Value: "45536.5"},
+	}
+	if !slices.Equal(tenders.Rows[1].Cells, wantCells) {
+		t.Fatalf("cells %+v, want %+v", tenders.Rows[1].Cells, wantCells)
+	}
+}
+
+func readPart(t *testing.T, archive *zip.Reader, name string, v any) {
+	t.Helper()
+
+	f, err := archive.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = xml.Unmarshal(data, v)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+}
@@ -0,0 +1,22 @@
+package attachment
+
+import "time"
+
+// The entity types attachments belong to.
+const (
+	Tender = "tender"
+	Bid    = "bid"
+)
+
+type Attachment struct {
+	Id          string    `json:"id"`
+	EntityType  string    `json:"entityType"`
+	EntityId    string    `json:"entityId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  string    `json:"uploadedBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
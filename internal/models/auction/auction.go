@@ -0,0 +1,45 @@
+package auction
+
+import "time"
+
+// Settings configure a reverse auction. Rounds last RoundSeconds each; a
+// price placed less than ExtensionSeconds before the end of a round pushes
+// the end back to ExtensionSeconds from the placement.
+type Settings struct {
+	StartPrice       float64 `json:"startPrice" validate:"gt=0"`
+	MinDecrement     float64 `json:"minDecrement" validate:"gt=0"`
+	RoundSeconds     int     `json:"roundSeconds" validate:"gte=10"`
+	MaxRounds        int     `json:"maxRounds" validate:"gte=1"`
+	ExtensionSeconds int     `json:"extensionSeconds" validate:"gte=0"`
+}
+
+type State struct {
+	Status        string
+	Round         int
+	RoundEndsAt   *time.Time
+	RoundBids     int
+	BestPrice     *float64
+	BestBidId     string
+	ProposedBidId string
+}
+
+type Auction struct {
+	TenderId string
+	Settings Settings
+	State    State
+}
+
+// View is the public state of an auction. It never reveals who placed the
+// best price.
+type View struct {
+	TenderId      string     `json:"tenderId"`
+	Status        string     `json:"status"`
+	Round         int        `json:"round"`
+	MaxRounds     int        `json:"maxRounds"`
+	RoundEndsAt   *time.Time `json:"roundEndsAt,omitempty"`
+	StartPrice    float64    `json:"startPrice"`
+	MinDecrement  float64    `json:"minDecrement"`
+	BestPrice     *float64   `json:"bestPrice,omitempty"`
+	RoundBids     int        `json:"roundBids"`
+	ProposedBidId string     `json:"proposedBidId,omitempty"`
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entry is one record of the audit log. Before and After are the JSON state
+// of the entity around the change; Hash chains the entry to the previous one.
+type Entry struct {
+	Id             int64           `json:"id"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	EntityType     string          `json:"entityType"`
+	EntityId       string          `json:"entityId"`
+	Action         string          `json:"action"`
+	Actor          string          `json:"actor"`
+	OrganizationId string          `json:"organizationId,omitempty"`
+	IP             string          `json:"ip,omitempty"`
+	RequestId      string          `json:"requestId,omitempty"`
+	Before         json.RawMessage `json:"before,omitempty"`
+	After          json.RawMessage `json:"after,omitempty"`
+	PrevHash       string          `json:"prevHash"`
+	Hash           string          `json:"hash"`
+}
+
+// Subject is the current state of an audited entity.
+type Subject struct {
+	EntityId       string
+	OrganizationId string
+	State          json.RawMessage
+}
+
+type Filter struct {
+	OrganizationId string
+	EntityType     string
+	EntityId       string
+	Actor          string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	Offset         int
+}
+
+type Verification struct {
+	Valid    bool  `json:"valid"`
+	Entries  int   `json:"entries"`
+	BrokenAt int64 `json:"brokenAt,omitempty"`
+}
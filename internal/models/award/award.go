@@ -0,0 +1,46 @@
+package award
+
+import "time"
+
+type Award struct {
+	Id        string    `json:"id"`
+	TenderId  string    `json:"tenderId"`
+	BidId     string    `json:"bidId"`
+	LotId     string    `json:"lotId,omitempty"`
+	Approvers []string  `json:"approvers"`
+	Delivery  *Delivery `json:"delivery,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Outcome tells what reaching the approval quorum changed besides the award
+// itself.
+type Outcome struct {
+	BidApproved   bool
+	TenderAwarded bool
+}
+
+// Delivery records whether the winner delivered what the award was for on
+// time.
+type Delivery struct {
+	OnTime     bool      `json:"onTime"`
+	RecordedBy string    `json:"recordedBy"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+type DeliveryRequest struct {
+	OnTime *bool `json:"onTime" validate:"required"`
+}
+
+type Contract struct {
+	Award             Award
+	TenderName        string
+	TenderDescription string
+	ServiceType       string
+	LotName           string
+	OrganizationName  string
+	BidName           string
+	BidDescription    string
+	BidAuthorType     string
+	BidAuthorName     string
+	BidVersion        int
+}
@@ -3,11 +3,16 @@ package bids
 import "time"
 
 type BidRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	TenderId    string `json:"tenderId" validate:"required"`
-	AuthorType  string `json:"authorType" validate:"required"`
-	AuthorId    string `json:"authorId" validate:"required"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description" validate:"required"`
+	TenderId    string   `json:"tenderId" validate:"required"`
+	AuthorType  string   `json:"authorType" validate:"required"`
+	AuthorId    string   `json:"authorId" validate:"required"`
+	LotIds      []string `json:"lotIds,omitempty"`
+	Price       *float64 `json:"price,omitempty" validate:"omitempty,gt=0"`
+	// OutOfBudget is worked out from the tender budget when the bid is
+	// placed.
+	OutOfBudget bool `json:"-"`
 }
 
 type Bid struct {
@@ -18,27 +23,75 @@ type Bid struct {
 	TenderId    string    `json:"tenderId"`
 	AuthorType  string    `json:"authorType"`
 	AuthorId    string    `json:"authorId"`
+	Price       *float64  `json:"price,omitempty"`
 	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
 type BidResponse struct {
-	Id         string    `json:"id"`
-	Name       string    `json:"name"`
-	Status     string    `json:"status"`
-	AuthorType string    `json:"authorType"`
-	AuthorId   string    `json:"authorId"`
-	Version    int       `json:"version"`
-	CreatedAt  time.Time `json:"createdAt"`
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	TenderId    string    `json:"tenderId"`
+	Status      string    `json:"status"`
+	AuthorType  string    `json:"authorType"`
+	AuthorId    string    `json:"authorId"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
+	LotIds      []string  `json:"lotIds,omitempty"`
+	Price       *float64  `json:"price,omitempty"`
 }
 
 type BidPatchRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       *float64 `json:"price,omitempty" validate:"omitempty,gt=0"`
 }
 
 type BidReviewResponse struct {
 	Id          string    `json:"id"`
 	Description string    `json:"description"`
+	Rating      *int      `json:"rating,omitempty"`
 	CreatedAt   time.Time `json:"createdAt"`
 }
+
+// Comment is one entry in the feedback thread of a bid. Replies point to the
+// comment they answer with ParentId.
+type Comment struct {
+	Id          string     `json:"id"`
+	BidId       string     `json:"bidId"`
+	ParentId    string     `json:"parentId,omitempty"`
+	AuthorId    string     `json:"-"`
+	Author      string     `json:"author"`
+	Side        string     `json:"side"`
+	Description string     `json:"description"`
+	Rating      *int       `json:"rating,omitempty"`
+	Deleted     bool       `json:"deleted"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	EditedAt    *time.Time `json:"editedAt,omitempty"`
+}
+
+// CommentRequest adds a comment to a feedback thread. Reviewers may rate the
+// bid from 1 to 5 when starting a new thread.
+type CommentRequest struct {
+	Description string `json:"description" validate:"required,max=1000"`
+	ParentId    string `json:"parentId,omitempty" validate:"omitempty,uuid"`
+	Rating      *int   `json:"rating,omitempty" validate:"omitempty,min=1,max=5"`
+}
+
+type CommentPatchRequest struct {
+	Description string `json:"description" validate:"required,max=1000"`
+	Rating      *int   `json:"rating,omitempty" validate:"omitempty,min=1,max=5"`
+}
+
+type Decision struct {
+	BidId       string `json:"bidId"`
+	LotId       string `json:"lotId,omitempty"`
+	Status      string `json:"status"`
+	NumApproved int    `json:"numApproved"`
+}
+
+type Author struct {
+	Type string `json:"authorType"`
+	Id   string `json:"authorId"`
+}
@@ -0,0 +1,35 @@
+package conflict
+
+import "time"
+
+// Rule sets what an organization does when a conflict rule is hit on one of
+// its tenders.
+type Rule struct {
+	Rule   string `json:"rule" validate:"required"`
+	Action string `json:"action" validate:"required,oneof=Block Record Allow"`
+}
+
+type RulesRequest struct {
+	Rules []Rule `json:"rules" validate:"required,dive"`
+}
+
+// Finding is a conflict rule hit by a bid or vote.
+type Finding struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+}
+
+// Recusal is a voter's declaration that they will not decide on the bids of
+// a tender.
+type Recusal struct {
+	TenderId  string    `json:"tenderId"`
+	UserId    string    `json:"-"`
+	Username  string    `json:"username"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RecusalRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
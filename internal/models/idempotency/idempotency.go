@@ -0,0 +1,19 @@
+package idempotency
+
+import "time"
+
+// Record is a request made with an Idempotency-Key and, once it completed,
+// the response to replay. Status is zero while the request is in progress.
+// CreatedAt tells reservations of the same key apart.
+type Record struct {
+	Key         string
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	// LockedUntil is when the reservation of a request in progress lapses
+	// and a retry may take the key over.
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
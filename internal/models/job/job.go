@@ -0,0 +1,26 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job is a unit of background work in the queue. Payload is the JSON encoded
+// argument of the kind's handler.
+type Job struct {
+	Id   string `json:"id"`
+	Kind string `json:"kind"`
+	// SubjectId names what the job acts on. A subject has at most one
+	// pending job of a kind.
+	SubjectId   string          `json:"subjectId,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	RunAt       time.Time       `json:"runAt"`
+	LastError   string          `json:"lastError,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	// LockedUntil is when the claim of a running job expires.
+	LockedUntil time.Time `json:"-"`
+}
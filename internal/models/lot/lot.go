@@ -0,0 +1,27 @@
+package lot
+
+import "time"
+
+type Lot struct {
+	Id          string    `json:"id"`
+	TenderId    string    `json:"tenderId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ServiceType string    `json:"serviceType"`
+	Budget      *float64  `json:"budget,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type LotRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description" validate:"required,max=500"`
+	ServiceType string   `json:"serviceType" validate:"required,oneof=Construction Delivery Manufacture"`
+	Budget      *float64 `json:"budget,omitempty" validate:"omitempty,gte=0"`
+}
+
+type LotPatchRequest struct {
+	Name        string   `json:"name,omitempty" validate:"max=100"`
+	Description string   `json:"description,omitempty" validate:"max=500"`
+	ServiceType string   `json:"serviceType,omitempty" validate:"omitempty,oneof=Construction Delivery Manufacture"`
+	Budget      *float64 `json:"budget,omitempty" validate:"omitempty,gte=0"`
+}
@@ -0,0 +1,57 @@
+package notification
+
+import "time"
+
+const (
+	FeedbackLeft        = "feedback_left"
+	FeedbackReplied     = "feedback_replied"
+	BidDecided          = "bid_decided"
+	TenderStatusChanged = "tender_status_changed"
+)
+
+// Event is something that happened to a tender or bid that its participants
+// should hear about.
+type Event struct {
+	Type       string
+	TenderId   string
+	TenderName string
+	BidId      string
+	BidName    string
+	Status     string
+	Text       string
+}
+
+type Notification struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"-"`
+	Event     string    `json:"event"`
+	TenderId  string    `json:"tenderId,omitempty"`
+	BidId     string    `json:"bidId,omitempty"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Settings are the notification preferences of a user.
+type Settings struct {
+	Language     string   `json:"language" validate:"required,oneof=en ru"`
+	InApp        bool     `json:"inApp"`
+	Email        bool     `json:"email"`
+	EmailAddress string   `json:"emailAddress,omitempty" validate:"required_if=Email true,omitempty,email"`
+	Muted        []string `json:"muted" validate:"dive,oneof=feedback_left feedback_replied bid_decided tender_status_changed"`
+}
+
+// DefaultSettings apply to users who never changed their preferences.
+func DefaultSettings() Settings {
+	return Settings{Language: "en", InApp: true, Muted: []string{}}
+}
+
+func (s Settings) IsMuted(event string) bool {
+	for _, muted := range s.Muted {
+		if muted == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package organization
+
+import "time"
+
+type Organization struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type OrganizationRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	Type        string `json:"type" validate:"required,oneof=IE LLC JSC"`
+}
+
+type OrganizationPatchRequest struct {
+	Name        string `json:"name,omitempty" validate:"max=100"`
+	Description string `json:"description,omitempty" validate:"max=500"`
+	Type        string `json:"type,omitempty" validate:"omitempty,oneof=IE LLC JSC"`
+}
+
+type Responsible struct {
+	Id             string `json:"id"`
+	OrganizationId string `json:"organizationId"`
+	UserId         string `json:"userId"`
+	Username       string `json:"username"`
+}
@@ -0,0 +1,36 @@
+package schedule
+
+import "time"
+
+// JobPayload is the payload of the publish_tender and spawn_recurrence
+// background jobs.
+type JobPayload struct {
+	SubjectId string `json:"subjectId"`
+	Actor     string `json:"actor"`
+}
+
+type PublicationRequest struct {
+	// PublishAt set to null cancels the scheduled publication.
+	PublishAt *time.Time `json:"publishAt"`
+}
+
+// Recurrence spawns a new tender from a base tender at a fixed frequency. A
+// tender is the base of at most one recurrence.
+type Recurrence struct {
+	TenderId     string    `json:"tenderId"`
+	Frequency    string    `json:"frequency"`
+	Publish      bool      `json:"publish"`
+	NextRunAt    time.Time `json:"nextRunAt"`
+	LastTenderId string    `json:"lastTenderId,omitempty"`
+	CreatedBy    string    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type RecurrenceRequest struct {
+	Frequency string `json:"frequency" validate:"required,oneof=Monthly Quarterly"`
+	// StartAt is the first run, one period from now when omitted.
+	StartAt *time.Time `json:"startAt,omitempty"`
+	// Publish publishes every spawned tender right away instead of leaving
+	// it in the Created status.
+	Publish bool `json:"publish"`
+}
@@ -0,0 +1,35 @@
+package supplier
+
+// Stats are the raw counts behind a supplier's reputation, taken over every
+// tender the supplier ever bid on.
+type Stats struct {
+	AuthorType       string
+	AuthorId         string
+	Name             string
+	Bids             int
+	Tenders          int
+	Decided          int
+	Won              int
+	Ratings          int
+	RatingSum        int
+	Deliveries       int
+	OnTimeDeliveries int
+}
+
+// Profile sums up how a bid author fared in past tenders. Rates and averages
+// are omitted while there is nothing to compute them from.
+type Profile struct {
+	AuthorType       string   `json:"authorType"`
+	AuthorId         string   `json:"authorId"`
+	Name             string   `json:"name"`
+	Bids             int      `json:"bids"`
+	Tenders          int      `json:"tenders"`
+	Decided          int      `json:"decided"`
+	Won              int      `json:"won"`
+	WinRate          *float64 `json:"winRate,omitempty"`
+	Ratings          int      `json:"ratings"`
+	AverageRating    *float64 `json:"averageRating,omitempty"`
+	Deliveries       int      `json:"deliveries"`
+	OnTimeDeliveries int      `json:"onTimeDeliveries"`
+	OnTimeRate       *float64 `json:"onTimeRate,omitempty"`
+}
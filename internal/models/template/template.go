@@ -0,0 +1,54 @@
+package template
+
+import (
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/lot"
+	"time"
+)
+
+// Spec is what a template fills in on the tenders created from it. The
+// deadline is kept as an offset from the moment the tender is created.
+type Spec struct {
+	Name                string            `json:"name" validate:"required,max=100"`
+	Description         string            `json:"description" validate:"required,max=500"`
+	ServiceType         string            `json:"serviceType" validate:"required,oneof=Construction Delivery Manufacture"`
+	Criteria            []string          `json:"criteria,omitempty" validate:"dive,required,max=500"`
+	DeadlineOffsetHours *int              `json:"deadlineOffsetHours,omitempty" validate:"omitempty,gt=0"`
+	Lots                []lot.LotRequest  `json:"lots,omitempty" validate:"dive"`
+	Type                string            `json:"type,omitempty" validate:"omitempty,oneof=Standard ReverseAuction"`
+	Auction             *auction.Settings `json:"auction,omitempty" validate:"required_if=Type ReverseAuction,omitempty"`
+	BudgetMin           *float64          `json:"budgetMin,omitempty" validate:"omitempty,gte=0"`
+	BudgetMax           *float64          `json:"budgetMax,omitempty" validate:"omitempty,gt=0"`
+	Currency            string            `json:"currency,omitempty" validate:"required_with=BudgetMin BudgetMax,omitempty,iso4217"`
+	BudgetVisibility    string            `json:"budgetVisibility,omitempty" validate:"omitempty,oneof=Public Hidden"`
+	BudgetPolicy        string            `json:"budgetPolicy,omitempty" validate:"omitempty,oneof=Reject Flag"`
+}
+
+type Template struct {
+	Id             string    `json:"id"`
+	OrganizationId string    `json:"organizationId"`
+	Title          string    `json:"title"`
+	Spec           Spec      `json:"spec"`
+	CreatedBy      string    `json:"createdBy"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type TemplateRequest struct {
+	Title string `json:"title" validate:"required,max=100"`
+	Spec  Spec   `json:"spec"`
+}
+
+// Overrides replace parts of a template's spec for one tender. Deadline, when
+// set, wins over DeadlineOffsetHours.
+type Overrides struct {
+	Name                string     `json:"name,omitempty" validate:"max=100"`
+	Description         string     `json:"description,omitempty" validate:"max=500"`
+	ServiceType         string     `json:"serviceType,omitempty" validate:"omitempty,oneof=Construction Delivery Manufacture"`
+	Criteria            []string   `json:"criteria,omitempty" validate:"dive,required,max=500"`
+	DeadlineOffsetHours *int       `json:"deadlineOffsetHours,omitempty" validate:"omitempty,gt=0"`
+	Deadline            *time.Time `json:"deadline,omitempty"`
+	BudgetMin           *float64   `json:"budgetMin,omitempty" validate:"omitempty,gte=0"`
+	BudgetMax           *float64   `json:"budgetMax,omitempty" validate:"omitempty,gt=0"`
+	Currency            string     `json:"currency,omitempty" validate:"omitempty,iso4217"`
+}
@@ -1,27 +1,160 @@
 package tender
 
-import "time"
+import (
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/lot"
+	"time"
+)
 
 type TenderRequest struct {
-	Name            string `json:"name" validate:"required"`
-	Description     string `json:"description" validate:"required"`
-	ServiceType     string `json:"serviceType" validate:"required"`
-	OrganizationId  string `json:"organizationId" validate:"required"`
-	CreatorUsername string `json:"creatorUsername" validate:"required"`
+	Name            string     `json:"name" validate:"required"`
+	Description     string     `json:"description" validate:"required"`
+	ServiceType     string     `json:"serviceType" validate:"required"`
+	OrganizationId  string     `json:"organizationId" validate:"required"`
+	CreatorUsername string     `json:"creatorUsername" validate:"required"`
+	Deadline        *time.Time `json:"deadline,omitempty"`
+	// PublishAt publishes the tender automatically at that time. It must lie
+	// in the future and the creator must be allowed to publish tenders.
+	PublishAt *time.Time        `json:"publishAt,omitempty"`
+	Criteria  []string          `json:"criteria,omitempty" validate:"dive,required,max=500"`
+	Lots      []lot.LotRequest  `json:"lots,omitempty" validate:"dive"`
+	Type      string            `json:"type,omitempty" validate:"omitempty,oneof=Standard ReverseAuction"`
+	Auction   *auction.Settings `json:"auction,omitempty" validate:"required_if=Type ReverseAuction,omitempty"`
+	BudgetMin *float64          `json:"budgetMin,omitempty" validate:"omitempty,gte=0"`
+	BudgetMax *float64          `json:"budgetMax,omitempty" validate:"omitempty,gt=0"`
+	Currency  string            `json:"currency,omitempty" validate:"required_with=BudgetMin BudgetMax,omitempty,iso4217"`
+	// BudgetVisibility is Public (default) or Hidden. Hidden budgets are only
+	// shown to members allowed to view the organization's bids.
+	BudgetVisibility string `json:"budgetVisibility,omitempty" validate:"omitempty,oneof=Public Hidden"`
+	// BudgetPolicy is Flag (default) or Reject and decides what happens to
+	// bids priced outside the budget.
+	BudgetPolicy string `json:"budgetPolicy,omitempty" validate:"omitempty,oneof=Reject Flag"`
 }
 
 type TenderResponse struct {
-	Id          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	ServiceType string    `json:"serviceType"`
-	Status      string    `json:"status"`
-	Version     int32     `json:"version"`
-	CreatedAt   time.Time `json:"createdAt"`
+	Id             string     `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	ServiceType    string     `json:"serviceType"`
+	Status         string     `json:"status"`
+	OrganizationId string     `json:"organizationId"`
+	Type           string     `json:"type,omitempty"`
+	Version        int32      `json:"version"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	PublishAt      *time.Time `json:"publishAt,omitempty"`
+	Criteria       []string   `json:"criteria,omitempty"`
+	BudgetMin      *float64   `json:"budgetMin,omitempty"`
+	BudgetMax      *float64   `json:"budgetMax,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
 }
 
 type TenderPatchRequest struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	ServiceType string `json:"serviceType,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ServiceType string     `json:"serviceType,omitempty"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+}
+
+type TenderTransition struct {
+	Id        string    `json:"id"`
+	TenderId  string    `json:"tenderId"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TenderTransitionsResponse struct {
+	Status    string             `json:"status"`
+	Available []string           `json:"available"`
+	History   []TenderTransition `json:"history"`
+}
+
+type Tender struct {
+	Id             string     `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	ServiceType    string     `json:"serviceType"`
+	Status         string     `json:"status"`
+	Type           string     `json:"type"`
+	OrganizationId string     `json:"organizationId"`
+	Version        int32      `json:"version"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	PublishAt      *time.Time `json:"publishAt,omitempty"`
+	Criteria       []string   `json:"criteria,omitempty"`
+	Budget         Budget     `json:"budget"`
+}
+
+type Budget struct {
+	Min        *float64 `json:"min,omitempty"`
+	Max        *float64 `json:"max,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+	Visibility string   `json:"visibility"`
+	Policy     string   `json:"policy"`
+}
+
+// BudgetReport compares the prices of a tender's bids with its budget.
+type BudgetReport struct {
+	TenderId string             `json:"tenderId"`
+	Budget   Budget             `json:"budget"`
+	Bids     []BudgetReportLine `json:"bids"`
+}
+
+type BudgetReportLine struct {
+	BidId  string   `json:"bidId"`
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	Price  *float64 `json:"price,omitempty"`
+	// DeltaToMax is the price minus the budget maximum, negative when the
+	// bid is cheaper.
+	DeltaToMax  *float64 `json:"deltaToMax,omitempty"`
+	OutOfBudget bool     `json:"outOfBudget"`
+}
+
+// CommitteeReport gathers what a procurement committee reviews about a
+// tender: its bids, the feedback left on them and the votes cast.
+type CommitteeReport struct {
+	Tender   Tender
+	Bids     []CommitteeBid
+	Feedback []CommitteeFeedback
+	Votes    []CommitteeVote
+}
+
+type CommitteeBid struct {
+	BidId      string
+	Name       string
+	AuthorType string
+	AuthorId   string
+	// Author is the organization name or the username of the author.
+	Author    string
+	Version   int
+	Status    string
+	Price     *float64
+	VotesCast int
+	CreatedAt time.Time
+}
+
+type CommitteeFeedback struct {
+	BidId       string
+	BidName     string
+	Author      string
+	Side        string
+	Description string
+	Rating      *int
+	CreatedAt   time.Time
+}
+
+// CommitteeVote is a single vote together with the decision it counts
+// towards.
+type CommitteeVote struct {
+	BidId       string
+	BidName     string
+	LotId       string
+	Username    string
+	Vote        string
+	Decision    string
+	NumApproved int
 }
@@ -0,0 +1,75 @@
+package transfer
+
+import (
+	"tender_system/internal/models/conflict"
+	"time"
+)
+
+// Tender is a tender as it is exported and imported in bulk, so that an
+// export imports as it is, in whatever status and version it reached. On
+// import an empty id is generated, the status defaults to Created and the
+// version to 1, the creation time to now and the creator to the importing
+// user.
+type Tender struct {
+	Id              string     `json:"id,omitempty" validate:"omitempty,uuid"`
+	Name            string     `json:"name" validate:"required,max=100"`
+	Description     string     `json:"description" validate:"required,max=500"`
+	ServiceType     string     `json:"serviceType" validate:"oneof=Construction Delivery Manufacture"`
+	Status          string     `json:"status,omitempty" validate:"oneof=Created Published Closed Cancelled Awarded"`
+	OrganizationId  string     `json:"organizationId" validate:"required,uuid"`
+	CreatorUsername string     `json:"creatorUsername,omitempty" validate:"max=100"`
+	Version         int32      `json:"version,omitempty" validate:"gte=1"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	Deadline        *time.Time `json:"deadline,omitempty"`
+}
+
+// Bid is a bid as it is exported and imported in bulk, decided or not. On
+// import an empty id is generated, the status defaults to Draft and the
+// version to 1 and the creation time to now.
+type Bid struct {
+	Id          string    `json:"id,omitempty" validate:"omitempty,uuid"`
+	Name        string    `json:"name" validate:"required,max=100"`
+	Description string    `json:"description" validate:"max=500"`
+	TenderId    string    `json:"tenderId" validate:"required,uuid"`
+	AuthorType  string    `json:"authorType" validate:"oneof=Organization User"`
+	AuthorId    string    `json:"authorId" validate:"required,uuid"`
+	Status      string    `json:"status,omitempty" validate:"oneof=Draft Submitted Withdrawn Approved Rejected"`
+	Price       *float64  `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Version     int       `json:"version,omitempty" validate:"gte=1"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// OutOfBudget and Conflicts are worked out on import and never read
+	// from or written to a file.
+	OutOfBudget bool               `json:"-"`
+	Conflicts   []conflict.Finding `json:"-"`
+}
+
+// TenderFilter selects the tenders to export. Only tenders of the listed
+// organizations are included.
+type TenderFilter struct {
+	OrganizationIds []string
+	ServiceType     string
+	Status          string
+}
+
+// BidFilter selects the bids to export: bids on tenders of the listed
+// organizations and bids placed by the listed authors.
+type BidFilter struct {
+	OrganizationIds []string
+	AuthorIds       []string
+	TenderId        string
+	Status          string
+}
+
+type RowError struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+// Report is the outcome of an import. Nothing is imported when any row is
+// invalid.
+type Report struct {
+	Reason   string     `json:"reason,omitempty"`
+	Imported int        `json:"imported"`
+	Errors   []RowError `json:"errors"`
+}
@@ -16,3 +16,32 @@ type OrganizationResponsible struct {
 	OrganizationId string `json:"organization_id"`
 	UserId         string `json:"user_id"`
 }
+
+type Member struct {
+	UserId   string   `json:"userId"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
+
+type EmployeeRequest struct {
+	Username  string `json:"username" validate:"required,max=50"`
+	FirstName string `json:"first_name" validate:"max=50"`
+	LastName  string `json:"last_name" validate:"max=50"`
+}
+
+type EmployeePatchRequest struct {
+	FirstName string `json:"first_name,omitempty" validate:"max=50"`
+	LastName  string `json:"last_name,omitempty" validate:"max=50"`
+}
+
+type Membership struct {
+	OrganizationId   string   `json:"organizationId"`
+	OrganizationName string   `json:"organizationName"`
+	OrganizationType string   `json:"organizationType"`
+	Roles            []string `json:"roles"`
+}
+
+type Profile struct {
+	User
+	Organizations []Membership `json:"organizations"`
+}
@@ -0,0 +1,86 @@
+// Package notify delivers tender and bid events to users through their in-app
+// inbox and by email, in the language and on the channels they chose.
+package notify
+
+import (
+	"log/slog"
+	"tender_system/internal/models/notification"
+)
+
+type Store interface {
+	ReadNotificationSettings(userId string) (notification.Settings, error)
+	SaveNotification(n notification.Notification) (notification.Notification, error)
+}
+
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+type Notifier struct {
+	log   *slog.Logger
+	store Store
+	mail  Sender
+}
+
+// New returns a notifier. A nil mail sender disables email.
+func New(log *slog.Logger, store Store, mail Sender) *Notifier {
+	return &Notifier{log: log, store: store, mail: mail}
+}
+
+// Notify delivers the event to every user once. Failures are logged rather
+// than returned, since the action that caused the event already succeeded;
+// mail is sent in the background.
+func (n *Notifier) Notify(userIds []string, ev notification.Event) {
+	seen := make(map[string]bool, len(userIds))
+	for _, userId := range userIds {
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+
+		err := n.deliver(userId, ev)
+		if err != nil {
+			n.log.Error("Failed to deliver notification", slog.String("event", ev.Type), slog.String("user", userId), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+	}
+}
+
+func (n *Notifier) deliver(userId string, ev notification.Event) error {
+	settings, err := n.store.ReadNotificationSettings(userId)
+	if err != nil {
+		return err
+	}
+	if settings.IsMuted(ev.Type) {
+		return nil
+	}
+
+	subject, body, err := Render(ev, settings.Language)
+	if err != nil {
+		return err
+	}
+
+	if settings.InApp {
+		_, err = n.store.SaveNotification(notification.Notification{
+			UserId:   userId,
+			Event:    ev.Type,
+			TenderId: ev.TenderId,
+			BidId:    ev.BidId,
+			Subject:  subject,
+			Body:     body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if settings.Email && settings.EmailAddress != "" && n.mail != nil {
+		go func() {
+			err := n.mail.Send(settings.EmailAddress, subject, body)
+			if err != nil {
+				n.log.Error("Failed to send notification email", slog.String("event", ev.Type), slog.String("user", userId), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+		}()
+	}
+
+	return nil
+}
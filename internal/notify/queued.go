@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"tender_system/internal/models/job"
+	"time"
+)
+
+// EmailJob is the background job kind delivering a notification email.
+const EmailJob = "send_email"
+
+type Email struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type Enqueuer interface {
+	Enqueue(kind string, payload any, runAt time.Time) (job.Job, error)
+}
+
+// Queued hands mail to the background job queue, which retries deliveries
+// that fail and survives restarts.
+type Queued struct {
+	queue Enqueuer
+}
+
+func NewQueued(queue Enqueuer) *Queued {
+	return &Queued{queue: queue}
+}
+
+func (m *Queued) Send(to, subject, body string) error {
+	_, err := m.queue.Enqueue(EmailJob, Email{To: to, Subject: subject, Body: body}, time.Time{})
+	return err
+}
+
+// Deliver returns the job handler sending queued mail through the sender.
+func Deliver(mail Sender) func(ctx context.Context, e Email) error {
+	return func(ctx context.Context, e Email) error {
+		return mail.Send(e.To, e.Subject, e.Body)
+	}
+}
@@ -0,0 +1,269 @@
+package notify_test
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/notify"
+	"testing"
+	"time"
+)
+
+// received is a message accepted by the SMTP stand-in.
+type received struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP starts a minimal SMTP server accepting every message without
+// authentication, the way MailHog does, and returns its address.
+func serveSMTP(t *testing.T) (string, <-chan received) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan received, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go session(conn, messages)
+		}
+	}()
+
+	return ln.Addr().String(), messages
+}
+
+func session(conn net.Conn, messages chan<- received) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		io.WriteString(conn, line+"\r\n")
+	}
+
+	reply("220 localhost ESMTP")
+	var msg received
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0])
+
+		switch {
+		case verb == "EHLO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case verb == "HELO":
+			reply("250 localhost")
+		case strings.HasPrefix(strings.ToUpper(cmd), "MAIL FROM:"):
+			msg = received{from: path(cmd[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(strings.ToUpper(cmd), "RCPT TO:"):
+			msg.to = append(msg.to, path(cmd[len("RCPT TO:"):]))
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			msg.data = data.String()
+			messages <- msg
+			reply("250 OK")
+		case verb == "RSET" || verb == "NOOP":
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// path extracts the address of a MAIL or RCPT argument, dropping any
+// parameters such as BODY=8BITMIME.
+func path(arg string) string {
+	start, end := strings.Index(arg, "<"), strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return strings.TrimSpace(arg)
+	}
+	return arg[start+1 : end]
+}
+
+func newMailer(t *testing.T, addr string) *notify.SMTP {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notify.NewSMTP(notify.SMTPConfig{Host: host, Port: port, From: "tenders@example.com"})
+}
+
+// parse reads a received message back with its subject decoded.
+func parse(t *testing.T, m received) (*mail.Message, string, string) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(m.data))
+	if err != nil {
+		t.Fatalf("malformed message: %v\n%s", err, m.data)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg, subject, strings.ReplaceAll(string(body), "\r\n", "\n")
+}
+
+func wait(t *testing.T, messages <-chan received) received {
+	t.Helper()
+
+	select {
+	case m := <-messages:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message arrived")
+		return received{}
+	}
+}
+
+func TestSMTPTemplates(t *testing.T) {
+	cases := []struct {
+		name    string
+		lang    string
+		event   notification.Event
+		subject string
+		body    string
+	}{
+		{
+			name:    "bid decided in English",
+			lang:    "en",
+			event:   notification.Event{Type: notification.BidDecided, TenderName: "Office cleaning", BidName: "CleanCo", Status: "Approved"},
+			subject: `Your bid "CleanCo" was approved`,
+			body:    "Your bid \"CleanCo\" for the tender \"Office cleaning\" was approved.\n",
+		},
+		{
+			name:    "bid decided in Russian",
+			lang:    "ru",
+			event:   notification.Event{Type: notification.BidDecided, TenderName: "Уборка офиса", BidName: "Чистота", Status: "Rejected"},
+			subject: "Предложение «Чистота» отклонено",
+			body:    "Ваше предложение «Чистота» по тендеру «Уборка офиса» отклонено.\n",
+		},
+		{
+			name:    "tender status in Russian",
+			lang:    "ru",
+			event:   notification.Event{Type: notification.TenderStatusChanged, TenderName: "Уборка офиса", Status: "Closed", Text: "Аукцион завершён."},
+			subject: "Тендер «Уборка офиса» закрыт",
+			body:    "Тендер «Уборка офиса», в котором вы участвуете, закрыт.\n\nАукцион завершён.\n",
+		},
+		{
+			name:    "feedback in an unsupported language",
+			lang:    "de",
+			event:   notification.Event{Type: notification.FeedbackLeft, TenderName: "Office cleaning", BidName: "CleanCo", Text: "Great price"},
+			subject: `New feedback on your bid "CleanCo"`,
+			body:    "Feedback was left on your bid \"CleanCo\" for the tender \"Office cleaning\":\n\nGreat price\n",
+		},
+	}
+
+	addr, messages := serveSMTP(t)
+	mailer := newMailer(t, addr)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			subject, body, err := notify.Render(c.event, c.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = mailer.Send("bob@example.com", subject, body)
+			if err != nil {
+				t.Fatalf("send: %v", err)
+			}
+
+			m := wait(t, messages)
+			if m.from != "tenders@example.com" || len(m.to) != 1 || m.to[0] != "bob@example.com" {
+				t.Fatalf("envelope from %s to %v", m.from, m.to)
+			}
+
+			msg, gotSubject, gotBody := parse(t, m)
+			if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=UTF-8" {
+				t.Fatalf("content type %q", ct)
+			}
+			if gotSubject != c.subject {
+				t.Fatalf("subject %q, want %q", gotSubject, c.subject)
+			}
+			if gotBody != c.body {
+				t.Fatalf("body %q, want %q", gotBody, c.body)
+			}
+		})
+	}
+}
+
+// settingsStore holds the settings of a single user.
+type settingsStore struct {
+	settings notification.Settings
+}
+
+func (s settingsStore) ReadNotificationSettings(string) (notification.Settings, error) {
+	return s.settings, nil
+}
+
+func (s settingsStore) SaveNotification(n notification.Notification) (notification.Notification, error) {
+	return n, nil
+}
+
+// TestNotifierMail checks that the notifier mails events in the language the
+// user chose.
+func TestNotifierMail(t *testing.T) {
+	addr, messages := serveSMTP(t)
+	store := settingsStore{notification.Settings{Language: "ru", Email: true, EmailAddress: "bob@example.com"}}
+	notifier := notify.New(slog.New(slog.NewTextHandler(io.Discard, nil)), store, newMailer(t, addr))
+
+	notifier.Notify([]string{"user-bob", "user-bob"}, notification.Event{
+		Type:       notification.TenderStatusChanged,
+		TenderName: "Уборка офиса",
+		Status:     "Published",
+	})
+
+	_, subject, body := parse(t, wait(t, messages))
+	if subject != "Тендер «Уборка офиса» опубликован" {
+		t.Fatalf("subject %q", subject)
+	}
+	if !strings.Contains(body, "опубликован") {
+		t.Fatalf("body %q", body)
+	}
+
+	select {
+	case m := <-messages:
+		t.Fatalf("the same user was mailed twice: %s", m.data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"tender_system/internal/models/notification"
+	"text/template"
+)
+
+type message struct {
+	subject string
+	body    string
+}
+
+var statusNames = map[string]map[string]string{
+	"ru": {
+		"Created":   "создан",
+		"Published": "опубликован",
+		"Closed":    "закрыт",
+		"Cancelled": "отменён",
+		"Awarded":   "завершён выбором победителя",
+		"Approved":  "одобрено",
+		"Rejected":  "отклонено",
+	},
+	"en": {
+		"Created":   "created",
+		"Published": "published",
+		"Closed":    "closed",
+		"Cancelled": "cancelled",
+		"Awarded":   "awarded",
+		"Approved":  "approved",
+		"Rejected":  "rejected",
+	},
+}
+
+var sources = map[string]map[string]message{
+	notification.FeedbackLeft: {
+		"en": {
+			subject: `New feedback on your bid "{{.BidName}}"`,
+			body:    "Feedback was left on your bid \"{{.BidName}}\" for the tender \"{{.TenderName}}\":\n\n{{.Text}}\n",
+		},
+		"ru": {
+			subject: `Новый отзыв на предложение «{{.BidName}}»`,
+			body:    "На ваше предложение «{{.BidName}}» по тендеру «{{.TenderName}}» оставлен отзыв:\n\n{{.Text}}\n",
+		},
+	},
+	notification.FeedbackReplied: {
+		"en": {
+			subject: `New reply on the bid "{{.BidName}}"`,
+			body:    "Your comment on the bid \"{{.BidName}}\" for the tender \"{{.TenderName}}\" got a reply:\n\n{{.Text}}\n",
+		},
+		"ru": {
+			subject: `Новый ответ по предложению «{{.BidName}}»`,
+			body:    "На ваш комментарий к предложению «{{.BidName}}» по тендеру «{{.TenderName}}» ответили:\n\n{{.Text}}\n",
+		},
+	},
+	notification.BidDecided: {
+		"en": {
+			subject: `Your bid "{{.BidName}}" was {{status .Status}}`,
+			body:    "Your bid \"{{.BidName}}\" for the tender \"{{.TenderName}}\" was {{status .Status}}.\n",
+		},
+		"ru": {
+			subject: `Предложение «{{.BidName}}» {{status .Status}}`,
+			body:    "Ваше предложение «{{.BidName}}» по тендеру «{{.TenderName}}» {{status .Status}}.\n",
+		},
+	},
+	notification.TenderStatusChanged: {
+		"en": {
+			subject: `Tender "{{.TenderName}}" is {{status .Status}}`,
+			body:    "The tender \"{{.TenderName}}\" you placed a bid on is now {{status .Status}}.{{if .Text}}\n\n{{.Text}}{{end}}\n",
+		},
+		"ru": {
+			subject: `Тендер «{{.TenderName}}» {{status .Status}}`,
+			body:    "Тендер «{{.TenderName}}», в котором вы участвуете, {{status .Status}}.{{if .Text}}\n\n{{.Text}}{{end}}\n",
+		},
+	},
+}
+
+type compiled struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var templates = compile()
+
+func compile() map[string]map[string]compiled {
+	result := make(map[string]map[string]compiled)
+	for event, languages := range sources {
+		result[event] = make(map[string]compiled)
+		for lang, src := range languages {
+			funcs := template.FuncMap{"status": func(status string) string {
+				if name, ok := statusNames[lang][status]; ok {
+					return name
+				}
+				return status
+			}}
+			result[event][lang] = compiled{
+				subject: template.Must(template.New(event + "." + lang + ".subject").Funcs(funcs).Parse(src.subject)),
+				body:    template.Must(template.New(event + "." + lang + ".body").Funcs(funcs).Parse(src.body)),
+			}
+		}
+	}
+	return result
+}
+
+// Render builds the subject and body of the event in the given language,
+// falling back to English.
+func Render(ev notification.Event, lang string) (string, string, error) {
+	languages, ok := templates[ev.Type]
+	if !ok {
+		return "", "", fmt.Errorf("no template for event %s", ev.Type)
+	}
+
+	tmpl, ok := languages[lang]
+	if !ok {
+		tmpl = languages["en"]
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, ev); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&body, ev); err != nil {
+		return "", "", err
+	}
+
+	return subject.String(), body.String(), nil
+}
@@ -0,0 +1,82 @@
+// Package scheduler runs periodic tasks on exactly one server replica, the
+// one holding the leader lease. Work that runs at a set time goes through the
+// background job queue instead.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Leader interface {
+	Acquire(ctx context.Context) (bool, error)
+	Release()
+}
+
+type task struct {
+	name string
+	run  func(now time.Time) error
+}
+
+type Scheduler struct {
+	log      *slog.Logger
+	leader   Leader
+	interval time.Duration
+	tasks    []task
+	leading  bool
+}
+
+func New(log *slog.Logger, leader Leader, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		log:      log,
+		leader:   leader,
+		interval: interval,
+	}
+}
+
+// Every registers a task the leader runs on every tick.
+func (s *Scheduler) Every(name string, run func(now time.Time) error) {
+	s.tasks = append(s.tasks, task{name: name, run: run})
+}
+
+// Run ticks until the context is done, then gives up leadership.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer s.leader.Release()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(ctx, now.UTC())
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	leading, err := s.leader.Acquire(ctx)
+	if err != nil {
+		s.log.Error("Failed to acquire scheduler lease", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+	}
+	if leading != s.leading {
+		s.leading = leading
+		if leading {
+			s.log.Info("scheduler became leader")
+		} else {
+			s.log.Info("scheduler lost leadership")
+		}
+	}
+	if !leading {
+		return
+	}
+
+	for _, t := range s.tasks {
+		err = t.run(now)
+		if err != nil {
+			s.log.Error("Failed to run scheduled task", slog.String("task", t.name), slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		}
+	}
+}
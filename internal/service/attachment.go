@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tender_system/internal/authz"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/attachment"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/storage"
+)
+
+// AttachmentRepository is the data access of the AttachmentService.
+type AttachmentRepository interface {
+	responsibleReader
+	userFetcher
+	auditLog
+
+	GetTender(tenderId string) (tender.Tender, error)
+	GetBid(bidId string) (bids.Bid, error)
+	AddAttachment(att attachment.Attachment) (attachment.Attachment, error)
+	ListAttachments(entityType, entityId string) ([]attachment.Attachment, error)
+	GetAttachment(entityType, entityId, attachmentId string) (attachment.Attachment, error)
+	RemoveAttachment(entityType, entityId, attachmentId string) error
+}
+
+type AttachmentService struct {
+	repo       AttachmentRepository
+	authorizer *authz.Authorizer
+}
+
+func NewAttachmentService(repo AttachmentRepository, authorizer *authz.Authorizer) *AttachmentService {
+	return &AttachmentService{repo: repo, authorizer: authorizer}
+}
+
+// AuthorizeAttachments checks that the user may read, or with write change,
+// the attachments of a tender or a bid. Anyone may read the attachments of a
+// published tender, other tender attachments need the permission to view or
+// edit the tender. Bid attachments are changed by the bid's authors and read
+// by them and by the members allowed to view the bids of the tender.
+func (s *AttachmentService) AuthorizeAttachments(entityType, entityId, username string, write bool) error {
+	switch entityType {
+	case attachment.Tender:
+		return s.authorizeTenderAttachments(entityId, username, write)
+	case attachment.Bid:
+		return s.authorizeBidAttachments(entityId, username, write)
+	default:
+		return fmt.Errorf("%w: unknown entity type %q", storage.ErrBadRequest, entityType)
+	}
+}
+
+func (s *AttachmentService) authorizeTenderAttachments(tenderId, username string, write bool) error {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return err
+	}
+
+	if !write && ten.Status == tenderdomain.Published {
+		return nil
+	}
+
+	perm := authz.ViewTender
+	if write {
+		perm = authz.EditTender
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, perm)
+	return err
+}
+
+func (s *AttachmentService) authorizeBidAttachments(bidId, username string, write bool) error {
+	bid, err := s.repo.GetBid(bidId)
+	if err != nil {
+		return err
+	}
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return storage.ErrUserNotFound
+	}
+
+	ok, err := actsForAuthor(s.repo, s.authorizer, bid, usr)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	if write {
+		return fmt.Errorf("%w: only the author may change the bid attachments", storage.ErrForbidden)
+	}
+
+	ten, err := s.repo.GetTender(bid.TenderId)
+	if err != nil {
+		return err
+	}
+
+	return s.authorizer.Require(ten.OrganizationId, usr.Id, authz.ViewBids)
+}
+
+// AddAttachment stores the metadata of an uploaded file. The uploader must
+// have been authorized with AuthorizeAttachments.
+func (s *AttachmentService) AddAttachment(ctx context.Context, att attachment.Attachment) (attachment.Attachment, error) {
+	var resp attachment.Attachment
+	err := atomically(s.repo, func(repo AttachmentRepository) error {
+		before, err := snapshot(repo, att.EntityType, att.EntityId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.AddAttachment(att)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, att.EntityType, att.EntityId, "attachment "+resp.FileName+" added", att.UploadedBy, before)
+	})
+	if err != nil {
+		return attachment.Attachment{}, err
+	}
+
+	return resp, nil
+}
+
+func (s *AttachmentService) ListAttachments(entityType, entityId string) ([]attachment.Attachment, error) {
+	return s.repo.ListAttachments(entityType, entityId)
+}
+
+func (s *AttachmentService) GetAttachment(entityType, entityId, attachmentId string) (attachment.Attachment, error) {
+	return s.repo.GetAttachment(entityType, entityId, attachmentId)
+}
+
+// RemoveAttachment removes an attachment on behalf of a user authorized with
+// AuthorizeAttachments.
+func (s *AttachmentService) RemoveAttachment(ctx context.Context, entityType, entityId, attachmentId, username string) error {
+	att, err := s.repo.GetAttachment(entityType, entityId, attachmentId)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo AttachmentRepository) error {
+		before, err := snapshot(repo, entityType, entityId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.RemoveAttachment(entityType, entityId, attachmentId)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, entityType, entityId, "attachment "+att.FileName+" removed", username, before)
+	})
+}
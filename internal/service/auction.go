@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"tender_system/internal/authz"
+	auctiondomain "tender_system/internal/domain/auction"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// AuctionActor is recorded as the actor of transitions made when an auction
+// finishes.
+const AuctionActor = "system"
+
+// AuctionRepository is the data access of the AuctionService.
+type AuctionRepository interface {
+	recipientReader
+	userFetcher
+	auditLog
+
+	GetBid(bidId string) (bids.Bid, error)
+	DecideBid(bid *bids.BidResponse, decision string) error
+	ChangeTenderStatus(tenderId, to, actor, reason string) error
+	GetAuction(tenderId string) (auction.Auction, error)
+	UpdateAuction(tenderId string, prev, next auction.State) error
+	ListExpiredAuctions(now time.Time) ([]auction.Auction, error)
+}
+
+type AuctionService struct {
+	repo       AuctionRepository
+	authorizer *authz.Authorizer
+	notifier   Notifier
+}
+
+func NewAuctionService(repo AuctionRepository, authorizer *authz.Authorizer, notifier Notifier) *AuctionService {
+	return &AuctionService{repo: repo, authorizer: authorizer, notifier: notifier}
+}
+
+// ReadAuction returns the public state of a tender's reverse auction. It is
+// visible to anyone once the tender has been published and to members
+// allowed to view the organization's tenders before that. Reading settles
+// the rounds that ended since the last read.
+func (s *AuctionService) ReadAuction(ctx context.Context, tenderId, username string) (auction.View, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return auction.View{}, err
+	}
+
+	if ten.Status == tenderdomain.Created {
+		_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewTender)
+		if err != nil {
+			return auction.View{}, err
+		}
+	}
+
+	a, err := s.repo.GetAuction(tenderId)
+	if err != nil {
+		return auction.View{}, err
+	}
+
+	a, err = s.settle(ctx, a, time.Now().UTC())
+	if err != nil {
+		return auction.View{}, err
+	}
+
+	return auction.View{
+		TenderId:      a.TenderId,
+		Status:        a.State.Status,
+		Round:         a.State.Round,
+		MaxRounds:     a.Settings.MaxRounds,
+		RoundEndsAt:   a.State.RoundEndsAt,
+		StartPrice:    a.Settings.StartPrice,
+		MinDecrement:  a.Settings.MinDecrement,
+		BestPrice:     a.State.BestPrice,
+		RoundBids:     a.State.RoundBids,
+		ProposedBidId: a.State.ProposedBidId,
+	}, nil
+}
+
+// SettleExpired advances every auction whose round ended before now and
+// closes the finished ones.
+func (s *AuctionService) SettleExpired(now time.Time) error {
+	const op = "service.AuctionService.SettleExpired"
+
+	expired, err := s.repo.ListExpiredAuctions(now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, a := range expired {
+		_, err = s.settle(context.Background(), a, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// settle stores the state the auction has reached by now. When it finishes,
+// the lowest bid is submitted for decision and the tender closed. Losing a
+// race against another settle is not an error; the stored state is returned.
+func (s *AuctionService) settle(ctx context.Context, a auction.Auction, now time.Time) (auction.Auction, error) {
+	const op = "service.AuctionService.settle"
+
+	next := auctiondomain.Advance(a.Settings, a.State, now)
+	if next.Status == a.State.Status && next.Round == a.State.Round {
+		return a, nil
+	}
+
+	err := atomically(s.repo, func(repo AuctionRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, a.TenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.UpdateAuction(a.TenderId, a.State, next)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, a.TenderId, fmt.Sprintf("reverse auction %s in round %d", next.Status, next.Round), AuctionActor, before)
+	})
+	if errors.Is(err, storage.ErrConflict) {
+		return s.repo.GetAuction(a.TenderId)
+	}
+	if err != nil {
+		return auction.Auction{}, fmt.Errorf("%s: %w", op, err)
+	}
+	a.State = next
+
+	if next.Status != auctiondomain.Finished {
+		return a, nil
+	}
+
+	err = s.propose(ctx, a.TenderId, next.ProposedBidId)
+	if err != nil {
+		return auction.Auction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return a, nil
+}
+
+// propose submits the winning bid for decision and closes the tender to
+// further bids.
+func (s *AuctionService) propose(ctx context.Context, tenderId, bidId string) error {
+	if bidId != "" {
+		bid, err := s.repo.GetBid(bidId)
+		if err != nil {
+			return err
+		}
+
+		if biddomain.Normalize(bid.Status) == biddomain.Draft {
+			err = atomically(s.repo, func(repo AuctionRepository) error {
+				before, err := snapshot(repo, auditdomain.Bid, bidId)
+				if err != nil {
+					return err
+				}
+
+				resp := toResponse(bid)
+				err = repo.DecideBid(&resp, biddomain.Submitted)
+				if err != nil {
+					return err
+				}
+
+				return recordChange(ctx, repo, auditdomain.Bid, bidId, "bid submitted by the reverse auction", AuctionActor, before)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return err
+	}
+	if ten.Status != tenderdomain.Published {
+		return nil
+	}
+
+	err = atomically(s.repo, func(repo AuctionRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.ChangeTenderStatus(tenderId, tenderdomain.Closed, AuctionActor, "reverse auction finished")
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "reverse auction finished", AuctionActor, before)
+	})
+	if err != nil {
+		return err
+	}
+
+	return notifyTenderStatus(s.repo, s.notifier, tenderId, "The reverse auction has finished.")
+}
+
+// auctionStore reads and advances reverse auctions.
+type auctionStore interface {
+	GetAuction(tenderId string) (auction.Auction, error)
+	UpdateAuction(tenderId string, prev, next auction.State) error
+}
+
+// placeAuctionPrice validates a price offered on the tender. Prices on
+// reverse auctions must undercut the current best one; the returned function
+// records the accepted price for the given bid and must be called once the
+// bid is stored. Prices on other tenders are accepted as they are.
+func placeAuctionPrice(repo auctionStore, tenderId, bidStatus string, price float64) (func(bidId string) error, error) {
+	a, err := repo.GetAuction(tenderId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return func(string) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !auctiondomain.CanBid(bidStatus) {
+		return nil, fmt.Errorf("%w: %s bids cannot take part in the auction", storage.ErrConflict, bidStatus)
+	}
+
+	price = math.Round(price*100) / 100
+	next, err := auctiondomain.Place(a.Settings, a.State, "", price, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", storage.ErrConflict, err)
+	}
+
+	return func(bidId string) error {
+		next.BestBidId = bidId
+		return repo.UpdateAuction(tenderId, a.State, next)
+	}, nil
+}
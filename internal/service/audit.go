@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/storage"
+)
+
+// verifyBatch is the number of audit entries verified per query.
+const verifyBatch = 500
+
+// AuditRepository is the data access of the AuditService.
+type AuditRepository interface {
+	userFetcher
+
+	ListAudit(f audit.Filter) ([]audit.Entry, error)
+	ReadAuditChain(afterId int64, limit int) ([]audit.Entry, error)
+}
+
+type AuditService struct {
+	repo       AuditRepository
+	authorizer *authz.Authorizer
+}
+
+func NewAuditService(repo AuditRepository, authorizer *authz.Authorizer) *AuditService {
+	return &AuditService{repo: repo, authorizer: authorizer}
+}
+
+// ListAudit returns the audit entries of an organization to members allowed
+// to view its audit log. Without an organization users only see the entries
+// of their own actions.
+func (s *AuditService) ListAudit(username string, f audit.Filter) ([]audit.Entry, error) {
+	const op = "service.AuditService.ListAudit"
+
+	if f.OrganizationId == "" {
+		if _, err := s.repo.FetchUser(username); err != nil {
+			return nil, storage.ErrUserNotFound
+		}
+		if f.Actor != "" && f.Actor != username {
+			return nil, fmt.Errorf("%w: select an organization to see the actions of others", storage.ErrForbidden)
+		}
+		f.Actor = username
+	} else {
+		_, err := authorize(s.repo, s.authorizer, f.OrganizationId, username, authz.ViewAudit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.repo.ListAudit(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// VerifyAudit recomputes the whole hash chain and reports the first entry
+// that was changed, removed or inserted out of order.
+func (s *AuditService) VerifyAudit(username string) (audit.Verification, error) {
+	const op = "service.AuditService.VerifyAudit"
+
+	if _, err := s.repo.FetchUser(username); err != nil {
+		return audit.Verification{}, storage.ErrUserNotFound
+	}
+
+	var result audit.Verification
+	var last audit.Entry
+	for {
+		entries, err := s.repo.ReadAuditChain(last.Id, verifyBatch)
+		if err != nil {
+			return audit.Verification{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		if broken := auditdomain.Verify(last, entries); broken != 0 {
+			result.BrokenAt = broken
+			return result, nil
+		}
+
+		result.Entries += len(entries)
+		last = entries[len(entries)-1]
+	}
+
+	result.Valid = true
+	return result, nil
+}
@@ -0,0 +1,942 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	conflictdomain "tender_system/internal/domain/conflict"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/award"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// MaxQuorum caps the number of approvals needed to accept a bid.
+const MaxQuorum = 3
+
+// BidRepository is the data access of the BidService.
+type BidRepository interface {
+	auctionStore
+	auditLog
+	organizationReader
+	recipientReader
+	responsibleReader
+	userFetcher
+	voteConflictStore
+
+	GetBid(bidId string) (bids.Bid, error)
+	// LockBid reads the bid and locks it, and its tender, until the
+	// transaction it runs in ends.
+	LockBid(bidId string) (bids.Bid, error)
+	OrganizationExists(organizationId string) (bool, error)
+	EmployeeExists(userId string) (bool, error)
+	ListTenderBids(tenderId string, authorIds []string, lotId string, limit, offset int) ([]bids.BidResponse, error)
+	ListAuthorBids(authorIds []string, limit, offset int) ([]bids.BidResponse, error)
+	SaveComment(c bids.Comment) (bids.Comment, error)
+	GetComment(bidId, commentId string) (bids.Comment, error)
+	ListComments(bidId string) ([]bids.Comment, error)
+	UpdateComment(commentId, description string, rating *int) (bids.Comment, error)
+	DeleteComment(commentId string) error
+	ReadAuthorFeedback(tenderId, authorId string, limit, offset int) ([]bids.BidReviewResponse, error)
+	ListLots(tenderId string) ([]lot.Lot, error)
+	ReadBidLots(bidId string) ([]string, error)
+	CountOpenBidLots(bidId string) (int, error)
+	GetAward(tenderId, lotId string) (award.Award, error)
+	ReadDecision(bidId, lotId string) (bids.Decision, error)
+	HasVoted(bidId, lotId, userId string) (bool, error)
+	SaveVote(bidId, lotId, userId, username, decision string) (bids.Decision, error)
+	CloseDecision(bidId, lotId string) error
+	DecideBid(bid *bids.BidResponse, decision string) error
+	AwardBid(bid *bids.BidResponse, lotId, actor string, guard func(status string) error) (award.Outcome, error)
+	SaveBid(bid bids.BidRequest) (bids.BidResponse, error)
+	ChangeBidStatus(bidId, status string, guard func(from string) error) (bids.BidResponse, error)
+	EditBid(bidId, name, desc string, price *float64, outOfBudget bool) (bids.BidResponse, error)
+	RollbackBid(bidId string, version int, guard func(current, restored *float64) error) (bids.BidResponse, error)
+}
+
+type BidService struct {
+	repo       BidRepository
+	authorizer *authz.Authorizer
+	notifier   Notifier
+}
+
+func NewBidService(repo BidRepository, authorizer *authz.Authorizer, notifier Notifier) *BidService {
+	return &BidService{repo: repo, authorizer: authorizer, notifier: notifier}
+}
+
+// CreateBid places a new bid in the Draft status. The author must exist, and
+// users may only bid once they belong to an organization. The bid is subject
+// to the conflict of interest rules of the tender's organization, must target
+// the tender's lots when it has any, and its price is checked against the
+// tender budget and, on reverse auctions, the best price so far. The author
+// is recorded as the actor, as the API names no other.
+func (s *BidService) CreateBid(ctx context.Context, req bids.BidRequest) (bids.BidResponse, error) {
+	const op = "service.BidService.CreateBid"
+
+	err := s.checkAuthor(req.AuthorType, req.AuthorId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	ten, err := s.repo.GetTender(req.TenderId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+	if !tenderdomain.AcceptsBids(ten.Status) {
+		return bids.BidResponse{}, fmt.Errorf("%w: the tender is %s", storage.ErrBadRequest, strings.ToLower(ten.Status))
+	}
+
+	findings, blocked, err := bidConflicts(s.repo, ten, req.AuthorType, req.AuthorId)
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if blocked {
+		err = s.repo.RecordConflicts(auditdomain.Tender, ten.Id, ten.OrganizationId, req.AuthorId, findings)
+		if err != nil {
+			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return bids.BidResponse{}, fmt.Errorf("%w: %s", storage.ErrForbidden, conflictdomain.Describe(findings))
+	}
+
+	err = s.checkBidLots(ten.Id, req.LotIds)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	if req.Price != nil {
+		outOfBudget, refusal := tenderdomain.CheckPrice(ten.Budget, *req.Price)
+		if refusal != "" {
+			return bids.BidResponse{}, fmt.Errorf("%w: %s", storage.ErrBadRequest, refusal)
+		}
+		req.OutOfBudget = outOfBudget
+	}
+
+	var resp bids.BidResponse
+	err = atomically(s.repo, func(repo BidRepository) error {
+		placePrice := func(string) error { return nil }
+		if req.Price != nil {
+			placePrice, err = placeAuctionPrice(repo, ten.Id, biddomain.Draft, *req.Price)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp, err = repo.SaveBid(req)
+		if err != nil {
+			return err
+		}
+
+		err = placePrice(resp.Id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = recordChange(ctx, repo, auditdomain.Bid, resp.Id, "bid created", req.AuthorId, audit.Subject{})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(findings) > 0 {
+			err = repo.RecordConflicts(auditdomain.Bid, resp.Id, ten.OrganizationId, req.AuthorId, findings)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// checkAuthor requires the author of a new bid to exist and, for users, to
+// belong to an organization.
+func (s *BidService) checkAuthor(authorType, authorId string) error {
+	const op = "service.BidService.checkAuthor"
+
+	if authorType == "Organization" {
+		ok, err := s.repo.OrganizationExists(authorId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !ok {
+			return storage.ErrUserNotFound
+		}
+		return nil
+	}
+
+	ok, err := s.repo.EmployeeExists(authorId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+
+	organizations, err := s.repo.ReadUserOrganizations(authorId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(organizations) == 0 {
+		return fmt.Errorf("%w: users must belong to an organization to bid", storage.ErrForbidden)
+	}
+
+	return nil
+}
+
+// checkBidLots requires a bid on a tender with lots to name at least one of
+// them and a bid on a tender without lots to name none.
+func (s *BidService) checkBidLots(tenderId string, lotIds []string) error {
+	const op = "service.BidService.checkBidLots"
+
+	lots, err := s.repo.ListLots(tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(lots) == 0 {
+		if len(lotIds) > 0 {
+			return fmt.Errorf("%w: the tender has no lots", storage.ErrBadRequest)
+		}
+		return nil
+	}
+
+	if len(lotIds) == 0 {
+		return fmt.Errorf("%w: the bid must target at least one lot", storage.ErrBadRequest)
+	}
+
+	for _, lotId := range lotIds {
+		if !slices.ContainsFunc(lots, func(l lot.Lot) bool { return l.Id == lotId }) {
+			return fmt.Errorf("%w: lot %s does not belong to the tender", storage.ErrBadRequest, lotId)
+		}
+	}
+
+	return nil
+}
+
+// GetBidStatus returns the status of a bid to its authors and to members
+// allowed to view the bids of the tender's organization.
+func (s *BidService) GetBidStatus(bidId, username string) (string, error) {
+	bid, err := s.repo.GetBid(bidId)
+	if err != nil {
+		return "", err
+	}
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return "", storage.ErrUserNotFound
+	}
+
+	ok, err := actsForAuthor(s.repo, s.authorizer, bid, usr)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return bid.Status, nil
+	}
+
+	ten, err := s.repo.GetTender(bid.TenderId)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.authorizer.Require(ten.OrganizationId, usr.Id, authz.ViewBids)
+	if err != nil {
+		return "", err
+	}
+
+	return bid.Status, nil
+}
+
+// ChangeBidStatus moves a bid to a status its author may choose, submitting
+// or withdrawing it, subject to the tender deadline. Decisions are left to
+// voting.
+func (s *BidService) ChangeBidStatus(ctx context.Context, bidId, status, username string) (bids.BidResponse, error) {
+	bid, usr, err := s.editableBid(bidId, username)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	if !biddomain.Valid(status) || biddomain.IsDecision(status) {
+		return bids.BidResponse{}, fmt.Errorf("%w: invalid status %q", storage.ErrBadRequest, status)
+	}
+
+	ten, err := s.repo.GetTender(bid.TenderId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	var resp bids.BidResponse
+	err = atomically(s.repo, func(repo BidRepository) error {
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.ChangeBidStatus(bidId, biddomain.Normalize(status), func(from string) error {
+			return biddomain.Transition(from, status, ten.Deadline, time.Now())
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, "bid status changed to "+resp.Status, usr.Username, before)
+	})
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// EditBid changes the name, description or price of a bid on behalf of its
+// author. Only drafts and submitted bids can be edited, and only until the
+// tender deadline. A new price is checked against the tender budget and, on
+// reverse auctions, must beat the best price so far. The checks run under
+// the locks of the bid and its tender, which the auction price and the bid
+// are written under in one transaction.
+func (s *BidService) EditBid(ctx context.Context, bidId, username, name, desc string, price *float64) (bids.BidResponse, error) {
+	_, usr, err := s.editableBid(bidId, username)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	var resp bids.BidResponse
+	err = atomically(s.repo, func(repo BidRepository) error {
+		bid, err := repo.LockBid(bidId)
+		if err != nil {
+			return err
+		}
+
+		ten, err := repo.GetTender(bid.TenderId)
+		if err != nil {
+			return err
+		}
+
+		if refusal := biddomain.CheckEdit(bid.Status, ten.Deadline, time.Now()); refusal != "" {
+			return fmt.Errorf("%w: %s", storage.ErrConflict, refusal)
+		}
+
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		outOfBudget := false
+		if price != nil {
+			var refusal string
+			outOfBudget, refusal = tenderdomain.CheckPrice(ten.Budget, *price)
+			if refusal != "" {
+				return fmt.Errorf("%w: %s", storage.ErrBadRequest, refusal)
+			}
+
+			placePrice, err := placeAuctionPrice(repo, ten.Id, bid.Status, *price)
+			if err != nil {
+				return err
+			}
+
+			err = placePrice(bid.Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp, err = repo.EditBid(bidId, name, desc, price, outOfBudget)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, "bid edited", usr.Username, before)
+	})
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// RollbackBid restores an earlier version of a bid as a new version on
+// behalf of its author. The bid keeps its status; on reverse auctions the
+// price may not change, since auction prices only ever go down.
+func (s *BidService) RollbackBid(ctx context.Context, bidId, username string, version int) (bids.BidResponse, error) {
+	bid, usr, err := s.editableBid(bidId, username)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	if version < 1 || version >= bid.Version {
+		return bids.BidResponse{}, fmt.Errorf("%w: the bid has no earlier version %d", storage.ErrBadRequest, version)
+	}
+
+	_, err = s.repo.GetAuction(bid.TenderId)
+	auctioned := err == nil
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return bids.BidResponse{}, err
+	}
+
+	var resp bids.BidResponse
+	err = atomically(s.repo, func(repo BidRepository) error {
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.RollbackBid(bidId, version, func(current, restored *float64) error {
+			if auctioned && !samePrice(current, restored) {
+				return fmt.Errorf("%w: rolling back would change the price offered in the auction", storage.ErrConflict)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, fmt.Sprintf("bid rolled back to version %d", version), usr.Username, before)
+	})
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// samePrice reports whether two optional prices are equal.
+func samePrice(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// editableBid resolves a bid the user may change as its author, together
+// with the user.
+func (s *BidService) editableBid(bidId, username string) (bids.Bid, user.User, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return bids.Bid{}, user.User{}, storage.ErrUserNotFound
+	}
+
+	bid, err := s.repo.GetBid(bidId)
+	if err != nil {
+		return bids.Bid{}, user.User{}, err
+	}
+
+	ok, err := actsForAuthor(s.repo, s.authorizer, bid, usr)
+	if err != nil {
+		return bids.Bid{}, user.User{}, err
+	}
+	if !ok {
+		return bids.Bid{}, user.User{}, fmt.Errorf("%w: only the author may change the bid", storage.ErrForbidden)
+	}
+
+	return bid, usr, nil
+}
+
+// responsibleReader lists the organizations a user is responsible for.
+type responsibleReader interface {
+	ReadResponsibleOrganizations(userId string) ([]string, error)
+}
+
+// actsForAuthor reports whether the user may act as the author of a bid. A
+// user bid belongs to its author and to the fellow responsibles of the
+// organizations the author is responsible for, an organization bid to the
+// members allowed to submit bids for the organization.
+func actsForAuthor(repo responsibleReader, authorizer *authz.Authorizer, bid bids.Bid, usr user.User) (bool, error) {
+	const op = "service.actsForAuthor"
+
+	if bid.AuthorType != "User" {
+		ok, err := authorizer.Can(bid.AuthorId, usr.Id, authz.SubmitBid)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		return ok, nil
+	}
+	if bid.AuthorId == usr.Id {
+		return true, nil
+	}
+
+	authorOrganizations, err := repo.ReadResponsibleOrganizations(bid.AuthorId)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	organizations, err := repo.ReadResponsibleOrganizations(usr.Id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, organizationId := range organizations {
+		if slices.Contains(authorOrganizations, organizationId) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ReadTenderBids lists all bids of a tender to members allowed to view them.
+// Everyone else only sees the bids they authored themselves or on behalf of
+// an organization they may submit bids for. When organizationId is set only
+// the bids visible while acting for that organization are listed, and when
+// lotId is set only the bids targeting that lot.
+func (s *BidService) ReadTenderBids(username, organizationId, tenderId, lotId string, limit, offset int) ([]bids.BidResponse, error) {
+	const op = "service.BidService.ReadTenderBids"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	if organizationId == "" || organizationId == ten.OrganizationId {
+		ok, err := s.authorizer.Can(ten.OrganizationId, usr.Id, authz.ViewBids)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if ok {
+			return s.repo.ListTenderBids(tenderId, nil, lotId, limit, offset)
+		}
+	}
+
+	authors, err := actingAuthors(s.repo, s.authorizer, usr, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repo.ListTenderBids(tenderId, authors, lotId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(resp) == 0 && len(authors) == 1 && authors[0] == usr.Id {
+		return nil, storage.ErrForbidden
+	}
+
+	return resp, nil
+}
+
+// ReadMyBids lists the bids placed by the user or, when organizationId is
+// set, by that organization.
+func (s *BidService) ReadMyBids(username, organizationId string, limit, offset int) ([]bids.BidResponse, error) {
+	const op = "service.BidService.ReadMyBids"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	authors, err := actingAuthors(s.repo, s.authorizer, usr, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repo.ListAuthorBids(authors, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// LeaveFeedback lets members allowed to review bids of the tender's
+// organization leave feedback on a bid, optionally rating it from 1 to 5.
+func (s *BidService) LeaveFeedback(ctx context.Context, bidId, bidFeedback, username string, rating *int) (bids.BidResponse, error) {
+	const op = "service.BidService.LeaveFeedback"
+
+	if _, err := s.repo.FetchUser(username); err != nil {
+		return bids.BidResponse{}, storage.ErrUserNotFound
+	}
+
+	bid, err := s.repo.GetBid(bidId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	ten, err := s.repo.GetTender(bid.TenderId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.LeaveFeedback)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	err = atomically(s.repo, func(repo BidRepository) error {
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.SaveComment(bids.Comment{
+			BidId:       bidId,
+			AuthorId:    usr.Id,
+			Side:        biddomain.SideTender,
+			Description: bidFeedback,
+			Rating:      rating,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, "bid feedback left", usr.Username, before)
+	})
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = notifyBidAuthor(s.repo, s.notifier, bid, notification.Event{Type: notification.FeedbackLeft, Text: bidFeedback})
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return toResponse(bid), nil
+}
+
+// GetTenderReviews lists the feedback left on bids of authorUsername within
+// the tender. Only the author and members allowed to read feedback of the
+// tender's organization may see it.
+func (s *BidService) GetTenderReviews(tenderId, authorUsername, requesterUsername string, limit, offset int) ([]bids.BidReviewResponse, error) {
+	const op = "service.BidService.GetTenderReviews"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.repo.FetchUser(authorUsername)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	if requesterUsername != authorUsername {
+		_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, requesterUsername, authz.ReadFeedback)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.repo.ReadAuthorFeedback(tenderId, author.Id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// SubmitDecision records a vote on a submitted bid by a member allowed to
+// vote for the tender's organization. A single rejection rejects the bid.
+// Once the number of approvals reaches the quorum, min(MaxQuorum, number of
+// voting members who did not recuse themselves), the bid is approved and the
+// tender awarded to it. Votes are subject to the organization's conflict of
+// interest rules.
+//
+// On tenders with lots every vote is cast for one lot of the bid. The bid is
+// approved with its first awarded lot and only rejected once it has no lot
+// left to compete for; the tender is awarded when all of its lots are.
+func (s *BidService) SubmitDecision(ctx context.Context, bidId, decision, username, lotId string) (bids.BidResponse, error) {
+	const op = "service.BidService.SubmitDecision"
+
+	bid, err := s.repo.GetBid(bidId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	ten, err := s.repo.GetTender(bid.TenderId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.Vote)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	err = checkDecidable(ten.Status)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	err = s.checkDecisionLot(ten.Id, bidId, lotId)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	status := biddomain.Normalize(bid.Status)
+	if status != biddomain.Submitted && (lotId == "" || status != biddomain.Approved) {
+		return bids.BidResponse{}, &biddomain.TransitionError{From: status, To: decision, Reason: "only submitted bids can be decided"}
+	}
+
+	voted, err := s.repo.HasVoted(bidId, lotId, usr.Id)
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if voted {
+		return bids.BidResponse{}, fmt.Errorf("%w: the user has already voted", storage.ErrForbidden)
+	}
+
+	err = checkVoteConflicts(s.repo, ten, bid, usr)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	// The checks above are repeated under the locks of the tender and the
+	// bid, which the vote, the running decision, the bid's outcome and their
+	// audit entries are written under in one transaction, so concurrent votes
+	// wait for it.
+	var (
+		resp     bids.BidResponse
+		rejected bool
+		outcome  award.Outcome
+	)
+	err = atomically(s.repo, func(repo BidRepository) error {
+		bid, err := repo.LockBid(bidId)
+		if err != nil {
+			return err
+		}
+
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+		tenderBefore, err := snapshot(repo, auditdomain.Tender, bid.TenderId)
+		if err != nil {
+			return err
+		}
+
+		resp, rejected, outcome, err = s.castVote(repo, bid, ten, usr, decision, lotId)
+		if err != nil {
+			return err
+		}
+
+		// Only votes of resolved users get this far, so username is the actor.
+		action := "bid voted " + decision
+		if lotId != "" {
+			action += " for lot " + lotId
+		}
+		err = recordChange(ctx, repo, auditdomain.Bid, bidId, action, username, before)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		tenderAfter, err := snapshot(repo, auditdomain.Tender, bid.TenderId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if bytes.Equal(tenderAfter.State, tenderBefore.State) {
+			return nil
+		}
+		err = recordChange(ctx, repo, auditdomain.Tender, bid.TenderId, "tender awarded to bid "+bidId, username, tenderBefore)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	if rejected {
+		err = notifyBidAuthor(s.repo, s.notifier, bid, notification.Event{Type: notification.BidDecided, Status: biddomain.Rejected})
+		if err != nil {
+			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return resp, nil
+	}
+
+	if outcome.BidApproved {
+		err = notifyBidAuthor(s.repo, s.notifier, bid, notification.Event{Type: notification.BidDecided, Status: biddomain.Approved})
+		if err != nil {
+			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if !outcome.TenderAwarded {
+		return resp, nil
+	}
+
+	err = notifyTenderStatus(s.repo, s.notifier, ten.Id, "")
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// castVote stores the vote of usr on the locked bid and decides the bid, or
+// awards the tender to it, once the vote settles the decision. It reports
+// whether the bid was rejected and the outcome of an award.
+func (s *BidService) castVote(repo BidRepository, bid bids.Bid, ten tender.Tender, usr user.User, decision, lotId string) (bids.BidResponse, bool, award.Outcome, error) {
+	const op = "service.BidService.castVote"
+
+	status := biddomain.Normalize(bid.Status)
+	if status != biddomain.Submitted && (lotId == "" || status != biddomain.Approved) {
+		return bids.BidResponse{}, false, award.Outcome{}, &biddomain.TransitionError{From: status, To: decision, Reason: "only submitted bids can be decided"}
+	}
+
+	voted, err := repo.HasVoted(bid.Id, lotId, usr.Id)
+	if err != nil {
+		return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if voted {
+		return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%w: the user has already voted", storage.ErrForbidden)
+	}
+
+	dec, err := repo.ReadDecision(bid.Id, lotId)
+	if err == nil && dec.Status == "Closed" {
+		return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%w: the decision is closed", storage.ErrForbidden)
+	}
+
+	dec, err = repo.SaveVote(bid.Id, lotId, usr.Id, usr.Username, decision)
+	if err != nil {
+		return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp := toResponse(bid)
+
+	if decision == biddomain.Rejected {
+		err = repo.CloseDecision(bid.Id, lotId)
+		if err != nil {
+			return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if lotId != "" {
+			open, err := repo.CountOpenBidLots(bid.Id)
+			if err != nil {
+				return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+			}
+			if open > 0 || status == biddomain.Approved {
+				return resp, false, award.Outcome{}, nil
+			}
+		}
+
+		err = repo.DecideBid(&resp, biddomain.Rejected)
+		if err != nil {
+			return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return resp, true, award.Outcome{}, nil
+	}
+
+	members, err := repo.ListOrganizationMembers(ten.OrganizationId)
+	if err != nil {
+		return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recusals, err := repo.ListRecusals(ten.Id)
+	if err != nil {
+		return bids.BidResponse{}, false, award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	voters := 0
+	for _, member := range members {
+		recused := slices.ContainsFunc(recusals, func(r conflict.Recusal) bool { return r.UserId == member.UserId })
+		if authz.AllowsAny(member.Roles, authz.Vote) && !recused {
+			voters++
+		}
+	}
+
+	if dec.NumApproved < quorum(voters) {
+		return resp, false, award.Outcome{}, nil
+	}
+
+	// The tender may have been cancelled or awarded since it was read, so
+	// the status is checked again under the lock the award holds.
+	outcome, err := repo.AwardBid(&resp, lotId, usr.Username, func(status string) error {
+		err := checkDecidable(status)
+		if err != nil {
+			return err
+		}
+		_, err = repo.GetAward(ten.Id, lotId)
+		if err == nil {
+			return fmt.Errorf("%w: the tender is already awarded", storage.ErrConflict)
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return bids.BidResponse{}, false, award.Outcome{}, err
+	}
+
+	return resp, false, outcome, nil
+}
+
+// checkDecisionLot requires a lot the bid targets on tenders with lots and
+// no lot on tenders without.
+func (s *BidService) checkDecisionLot(tenderId, bidId, lotId string) error {
+	const op = "service.BidService.checkDecisionLot"
+
+	lots, err := s.repo.ListLots(tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(lots) == 0 {
+		if lotId != "" {
+			return fmt.Errorf("%w: the tender has no lots", storage.ErrBadRequest)
+		}
+		return nil
+	}
+
+	if lotId == "" {
+		return fmt.Errorf("%w: the tender has lots, the decision must name one", storage.ErrBadRequest)
+	}
+
+	bidLots, err := s.repo.ReadBidLots(bidId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, id := range bidLots {
+		if id == lotId {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: the bid does not target lot %s", storage.ErrBadRequest, lotId)
+}
+
+// checkDecidable refuses votes on tenders that can no longer be awarded.
+func checkDecidable(status string) error {
+	if !tenderdomain.AcceptsDecisions(status) {
+		return fmt.Errorf("%w: the tender is %s, its bids are no longer decided", storage.ErrConflict, strings.ToLower(status))
+	}
+	return nil
+}
+
+func quorum(voters int) int {
+	return min(MaxQuorum, voters)
+}
+
+func toResponse(bid bids.Bid) bids.BidResponse {
+	return bids.BidResponse{
+		Id:          bid.Id,
+		Name:        bid.Name,
+		Description: bid.Description,
+		TenderId:    bid.TenderId,
+		Status:      bid.Status,
+		AuthorType:  bid.AuthorType,
+		AuthorId:    bid.AuthorId,
+		Price:       bid.Price,
+		Version:     bid.Version,
+		CreatedAt:   bid.CreatedAt,
+	}
+}
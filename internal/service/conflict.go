@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	conflictdomain "tender_system/internal/domain/conflict"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+// ConflictRepository is the data access of the ConflictService.
+type ConflictRepository interface {
+	memberRoleReader
+	userFetcher
+	auditLog
+
+	GetTender(tenderId string) (tender.Tender, error)
+	ReadConflictRules(organizationId string) ([]conflict.Rule, error)
+	SaveConflictRules(organizationId string, rules []conflict.Rule) error
+	SaveRecusal(r conflict.Recusal) (conflict.Recusal, error)
+	ListRecusals(tenderId string) ([]conflict.Recusal, error)
+	HasVotedOnTender(tenderId, userId string) (bool, error)
+}
+
+type ConflictService struct {
+	repo       ConflictRepository
+	authorizer *authz.Authorizer
+}
+
+func NewConflictService(repo ConflictRepository, authorizer *authz.Authorizer) *ConflictService {
+	return &ConflictService{repo: repo, authorizer: authorizer}
+}
+
+// ReadRules returns every conflict rule with the action the organization
+// takes on it. Any member of the organization may see them.
+func (s *ConflictService) ReadRules(organizationId, username string) ([]conflict.Rule, error) {
+	const op = "service.ConflictService.ReadRules"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	err = requireMember(s.repo, organizationId, usr.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.ReadConflictRules(organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rules := conflictdomain.Rules()
+	for i := range rules {
+		for _, r := range stored {
+			if r.Rule == rules[i].Rule {
+				rules[i].Action = r.Action
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// UpdateRules changes the actions of the given rules, leaving the others as
+// they are.
+func (s *ConflictService) UpdateRules(ctx context.Context, organizationId, username string, req conflict.RulesRequest) ([]conflict.Rule, error) {
+	const op = "service.ConflictService.UpdateRules"
+
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ManageOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range req.Rules {
+		if !conflictdomain.ValidRule(r.Rule) {
+			return nil, fmt.Errorf("%w: unknown conflict rule %s", storage.ErrBadRequest, r.Rule)
+		}
+	}
+
+	err = atomically(s.repo, func(repo ConflictRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.SaveConflictRules(organizationId, req.Rules)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "conflict rules changed", usr.Username, before)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.ReadRules(organizationId, username)
+}
+
+// Recuse declares that the user will not decide on the bids of the tender.
+// Recused voters no longer count towards the quorum, so only voters who have
+// not voted on the tender yet may recuse themselves.
+func (s *ConflictService) Recuse(ctx context.Context, tenderId, username string, req conflict.RecusalRequest) (conflict.Recusal, error) {
+	const op = "service.ConflictService.Recuse"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return conflict.Recusal{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.Vote)
+	if err != nil {
+		return conflict.Recusal{}, err
+	}
+
+	voted, err := s.repo.HasVotedOnTender(tenderId, usr.Id)
+	if err != nil {
+		return conflict.Recusal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if voted {
+		return conflict.Recusal{}, fmt.Errorf("%w: the user has already voted on the tender", storage.ErrConflict)
+	}
+
+	var resp conflict.Recusal
+	err = atomically(s.repo, func(repo ConflictRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.SaveRecusal(conflict.Recusal{
+			TenderId: tenderId,
+			UserId:   usr.Id,
+			Username: usr.Username,
+			Reason:   req.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, usr.Username+" recused", usr.Username, before)
+	})
+	if err != nil {
+		return conflict.Recusal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// ListRecusals shows the recusals on a tender to members allowed to view its
+// bids.
+func (s *ConflictService) ListRecusals(tenderId, username string) ([]conflict.Recusal, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewBids)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListRecusals(tenderId)
+}
+
+// voteConflictStore reads and records what the conflict of interest checks
+// of a vote depend on.
+type voteConflictStore interface {
+	conflictRuleReader
+	ReadUserOrganizations(userId string) ([]string, error)
+	ListRecusals(tenderId string) ([]conflict.Recusal, error)
+	RecordConflicts(entityType, entityId, organizationId, actor string, findings []conflict.Finding) error
+}
+
+// checkVoteConflicts refuses votes by recused voters and evaluates the vote
+// conflict rules of the tender's organization. Every finding is recorded in
+// the audit trail, and blocking ones fail the vote.
+func checkVoteConflicts(repo voteConflictStore, ten tender.Tender, bid bids.Bid, usr user.User) error {
+	const op = "service.checkVoteConflicts"
+
+	recusals, err := repo.ListRecusals(ten.Id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, r := range recusals {
+		if r.UserId == usr.Id {
+			return fmt.Errorf("%w: the user recused themselves from the tender", storage.ErrForbidden)
+		}
+	}
+
+	var hits []conflict.Finding
+	if bid.AuthorType == "Organization" {
+		roles, err := repo.ReadMemberRoles(bid.AuthorId, usr.Id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if len(roles) > 0 {
+			hits = append(hits, conflict.Finding{Rule: conflictdomain.OwnBidVote, Detail: "the voter is a member of the bidding organization"})
+		}
+	} else if bid.AuthorId == usr.Id {
+		hits = append(hits, conflict.Finding{Rule: conflictdomain.OwnBidVote, Detail: "the voter authored the bid"})
+	} else {
+		voterOrgs, err := repo.ReadUserOrganizations(usr.Id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		authorOrgs, err := repo.ReadUserOrganizations(bid.AuthorId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		for _, orgId := range voterOrgs {
+			if orgId != ten.OrganizationId && slices.Contains(authorOrgs, orgId) {
+				hits = append(hits, conflict.Finding{Rule: conflictdomain.ColleagueVote, Detail: "the voter and the bid author share organization " + orgId})
+				break
+			}
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+
+	rules, err := repo.ReadConflictRules(ten.OrganizationId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	findings, blocked := conflictdomain.Evaluate(rules, hits)
+	err = repo.RecordConflicts(auditdomain.Bid, bid.Id, ten.OrganizationId, usr.Username, findings)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if blocked {
+		return fmt.Errorf("%w: %s", storage.ErrForbidden, conflictdomain.Describe(findings))
+	}
+
+	return nil
+}
+
+// conflictRuleReader reads what the conflict of interest checks of a bid
+// depend on.
+type conflictRuleReader interface {
+	ReadMemberRoles(organizationId, userId string) ([]string, error)
+	ReadConflictRules(organizationId string) ([]conflict.Rule, error)
+}
+
+// bidConflicts evaluates the member_bid rule of the tender's organization for
+// a new bid by the author and reports whether a finding blocks it. Nothing is
+// recorded; that is up to the caller.
+func bidConflicts(repo conflictRuleReader, ten tender.Tender, authorType, authorId string) ([]conflict.Finding, bool, error) {
+	var hits []conflict.Finding
+
+	if authorType == "Organization" {
+		if authorId == ten.OrganizationId {
+			hits = append(hits, conflict.Finding{Rule: conflictdomain.MemberBid, Detail: "the organization bids on its own tender"})
+		}
+	} else {
+		roles, err := repo.ReadMemberRoles(ten.OrganizationId, authorId)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(roles) > 0 {
+			hits = append(hits, conflict.Finding{Rule: conflictdomain.MemberBid, Detail: "the author is a member of the tender's organization"})
+		}
+	}
+	if len(hits) == 0 {
+		return nil, false, nil
+	}
+
+	rules, err := repo.ReadConflictRules(ten.OrganizationId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	findings, blocked := conflictdomain.Evaluate(rules, hits)
+	return findings, blocked, nil
+}
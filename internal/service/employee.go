@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+// EmployeeRepository is the data access of the EmployeeService.
+type EmployeeRepository interface {
+	auditLog
+
+	FetchUser(username string) (user.User, error)
+	ReadUserMemberships(userId string) ([]user.Membership, error)
+	ListEmployees(limit, offset int) ([]user.User, error)
+	SaveEmployee(req user.EmployeeRequest) (user.User, error)
+	UpdateEmployee(usr user.User) (user.User, error)
+	DeleteEmployee(userId string, guard func(organizationId string, responsibles, openTenders int) error) error
+}
+
+type EmployeeService struct {
+	repo EmployeeRepository
+}
+
+func NewEmployeeService(repo EmployeeRepository) *EmployeeService {
+	return &EmployeeService{repo: repo}
+}
+
+func (s *EmployeeService) ListEmployees(limit, offset int) ([]user.User, error) {
+	return s.repo.ListEmployees(limit, offset)
+}
+
+func (s *EmployeeService) GetEmployee(username string) (user.User, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return user.User{}, storage.ErrNotFound
+	}
+
+	return usr, nil
+}
+
+// ReadProfile returns the user with every organization they belong to and
+// their roles there.
+func (s *EmployeeService) ReadProfile(username string) (user.Profile, error) {
+	const op = "service.EmployeeService.ReadProfile"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return user.Profile{}, storage.ErrUserNotFound
+	}
+
+	memberships, err := s.repo.ReadUserMemberships(usr.Id)
+	if err != nil {
+		return user.Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user.Profile{User: usr, Organizations: memberships}, nil
+}
+
+// CreateEmployee registers a new account. Accounts register themselves, so
+// the new employee is the actor.
+func (s *EmployeeService) CreateEmployee(ctx context.Context, req user.EmployeeRequest) (user.User, error) {
+	var usr user.User
+	err := atomically(s.repo, func(repo EmployeeRepository) error {
+		var err error
+		usr, err = repo.SaveEmployee(req)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Employee, usr.Id, "employee created", usr.Username, audit.Subject{})
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return usr, nil
+}
+
+// UpdateEmployee changes the profile of targetUsername. Employees may only
+// edit themselves.
+func (s *EmployeeService) UpdateEmployee(ctx context.Context, targetUsername, username string, req user.EmployeePatchRequest) (user.User, error) {
+	usr, err := s.self(targetUsername, username)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if req.FirstName != "" {
+		usr.FirstName = req.FirstName
+	}
+	if req.LastName != "" {
+		usr.LastName = req.LastName
+	}
+
+	var updated user.User
+	err = atomically(s.repo, func(repo EmployeeRepository) error {
+		before, err := snapshot(repo, auditdomain.Employee, usr.Id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = repo.UpdateEmployee(usr)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Employee, usr.Id, "employee edited", usr.Username, before)
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteEmployee removes the account of targetUsername. It is refused while
+// the employee is the last responsible of an organization with open tenders.
+func (s *EmployeeService) DeleteEmployee(ctx context.Context, targetUsername, username string) error {
+	usr, err := s.self(targetUsername, username)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo EmployeeRepository) error {
+		before, err := snapshot(repo, auditdomain.Employee, usr.Id)
+		if err != nil {
+			return err
+		}
+
+		err = repo.DeleteEmployee(usr.Id, guardLastResponsible)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Employee, usr.Id, "employee deleted", usr.Username, before)
+	})
+}
+
+func (s *EmployeeService) self(targetUsername, username string) (user.User, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return user.User{}, storage.ErrUserNotFound
+	}
+
+	if targetUsername != username {
+		if _, err := s.repo.FetchUser(targetUsername); err != nil {
+			return user.User{}, storage.ErrNotFound
+		}
+		return user.User{}, storage.ErrForbidden
+	}
+
+	return usr, nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// ReadFeedback returns the feedback thread of a bid. Threads are private to
+// the bid author and the members allowed to read feedback of the tender's
+// organization, so competing bidders never see each other's.
+func (s *BidService) ReadFeedback(bidId, username string) ([]bids.Comment, error) {
+	const op = "service.BidService.ReadFeedback"
+
+	bid, ten, usr, err := s.feedbackContext(bidId, username)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewer, err := s.authorizer.Can(ten.OrganizationId, usr.Id, authz.ReadFeedback)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !reviewer {
+		err = s.requireBidAuthor(bid, usr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.repo.ListComments(bidId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// PostComment adds a comment to the feedback thread of a bid. Members allowed
+// to leave feedback for the tender's organization may start new threads,
+// optionally rating the bid, and reply; the bid author may only reply.
+func (s *BidService) PostComment(ctx context.Context, bidId, username string, req bids.CommentRequest) (bids.Comment, error) {
+	const op = "service.BidService.PostComment"
+
+	bid, ten, usr, err := s.feedbackContext(bidId, username)
+	if err != nil {
+		return bids.Comment{}, err
+	}
+
+	side := biddomain.SideTender
+	reviewer, err := s.authorizer.Can(ten.OrganizationId, usr.Id, authz.LeaveFeedback)
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !reviewer {
+		err = s.requireBidAuthor(bid, usr)
+		if err != nil {
+			return bids.Comment{}, err
+		}
+		if req.ParentId == "" {
+			return bids.Comment{}, fmt.Errorf("%w: bid authors can only reply to feedback", storage.ErrForbidden)
+		}
+		side = biddomain.SideBid
+	}
+
+	if req.Rating != nil && (side != biddomain.SideTender || req.ParentId != "") {
+		return bids.Comment{}, fmt.Errorf("%w: only reviewers can rate a bid, in a new thread", storage.ErrBadRequest)
+	}
+
+	var parent bids.Comment
+	if req.ParentId != "" {
+		parent, err = s.repo.GetComment(bidId, req.ParentId)
+		if err != nil {
+			return bids.Comment{}, err
+		}
+		if parent.Deleted {
+			return bids.Comment{}, fmt.Errorf("%w: cannot reply to a deleted comment", storage.ErrConflict)
+		}
+	}
+
+	var resp bids.Comment
+	err = atomically(s.repo, func(repo BidRepository) error {
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.SaveComment(bids.Comment{
+			BidId:       bidId,
+			ParentId:    req.ParentId,
+			AuthorId:    usr.Id,
+			Side:        side,
+			Description: req.Description,
+			Rating:      req.Rating,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, "comment "+resp.Id+" posted", usr.Username, before)
+	})
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if side == biddomain.SideTender {
+		err = notifyBidAuthor(s.repo, s.notifier, bid, notification.Event{Type: notification.FeedbackLeft, Text: req.Description})
+	} else if parent.AuthorId != "" {
+		s.notifier.Notify([]string{parent.AuthorId}, notification.Event{
+			Type:       notification.FeedbackReplied,
+			TenderId:   ten.Id,
+			TenderName: ten.Name,
+			BidId:      bid.Id,
+			BidName:    bid.Name,
+			Text:       req.Description,
+		})
+	}
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// EditComment changes the text of the user's own comment within
+// biddomain.FeedbackEditWindow of writing it.
+func (s *BidService) EditComment(ctx context.Context, bidId, commentId, username string, req bids.CommentPatchRequest) (bids.Comment, error) {
+	c, usr, err := s.ownComment(bidId, commentId, username)
+	if err != nil {
+		return bids.Comment{}, err
+	}
+
+	if req.Rating != nil && (c.Side != biddomain.SideTender || c.ParentId != "") {
+		return bids.Comment{}, fmt.Errorf("%w: only reviewers can rate a bid, in a new thread", storage.ErrBadRequest)
+	}
+
+	var resp bids.Comment
+	err = atomically(s.repo, func(repo BidRepository) error {
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.UpdateComment(c.Id, req.Description, req.Rating)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, "comment "+c.Id+" edited", usr.Username, before)
+	})
+	if err != nil {
+		return bids.Comment{}, err
+	}
+
+	return resp, nil
+}
+
+// DeleteComment removes the user's own comment within
+// biddomain.FeedbackEditWindow of writing it. Replies to it stay in the
+// thread.
+func (s *BidService) DeleteComment(ctx context.Context, bidId, commentId, username string) error {
+	c, usr, err := s.ownComment(bidId, commentId, username)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo BidRepository) error {
+		before, err := snapshot(repo, auditdomain.Bid, bidId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.DeleteComment(c.Id)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Bid, bidId, "comment "+c.Id+" deleted", usr.Username, before)
+	})
+}
+
+func (s *BidService) feedbackContext(bidId, username string) (bids.Bid, tender.Tender, user.User, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return bids.Bid{}, tender.Tender{}, user.User{}, storage.ErrUserNotFound
+	}
+
+	bid, err := s.repo.GetBid(bidId)
+	if err != nil {
+		return bids.Bid{}, tender.Tender{}, user.User{}, err
+	}
+
+	ten, err := s.repo.GetTender(bid.TenderId)
+	if err != nil {
+		return bids.Bid{}, tender.Tender{}, user.User{}, err
+	}
+
+	return bid, ten, usr, nil
+}
+
+// requireBidAuthor returns storage.ErrForbidden unless the user may act as the
+// author of the bid.
+func (s *BidService) requireBidAuthor(bid bids.Bid, usr user.User) error {
+	authors, err := actingAuthors(s.repo, s.authorizer, usr, "")
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(authors, bid.AuthorId) {
+		return fmt.Errorf("%w: not a participant of the feedback thread", storage.ErrForbidden)
+	}
+	return nil
+}
+
+// ownComment resolves a comment the user may change, together with the user.
+func (s *BidService) ownComment(bidId, commentId, username string) (bids.Comment, user.User, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return bids.Comment{}, user.User{}, storage.ErrUserNotFound
+	}
+
+	c, err := s.repo.GetComment(bidId, commentId)
+	if err != nil {
+		return bids.Comment{}, user.User{}, err
+	}
+	if c.Deleted {
+		return bids.Comment{}, user.User{}, storage.ErrNotFound
+	}
+
+	if c.AuthorId != usr.Id {
+		return bids.Comment{}, user.User{}, fmt.Errorf("%w: only the author may change a comment", storage.ErrForbidden)
+	}
+	if !biddomain.CanChangeComment(c.CreatedAt, time.Now().UTC()) {
+		return bids.Comment{}, user.User{}, fmt.Errorf("%w: comments can only be changed within %s of writing them", storage.ErrConflict, biddomain.FeedbackEditWindow)
+	}
+
+	return c, usr, nil
+}
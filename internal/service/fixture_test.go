@@ -0,0 +1,356 @@
+package service_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/service"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// The fixture is seeded with a buying organization owned by alice, a
+// supplier organization bob bids for, and carol, who belongs to neither.
+const (
+	buyerOrg    = "org-buyer"
+	supplierOrg = "org-supplier"
+
+	alice = "alice"
+	bob   = "bob"
+	carol = "carol"
+	// nobody is not an employee at all.
+	nobody = "nobody"
+
+	publishedTender = "tender-published"
+	draftTender     = "tender-draft"
+	closedTender    = "tender-closed"
+	lotTender       = "tender-lots"
+	userBid         = "bid-user"
+	missing         = "does-not-exist"
+)
+
+// fixture is an in-memory repository holding plain data, the way the
+// Postgres storage does: every rule the services are tested for lives in the
+// services. Anything not used by the tests falls through to the nil
+// repository and panics.
+type fixture struct {
+	service.Repository
+
+	users         map[string]user.User
+	organizations map[string]map[string][]string
+	responsibles  map[string][]string
+	tenders       map[string]tender.Tender
+	lots          map[string][]lot.Lot
+	bids          map[string]bids.Bid
+	history       map[string][]bids.Bid
+	auctions      map[string]auction.Auction
+	conflicts     []conflict.Finding
+	audit         []audit.Entry
+	authz         *authz.Authorizer
+	seq           int
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		users:         make(map[string]user.User),
+		organizations: make(map[string]map[string][]string),
+		responsibles:  make(map[string][]string),
+		tenders:       make(map[string]tender.Tender),
+		lots:          make(map[string][]lot.Lot),
+		bids:          make(map[string]bids.Bid),
+		history:       make(map[string][]bids.Bid),
+		auctions:      make(map[string]auction.Auction),
+	}
+	f.authz = authz.New(f)
+
+	for _, username := range []string{alice, bob, carol} {
+		f.users[username] = user.User{Id: "user-" + username, Username: username}
+	}
+	f.organizations[buyerOrg] = map[string][]string{"user-" + alice: {string(authz.Owner)}}
+	f.organizations[supplierOrg] = map[string][]string{"user-" + bob: {string(authz.Bidder)}}
+
+	max := 1000.0
+	f.tenders[publishedTender] = tender.Tender{
+		Id:             publishedTender,
+		Status:         tenderdomain.Published,
+		OrganizationId: buyerOrg,
+		Version:        3,
+		Budget:         tender.Budget{Max: &max, Currency: "RUB", Visibility: tenderdomain.BudgetPublic, Policy: tenderdomain.BudgetReject},
+	}
+	f.tenders[draftTender] = tender.Tender{
+		Id:             draftTender,
+		Status:         tenderdomain.Created,
+		OrganizationId: buyerOrg,
+		Version:        1,
+	}
+	f.tenders[closedTender] = tender.Tender{
+		Id:             closedTender,
+		Status:         tenderdomain.Closed,
+		OrganizationId: buyerOrg,
+		Version:        2,
+	}
+	f.tenders[lotTender] = tender.Tender{
+		Id:             lotTender,
+		Status:         tenderdomain.Published,
+		OrganizationId: buyerOrg,
+		Version:        1,
+	}
+	f.lots[lotTender] = []lot.Lot{{Id: "lot-1", TenderId: lotTender}}
+
+	price := 900.0
+	f.bids[userBid] = bids.Bid{
+		Id:         userBid,
+		Status:     biddomain.Submitted,
+		TenderId:   publishedTender,
+		AuthorType: "User",
+		AuthorId:   "user-" + bob,
+		Price:      &price,
+		Version:    2,
+	}
+	oldPrice := 950.0
+	f.history[userBid] = []bids.Bid{{
+		Id:         userBid,
+		Name:       "First draft",
+		Status:     biddomain.Draft,
+		TenderId:   publishedTender,
+		AuthorType: "User",
+		AuthorId:   "user-" + bob,
+		Price:      &oldPrice,
+		Version:    1,
+	}}
+
+	return f
+}
+
+func (f *fixture) FetchUser(username string) (user.User, error) {
+	usr, ok := f.users[username]
+	if !ok {
+		return user.User{}, storage.ErrUserNotFound
+	}
+	return usr, nil
+}
+
+func (f *fixture) EmployeeExists(userId string) (bool, error) {
+	for _, usr := range f.users {
+		if usr.Id == userId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fixture) OrganizationExists(organizationId string) (bool, error) {
+	_, ok := f.organizations[organizationId]
+	return ok, nil
+}
+
+func (f *fixture) ReadMemberRoles(organizationId, userId string) ([]string, error) {
+	return f.organizations[organizationId][userId], nil
+}
+
+func (f *fixture) ReadUserOrganizations(userId string) ([]string, error) {
+	result := make([]string, 0)
+	for organizationId, members := range f.organizations {
+		if len(members[userId]) > 0 {
+			result = append(result, organizationId)
+		}
+	}
+	return result, nil
+}
+
+func (f *fixture) ReadResponsibleOrganizations(userId string) ([]string, error) {
+	return f.responsibles[userId], nil
+}
+
+func (f *fixture) ReadConflictRules(organizationId string) ([]conflict.Rule, error) {
+	return nil, nil
+}
+
+func (f *fixture) RecordConflicts(entityType, entityId, organizationId, actor string, findings []conflict.Finding) error {
+	f.conflicts = append(f.conflicts, findings...)
+	return nil
+}
+
+func (f *fixture) AuditSubject(entityType, entityId string) (audit.Subject, error) {
+	var (
+		orgId string
+		state any
+	)
+	switch entityType {
+	case auditdomain.Tender:
+		ten, ok := f.tenders[entityId]
+		if !ok {
+			return audit.Subject{}, storage.ErrNotFound
+		}
+		orgId, state = ten.OrganizationId, ten
+	case auditdomain.Bid:
+		bid, ok := f.bids[entityId]
+		if !ok {
+			return audit.Subject{}, storage.ErrNotFound
+		}
+		orgId, state = f.tenders[bid.TenderId].OrganizationId, bid
+	default:
+		return audit.Subject{}, storage.ErrBadRequest
+	}
+
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return audit.Subject{}, err
+	}
+	return audit.Subject{EntityId: entityId, OrganizationId: orgId, State: raw}, nil
+}
+
+func (f *fixture) AppendAudit(e audit.Entry) (audit.Entry, error) {
+	e.Id = int64(len(f.audit) + 1)
+	f.audit = append(f.audit, e)
+	return e, nil
+}
+
+func (f *fixture) GetTender(tenderId string) (tender.Tender, error) {
+	ten, ok := f.tenders[tenderId]
+	if !ok {
+		return tender.Tender{}, storage.ErrNotFound
+	}
+	return ten, nil
+}
+
+func (f *fixture) SaveTender(req tender.TenderRequest) (tender.TenderResponse, error) {
+	f.seq++
+	ten := tender.Tender{
+		Id:             fmt.Sprintf("tender-%d", f.seq),
+		Name:           req.Name,
+		Status:         tenderdomain.Created,
+		Type:           req.Type,
+		OrganizationId: req.OrganizationId,
+		Version:        1,
+	}
+	f.tenders[ten.Id] = ten
+	return tender.TenderResponse{Id: ten.Id, Name: ten.Name, Status: ten.Status, Type: ten.Type, Version: ten.Version}, nil
+}
+
+func (f *fixture) RollbackTender(tenderId string, version int) (tender.TenderResponse, error) {
+	ten, ok := f.tenders[tenderId]
+	if !ok {
+		return tender.TenderResponse{}, storage.ErrNotFound
+	}
+	ten.Version++
+	f.tenders[tenderId] = ten
+	return tender.TenderResponse{Id: ten.Id, Status: ten.Status, Version: ten.Version}, nil
+}
+
+func (f *fixture) ListLots(tenderId string) ([]lot.Lot, error) {
+	return f.lots[tenderId], nil
+}
+
+func (f *fixture) GetAuction(tenderId string) (auction.Auction, error) {
+	a, ok := f.auctions[tenderId]
+	if !ok {
+		return auction.Auction{}, storage.ErrNotFound
+	}
+	return a, nil
+}
+
+func (f *fixture) GetBid(bidId string) (bids.Bid, error) {
+	bid, ok := f.bids[bidId]
+	if !ok {
+		return bids.Bid{}, storage.ErrNotFound
+	}
+	return bid, nil
+}
+
+func (f *fixture) LockBid(bidId string) (bids.Bid, error) {
+	return f.GetBid(bidId)
+}
+
+func (f *fixture) SaveBid(req bids.BidRequest) (bids.BidResponse, error) {
+	f.seq++
+	bid := bids.Bid{
+		Id:         fmt.Sprintf("bid-%d", f.seq),
+		Name:       req.Name,
+		Status:     biddomain.Draft,
+		TenderId:   req.TenderId,
+		AuthorType: req.AuthorType,
+		AuthorId:   req.AuthorId,
+		Price:      req.Price,
+		Version:    1,
+	}
+	f.bids[bid.Id] = bid
+	return response(bid), nil
+}
+
+func (f *fixture) ChangeBidStatus(bidId, status string, guard func(from string) error) (bids.BidResponse, error) {
+	bid, ok := f.bids[bidId]
+	if !ok {
+		return bids.BidResponse{}, storage.ErrNotFound
+	}
+	err := guard(bid.Status)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	f.history[bidId] = append(f.history[bidId], bid)
+	bid.Status = status
+	bid.Version++
+	f.bids[bidId] = bid
+	return response(bid), nil
+}
+
+func (f *fixture) EditBid(bidId, name, desc string, price *float64, outOfBudget bool) (bids.BidResponse, error) {
+	bid, ok := f.bids[bidId]
+	if !ok {
+		return bids.BidResponse{}, storage.ErrNotFound
+	}
+
+	f.history[bidId] = append(f.history[bidId], bid)
+	if price != nil {
+		bid.Price = price
+	}
+	bid.Version++
+	f.bids[bidId] = bid
+	return response(bid), nil
+}
+
+func (f *fixture) RollbackBid(bidId string, version int, guard func(current, restored *float64) error) (bids.BidResponse, error) {
+	bid, ok := f.bids[bidId]
+	if !ok || version < 1 || version > len(f.history[bidId]) {
+		return bids.BidResponse{}, storage.ErrNotFound
+	}
+
+	old := f.history[bidId][version-1]
+	err := guard(bid.Price, old.Price)
+	if err != nil {
+		return bids.BidResponse{}, err
+	}
+
+	f.history[bidId] = append(f.history[bidId], bid)
+	bid.Name, bid.Description, bid.Price = old.Name, old.Description, old.Price
+	bid.Version++
+	f.bids[bidId] = bid
+	return response(bid), nil
+}
+
+func response(bid bids.Bid) bids.BidResponse {
+	return bids.BidResponse{
+		Id:         bid.Id,
+		Name:       bid.Name,
+		TenderId:   bid.TenderId,
+		Status:     bid.Status,
+		AuthorType: bid.AuthorType,
+		AuthorId:   bid.AuthorId,
+		Version:    bid.Version,
+		Price:      bid.Price,
+	}
+}
+
+// expired is a deadline that has passed.
+var expired = time.Now().Add(-time.Hour)
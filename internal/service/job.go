@@ -0,0 +1,53 @@
+package service
+
+import (
+	"fmt"
+	"tender_system/internal/models/job"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+type FailedJobLister interface {
+	ListFailed(kind string, limit, offset int) ([]job.Job, error)
+}
+
+// JobRepository is the data access of the JobService.
+type JobRepository interface {
+	FetchUser(username string) (user.User, error)
+}
+
+// JobService exposes the background job queue to administrators. The queue
+// is shared by all organizations, so administrators are configured for the
+// whole installation rather than granted a role.
+type JobService struct {
+	repo   JobRepository
+	queue  FailedJobLister
+	admins map[string]bool
+}
+
+func NewJobService(repo JobRepository, queue FailedJobLister, admins []string) *JobService {
+	set := make(map[string]bool, len(admins))
+	for _, username := range admins {
+		set[username] = true
+	}
+	return &JobService{repo: repo, queue: queue, admins: set}
+}
+
+func (s *JobService) ListFailedJobs(username, kind string, limit, offset int) ([]job.Job, error) {
+	const op = "service.JobService.ListFailedJobs"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+	if !s.admins[usr.Username] {
+		return nil, fmt.Errorf("%w: only administrators may inspect background jobs", storage.ErrForbidden)
+	}
+
+	resp, err := s.queue.ListFailed(kind, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+// LotRepository is the data access of the LotService.
+type LotRepository interface {
+	userFetcher
+	auditLog
+
+	GetTender(tenderId string) (tender.Tender, error)
+	ListLots(tenderId string) ([]lot.Lot, error)
+	GetLot(tenderId, lotId string) (lot.Lot, error)
+	AddLot(tenderId string, req lot.LotRequest) (lot.Lot, error)
+	UpdateLot(l lot.Lot) (lot.Lot, error)
+	RemoveLot(tenderId, lotId string) error
+}
+
+type LotService struct {
+	repo       LotRepository
+	authorizer *authz.Authorizer
+}
+
+func NewLotService(repo LotRepository, authorizer *authz.Authorizer) *LotService {
+	return &LotService{repo: repo, authorizer: authorizer}
+}
+
+// ListLots returns the lots of a published tender to anyone and of any other
+// tender to members allowed to view the organization's tenders.
+func (s *LotService) ListLots(tenderId, username string) ([]lot.Lot, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if ten.Status != tenderdomain.Published {
+		_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewTender)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.ListLots(tenderId)
+}
+
+func (s *LotService) AddLot(ctx context.Context, tenderId, username string, req lot.LotRequest) (lot.Lot, error) {
+	usr, err := s.editableTender(tenderId, username)
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	var l lot.Lot
+	err = atomically(s.repo, func(repo LotRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		l, err = repo.AddLot(tenderId, req)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "lot "+l.Id+" added", usr.Username, before)
+	})
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	return l, nil
+}
+
+func (s *LotService) UpdateLot(ctx context.Context, tenderId, lotId, username string, req lot.LotPatchRequest) (lot.Lot, error) {
+	usr, err := s.editableTender(tenderId, username)
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	l, err := s.repo.GetLot(tenderId, lotId)
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	if req.Name != "" {
+		l.Name = req.Name
+	}
+	if req.Description != "" {
+		l.Description = req.Description
+	}
+	if req.ServiceType != "" {
+		l.ServiceType = req.ServiceType
+	}
+	if req.Budget != nil {
+		l.Budget = req.Budget
+	}
+
+	err = atomically(s.repo, func(repo LotRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		l, err = repo.UpdateLot(l)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "lot "+lotId+" edited", usr.Username, before)
+	})
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	return l, nil
+}
+
+func (s *LotService) RemoveLot(ctx context.Context, tenderId, lotId, username string) error {
+	usr, err := s.editableTender(tenderId, username)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.GetLot(tenderId, lotId)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo LotRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.RemoveLot(tenderId, lotId)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "lot "+lotId+" removed", usr.Username, before)
+	})
+}
+
+// editableTender checks that the user may edit the tender and that its lots
+// can still change, which is only the case before it is published. It
+// returns the resolved user.
+func (s *LotService) editableTender(tenderId, username string) (user.User, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.EditTender)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if ten.Status != tenderdomain.Created {
+		return user.User{}, fmt.Errorf("%w: lots can only change while the tender is %s", storage.ErrConflict, tenderdomain.Created)
+	}
+
+	return usr, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"tender_system/internal/authz"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/notification"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+// Notifier delivers events to users. Delivery problems never fail the action
+// that raised the event.
+type Notifier interface {
+	Notify(userIds []string, ev notification.Event)
+}
+
+// NotificationRepository is the data access of the NotificationService.
+type NotificationRepository interface {
+	FetchUser(username string) (user.User, error)
+	ReadNotificationSettings(userId string) (notification.Settings, error)
+	SaveNotificationSettings(userId string, settings notification.Settings) (notification.Settings, error)
+	ListNotifications(userId string, unreadOnly bool, limit, offset int) ([]notification.Notification, error)
+	MarkNotificationRead(userId, notificationId string) error
+	MarkAllNotificationsRead(userId string) error
+}
+
+type NotificationService struct {
+	repo NotificationRepository
+}
+
+func NewNotificationService(repo NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+func (s *NotificationService) ListNotifications(username string, unreadOnly bool, limit, offset int) ([]notification.Notification, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return s.repo.ListNotifications(usr.Id, unreadOnly, limit, offset)
+}
+
+func (s *NotificationService) MarkRead(username, notificationId string) error {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return storage.ErrUserNotFound
+	}
+
+	return s.repo.MarkNotificationRead(usr.Id, notificationId)
+}
+
+func (s *NotificationService) MarkAllRead(username string) error {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return storage.ErrUserNotFound
+	}
+
+	return s.repo.MarkAllNotificationsRead(usr.Id)
+}
+
+func (s *NotificationService) ReadSettings(username string) (notification.Settings, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return notification.Settings{}, storage.ErrUserNotFound
+	}
+
+	return s.repo.ReadNotificationSettings(usr.Id)
+}
+
+func (s *NotificationService) UpdateSettings(username string, settings notification.Settings) (notification.Settings, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return notification.Settings{}, storage.ErrUserNotFound
+	}
+
+	return s.repo.SaveNotificationSettings(usr.Id, settings)
+}
+
+// recipientReader resolves who is notified about a tender or a bid.
+type recipientReader interface {
+	GetTender(tenderId string) (tender.Tender, error)
+	ListOrganizationMembers(organizationId string) ([]user.Member, error)
+	ListTenderBidAuthors(tenderId string) ([]bids.Author, error)
+}
+
+// authorRecipients lists the users speaking for a bid author: the user
+// itself, or the members allowed to submit bids for the organization.
+func authorRecipients(repo recipientReader, author bids.Author) ([]string, error) {
+	if author.Type != "Organization" {
+		return []string{author.Id}, nil
+	}
+
+	members, err := repo.ListOrganizationMembers(author.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(members))
+	for _, member := range members {
+		if authz.AllowsAny(member.Roles, authz.SubmitBid) {
+			result = append(result, member.UserId)
+		}
+	}
+
+	return result, nil
+}
+
+// notifyBidAuthor tells the author of the bid about ev.
+func notifyBidAuthor(repo recipientReader, notifier Notifier, bid bids.Bid, ev notification.Event) error {
+	const op = "service.notifyBidAuthor"
+
+	ten, err := repo.GetTender(bid.TenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	recipients, err := authorRecipients(repo, bids.Author{Type: bid.AuthorType, Id: bid.AuthorId})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ev.TenderId, ev.TenderName = ten.Id, ten.Name
+	ev.BidId, ev.BidName = bid.Id, bid.Name
+	notifier.Notify(recipients, ev)
+	return nil
+}
+
+// notifyTenderStatus tells every bidder on the tender that its status
+// changed.
+func notifyTenderStatus(repo recipientReader, notifier Notifier, tenderId, reason string) error {
+	const op = "service.notifyTenderStatus"
+
+	ten, err := repo.GetTender(tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	authors, err := repo.ListTenderBidAuthors(tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var recipients []string
+	for _, author := range authors {
+		users, err := authorRecipients(repo, author)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		recipients = append(recipients, users...)
+	}
+
+	notifier.Notify(recipients, notification.Event{
+		Type:       notification.TenderStatusChanged,
+		TenderId:   ten.Id,
+		TenderName: ten.Name,
+		Status:     ten.Status,
+		Text:       reason,
+	})
+	return nil
+}
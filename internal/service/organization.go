@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/organization"
+	"tender_system/internal/storage"
+)
+
+// OrganizationRepository is the data access of the OrganizationService.
+type OrganizationRepository interface {
+	userFetcher
+	auditLog
+
+	ListOrganizations(limit, offset int) ([]organization.Organization, error)
+	GetOrganization(organizationId string) (organization.Organization, error)
+	SaveOrganization(req organization.OrganizationRequest, creatorId string) (organization.Organization, error)
+	UpdateOrganization(org organization.Organization) (organization.Organization, error)
+	DeleteOrganization(organizationId string) error
+	CountOpenTenders(organizationId string) (int, error)
+	ListResponsibles(organizationId string, limit, offset int) ([]organization.Responsible, error)
+	AddResponsible(organizationId, userId string) (organization.Responsible, error)
+	RemoveResponsible(organizationId, userId string, guard func(organizationId string, responsibles, openTenders int) error) error
+	IsOrganizationResponsible(organizationId, userId string) (bool, error)
+}
+
+type OrganizationService struct {
+	repo       OrganizationRepository
+	authorizer *authz.Authorizer
+}
+
+func NewOrganizationService(repo OrganizationRepository, authorizer *authz.Authorizer) *OrganizationService {
+	return &OrganizationService{repo: repo, authorizer: authorizer}
+}
+
+func (s *OrganizationService) ListOrganizations(limit, offset int) ([]organization.Organization, error) {
+	return s.repo.ListOrganizations(limit, offset)
+}
+
+func (s *OrganizationService) GetOrganization(organizationId string) (organization.Organization, error) {
+	return s.repo.GetOrganization(organizationId)
+}
+
+// CreateOrganization registers a new organization with username as its
+// first responsible.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, username string, req organization.OrganizationRequest) (organization.Organization, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return organization.Organization{}, storage.ErrUserNotFound
+	}
+
+	var org organization.Organization
+	err = atomically(s.repo, func(repo OrganizationRepository) error {
+		org, err = repo.SaveOrganization(req, usr.Id)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, org.Id, "organization created", usr.Username, audit.Subject{})
+	})
+	if err != nil {
+		return organization.Organization{}, err
+	}
+
+	return org, nil
+}
+
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, organizationId, username string, req organization.OrganizationPatchRequest) (organization.Organization, error) {
+	org, err := s.repo.GetOrganization(organizationId)
+	if err != nil {
+		return organization.Organization{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ManageOrganization)
+	if err != nil {
+		return organization.Organization{}, err
+	}
+
+	if req.Name != "" {
+		org.Name = req.Name
+	}
+	if req.Description != "" {
+		org.Description = req.Description
+	}
+	if req.Type != "" {
+		org.Type = req.Type
+	}
+
+	err = atomically(s.repo, func(repo OrganizationRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		org, err = repo.UpdateOrganization(org)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "organization edited", usr.Username, before)
+	})
+	if err != nil {
+		return organization.Organization{}, err
+	}
+
+	return org, nil
+}
+
+// DeleteOrganization removes an organization together with its tenders. It
+// is refused while any of the tenders is still open.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, organizationId, username string) error {
+	const op = "service.OrganizationService.DeleteOrganization"
+
+	_, err := s.repo.GetOrganization(organizationId)
+	if err != nil {
+		return err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	open, err := s.repo.CountOpenTenders(organizationId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if open > 0 {
+		return fmt.Errorf("%w: the organization has %d open tenders", storage.ErrConflict, open)
+	}
+
+	return atomically(s.repo, func(repo OrganizationRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.DeleteOrganization(organizationId)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "organization deleted", usr.Username, before)
+	})
+}
+
+func (s *OrganizationService) ListResponsibles(organizationId, username string, limit, offset int) ([]organization.Responsible, error) {
+	_, err := s.repo.GetOrganization(organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = authorize(s.repo, s.authorizer, organizationId, username, authz.ViewTender)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListResponsibles(organizationId, limit, offset)
+}
+
+func (s *OrganizationService) AddResponsible(ctx context.Context, organizationId, username, targetUsername string) (organization.Responsible, error) {
+	_, err := s.repo.GetOrganization(organizationId)
+	if err != nil {
+		return organization.Responsible{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ManageOrganization)
+	if err != nil {
+		return organization.Responsible{}, err
+	}
+
+	target, err := s.repo.FetchUser(targetUsername)
+	if err != nil {
+		return organization.Responsible{}, storage.ErrNotFound
+	}
+
+	var resp organization.Responsible
+	err = atomically(s.repo, func(repo OrganizationRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.AddResponsible(organizationId, target.Id)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, target.Username+" made responsible", usr.Username, before)
+	})
+	if err != nil {
+		return organization.Responsible{}, err
+	}
+
+	return resp, nil
+}
+
+func (s *OrganizationService) RemoveResponsible(ctx context.Context, organizationId, username, targetUsername string) error {
+	const op = "service.OrganizationService.RemoveResponsible"
+
+	_, err := s.repo.GetOrganization(organizationId)
+	if err != nil {
+		return err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.repo.FetchUser(targetUsername)
+	if err != nil {
+		return storage.ErrNotFound
+	}
+
+	ok, err := s.repo.IsOrganizationResponsible(organizationId, target.Id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return storage.ErrNotFound
+	}
+
+	return atomically(s.repo, func(repo OrganizationRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.RemoveResponsible(organizationId, target.Id, guardLastResponsible)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, target.Username+" no longer responsible", usr.Username, before)
+	})
+}
+
+// guardLastResponsible refuses to leave an organization with open tenders
+// without anyone responsible for them. The storage runs it with the
+// organization locked, counting the responsibles before the removal.
+func guardLastResponsible(organizationId string, responsibles, openTenders int) error {
+	if responsibles <= 1 && openTenders > 0 {
+		return fmt.Errorf("%w: the last responsible of organization %s cannot be removed while it has %d open tenders", storage.ErrConflict, organizationId, openTenders)
+	}
+	return nil
+}
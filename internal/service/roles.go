@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+// RoleRepository is the data access of the RoleService.
+type RoleRepository interface {
+	userFetcher
+	auditLog
+
+	ListOrganizationMembers(organizationId string) ([]user.Member, error)
+	OrganizationExists(organizationId string) (bool, error)
+	GrantRole(organizationId, userId string, role authz.Role) error
+	RevokeRole(organizationId, userId string, role authz.Role) error
+}
+
+type RoleService struct {
+	repo       RoleRepository
+	authorizer *authz.Authorizer
+}
+
+func NewRoleService(repo RoleRepository, authorizer *authz.Authorizer) *RoleService {
+	return &RoleService{repo: repo, authorizer: authorizer}
+}
+
+func (s *RoleService) ListRoles(organizationId, username string) ([]user.Member, error) {
+	const op = "service.RoleService.ListRoles"
+
+	_, err := s.requireManager(organizationId, username)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListOrganizationMembers(organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// GrantRole gives targetUsername an explicit role in the organization.
+// Granting a role the user already holds is a no-op.
+func (s *RoleService) GrantRole(ctx context.Context, organizationId, username, targetUsername, role string) error {
+	const op = "service.RoleService.GrantRole"
+
+	usr, target, err := s.resolveTarget(organizationId, username, targetUsername, role)
+	if err != nil {
+		return err
+	}
+
+	err = atomically(s.repo, func(repo RoleRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.GrantRole(organizationId, target.Id, authz.Role(role))
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "role "+role+" granted to "+target.Username, usr.Username, before)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeRole removes an explicit role. The implicit Owner role of
+// responsibles cannot be revoked this way.
+func (s *RoleService) RevokeRole(ctx context.Context, organizationId, username, targetUsername, role string) error {
+	usr, target, err := s.resolveTarget(organizationId, username, targetUsername, role)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo RoleRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.RevokeRole(organizationId, target.Id, authz.Role(role))
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "role "+role+" revoked from "+target.Username, usr.Username, before)
+	})
+}
+
+// resolveTarget checks that the manager may change the roles of the
+// organization and resolves both the manager and the target user.
+func (s *RoleService) resolveTarget(organizationId, username, targetUsername, role string) (user.User, user.User, error) {
+	if !authz.ValidRole(role) {
+		return user.User{}, user.User{}, storage.ErrBadRequest
+	}
+
+	usr, err := s.requireManager(organizationId, username)
+	if err != nil {
+		return user.User{}, user.User{}, err
+	}
+
+	target, err := s.repo.FetchUser(targetUsername)
+	if err != nil {
+		return user.User{}, user.User{}, storage.ErrNotFound
+	}
+
+	return usr, target, nil
+}
+
+func (s *RoleService) requireManager(organizationId, username string) (user.User, error) {
+	const op = "service.RoleService.requireManager"
+
+	ok, err := s.repo.OrganizationExists(organizationId)
+	if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return user.User{}, storage.ErrNotFound
+	}
+
+	return authorize(s.repo, s.authorizer, organizationId, username, authz.ManageRoles)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	scheduledomain "tender_system/internal/domain/schedule"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/job"
+	"tender_system/internal/models/schedule"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// ScheduleRepository is the data access of the ScheduleService.
+type ScheduleRepository interface {
+	userFetcher
+	auditLog
+
+	GetTender(tenderId string) (tender.Tender, error)
+	SetPublishAt(tenderId string, publishAt *time.Time) error
+	EnqueueJob(j job.Job) (job.Job, error)
+	CancelJobs(kind, subjectId string) error
+	GetRecurrence(tenderId string) (schedule.Recurrence, error)
+	SaveRecurrence(r schedule.Recurrence) (schedule.Recurrence, error)
+	AdvanceRecurrence(tenderId, lastTenderId string, nextRunAt time.Time) error
+	DeleteRecurrence(tenderId string) error
+}
+
+// ScheduleService sets up scheduled publications and recurring tenders and
+// runs the background jobs they leave.
+type ScheduleService struct {
+	repo       ScheduleRepository
+	authorizer *authz.Authorizer
+	tenders    *TenderService
+}
+
+func NewScheduleService(repo ScheduleRepository, authorizer *authz.Authorizer, tenders *TenderService) *ScheduleService {
+	return &ScheduleService{repo: repo, authorizer: authorizer, tenders: tenders}
+}
+
+// SchedulePublication publishes the tender automatically at publishAt, or
+// cancels the scheduled publication when it is nil. Only tenders still in the
+// Created status can be scheduled, by members allowed to publish them.
+func (s *ScheduleService) SchedulePublication(ctx context.Context, tenderId, username string, publishAt *time.Time) (tender.Tender, error) {
+	const op = "service.ScheduleService.SchedulePublication"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.Tender{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.PublishTender)
+	if err != nil {
+		return tender.Tender{}, err
+	}
+
+	if ten.Status != tenderdomain.Created {
+		return tender.Tender{}, fmt.Errorf("%w: only tenders in the %s status can be scheduled", storage.ErrConflict, tenderdomain.Created)
+	}
+
+	if publishAt != nil && !publishAt.After(time.Now()) {
+		return tender.Tender{}, fmt.Errorf("%w: publishAt must lie in the future", storage.ErrBadRequest)
+	}
+
+	err = atomically(s.repo, func(repo ScheduleRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		action := "tender publication cancelled"
+		if publishAt == nil {
+			err = repo.CancelJobs(scheduledomain.PublishTender, tenderId)
+		} else {
+			_, err = repo.EnqueueJob(scheduledomain.NewJob(scheduledomain.PublishTender, tenderId, usr.Username, *publishAt))
+			action = "tender publication scheduled for " + publishAt.UTC().Format(time.RFC3339)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = repo.SetPublishAt(tenderId, publishAt)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, action, usr.Username, before)
+	})
+	if err != nil {
+		return tender.Tender{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.repo.GetTender(tenderId)
+}
+
+// ReadRecurrence shows the tender's recurrence to members allowed to view the
+// organization's tenders.
+func (s *ScheduleService) ReadRecurrence(tenderId, username string) (schedule.Recurrence, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return schedule.Recurrence{}, err
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewTender)
+	if err != nil {
+		return schedule.Recurrence{}, err
+	}
+
+	return s.repo.GetRecurrence(tenderId)
+}
+
+// SetRecurrence makes the tender the base of a recurrence, replacing its
+// earlier settings. New tenders are cloned on behalf of the user, who must be
+// allowed to create tenders and, when they are published right away, to
+// publish them.
+func (s *ScheduleService) SetRecurrence(ctx context.Context, tenderId, username string, req schedule.RecurrenceRequest) (schedule.Recurrence, error) {
+	const op = "service.ScheduleService.SetRecurrence"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return schedule.Recurrence{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.CreateTender)
+	if err != nil {
+		return schedule.Recurrence{}, err
+	}
+	if req.Publish {
+		err = s.authorizer.Require(ten.OrganizationId, usr.Id, authz.PublishTender)
+		if err != nil {
+			return schedule.Recurrence{}, err
+		}
+	}
+
+	now := time.Now().UTC()
+	var next time.Time
+	if req.StartAt != nil {
+		if !req.StartAt.After(now) {
+			return schedule.Recurrence{}, fmt.Errorf("%w: startAt must lie in the future", storage.ErrBadRequest)
+		}
+		next = req.StartAt.UTC()
+	} else {
+		next, err = scheduledomain.Next(req.Frequency, now)
+		if err != nil {
+			return schedule.Recurrence{}, fmt.Errorf("%w: %s", storage.ErrBadRequest, err)
+		}
+	}
+
+	var rec schedule.Recurrence
+	err = atomically(s.repo, func(repo ScheduleRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		rec, err = repo.SaveRecurrence(schedule.Recurrence{
+			TenderId:  tenderId,
+			Frequency: req.Frequency,
+			Publish:   req.Publish,
+			NextRunAt: next,
+			CreatedBy: usr.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.EnqueueJob(scheduledomain.NewJob(scheduledomain.SpawnRecurrence, tenderId, usr.Username, next))
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "tender recurrence set to "+req.Frequency, usr.Username, before)
+	})
+	if err != nil {
+		return schedule.Recurrence{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rec, nil
+}
+
+// DeleteRecurrence stops the tender from recurring on behalf of a member
+// allowed to create the organization's tenders.
+func (s *ScheduleService) DeleteRecurrence(ctx context.Context, tenderId, username string) error {
+	const op = "service.ScheduleService.DeleteRecurrence"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.CreateTender)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo ScheduleRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.DeleteRecurrence(tenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.CancelJobs(scheduledomain.SpawnRecurrence, tenderId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "tender recurrence removed", usr.Username, before)
+	})
+}
+
+// PublishScheduled runs a publish_tender job. Tenders that left the Created
+// status meanwhile are left alone.
+func (s *ScheduleService) PublishScheduled(ctx context.Context, payload schedule.JobPayload) error {
+	ten, err := s.repo.GetTender(payload.SubjectId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if ten.Status != tenderdomain.Created {
+		return nil
+	}
+
+	_, err = s.tenders.UpdateTenderStatus(ctx, ten.Id, tenderdomain.Published, payload.Actor, "scheduled publication")
+	return err
+}
+
+// SpawnRecurring runs a spawn_recurrence job: it clones the base tender,
+// publishes the clone if asked to and schedules the next run. Runs missed
+// while no replica was running are skipped rather than caught up, and a job
+// run again after the recurrence already advanced past it does nothing.
+func (s *ScheduleService) SpawnRecurring(ctx context.Context, payload schedule.JobPayload) error {
+	rec, err := s.repo.GetRecurrence(payload.SubjectId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if rec.NextRunAt.After(now) {
+		return nil
+	}
+
+	spawned, err := s.tenders.CloneTender(ctx, rec.TenderId, rec.CreatedBy, "")
+	if err != nil {
+		return err
+	}
+
+	if rec.Publish {
+		_, err = s.tenders.UpdateTenderStatus(ctx, spawned.Id, tenderdomain.Published, rec.CreatedBy, "recurring tender")
+		if err != nil {
+			return err
+		}
+	}
+
+	next := rec.NextRunAt
+	for !next.After(now) {
+		next, err = scheduledomain.Next(rec.Frequency, next)
+		if err != nil {
+			return err
+		}
+	}
+
+	return atomically(s.repo, func(repo ScheduleRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, rec.TenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.AdvanceRecurrence(rec.TenderId, spawned.Id, next)
+		if err != nil {
+			return err
+		}
+
+		err = recordChange(ctx, repo, auditdomain.Tender, rec.TenderId, "tender recurred as "+spawned.Id, rec.CreatedBy, before)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.EnqueueJob(scheduledomain.NewJob(scheduledomain.SpawnRecurrence, rec.TenderId, rec.CreatedBy, next))
+		return err
+	})
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+)
+
+// Repository is the data access the services need. It performs no
+// permission or business rule checks of its own, so any storage backend
+// implementing it gets the same behaviour. Each service depends only on its
+// own part of it.
+type Repository interface {
+	TenderRepository
+	BidRepository
+	LotRepository
+	AuctionRepository
+	SupplierRepository
+	AttachmentRepository
+	ConflictRepository
+	RoleRepository
+	OrganizationRepository
+	EmployeeRepository
+	AuditRepository
+	NotificationRepository
+	TemplateRepository
+	ScheduleRepository
+	TransferRepository
+	JobRepository
+}
+
+// userFetcher resolves usernames.
+type userFetcher interface {
+	FetchUser(username string) (user.User, error)
+}
+
+// authorize resolves username and checks that it holds perm in the
+// organization.
+func authorize(repo userFetcher, authorizer *authz.Authorizer, organizationId, username string, perm authz.Permission) (user.User, error) {
+	usr, err := repo.FetchUser(username)
+	if err != nil {
+		return user.User{}, storage.ErrUserNotFound
+	}
+
+	err = authorizer.Require(organizationId, usr.Id, perm)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return usr, nil
+}
+
+// memberRoleReader reads the roles of organization members.
+type memberRoleReader interface {
+	ReadMemberRoles(organizationId, userId string) ([]string, error)
+}
+
+// requireMember returns storage.ErrForbidden unless the user holds any role in
+// the organization.
+func requireMember(repo memberRoleReader, organizationId, userId string) error {
+	roles, err := repo.ReadMemberRoles(organizationId, userId)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("%w: not a member of organization %s", storage.ErrForbidden, organizationId)
+	}
+	return nil
+}
+
+// organizationReader lists the organizations of a user.
+type organizationReader interface {
+	ReadUserOrganizations(userId string) ([]string, error)
+}
+
+// actingAuthors lists the bid authors the user may act as. Without a selected
+// organization that is the user and every organization the user may submit
+// bids for, otherwise only the selected organization.
+func actingAuthors(repo organizationReader, authorizer *authz.Authorizer, usr user.User, organizationId string) ([]string, error) {
+	const op = "service.actingAuthors"
+
+	if organizationId != "" {
+		err := authorizer.Require(organizationId, usr.Id, authz.SubmitBid)
+		if err != nil {
+			return nil, err
+		}
+		return []string{organizationId}, nil
+	}
+
+	organizations, err := repo.ReadUserOrganizations(usr.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	authors := []string{usr.Id}
+	for _, orgId := range organizations {
+		ok, err := authorizer.Can(orgId, usr.Id, authz.SubmitBid)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if ok {
+			authors = append(authors, orgId)
+		}
+	}
+
+	return authors, nil
+}
+
+// auditLog is the audit trail the services append their changes to.
+type auditLog interface {
+	AuditSubject(entityType, entityId string) (audit.Subject, error)
+	AppendAudit(e audit.Entry) (audit.Entry, error)
+}
+
+// transactor is implemented by repositories able to run several changes in
+// one transaction.
+type transactor interface {
+	// Atomically runs fn with a repository bound to one transaction, which
+	// commits once fn returns nil and rolls back otherwise.
+	Atomically(fn func(repo any) error) error
+}
+
+// atomically runs fn in one transaction of repo, or right against repo when
+// it has no transactions.
+func atomically[R any](repo R, fn func(repo R) error) error {
+	t, ok := any(repo).(transactor)
+	if !ok {
+		return fn(repo)
+	}
+	return t.Atomically(func(tx any) error {
+		return fn(tx.(R))
+	})
+}
+
+// snapshot reads the audited state of an entity ahead of a change to it.
+// Entities yet to be created, or already deleted, have none.
+func snapshot(log auditLog, entityType, entityId string) (audit.Subject, error) {
+	if entityId == "" {
+		return audit.Subject{}, nil
+	}
+	subject, err := log.AuditSubject(entityType, entityId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return audit.Subject{}, nil
+	}
+	if err != nil {
+		return audit.Subject{}, fmt.Errorf("service.snapshot: %w", err)
+	}
+	return subject, nil
+}
+
+// recordChange appends the audit entry of a change the actor made to the
+// entity, given its snapshot from before the change. The client address and
+// request id are those of the origin ctx carries. It is meant to run in the
+// transaction of the change, so that the change and its entry commit or roll
+// back together.
+func recordChange(ctx context.Context, log auditLog, entityType, entityId, action, actor string, before audit.Subject) error {
+	after, err := snapshot(log, entityType, entityId)
+	if err != nil {
+		return fmt.Errorf("service.recordChange: %w", err)
+	}
+	if after.EntityId != "" {
+		entityId = after.EntityId
+	} else if before.EntityId != "" {
+		entityId = before.EntityId
+	}
+	organizationId := after.OrganizationId
+	if organizationId == "" {
+		organizationId = before.OrganizationId
+	}
+
+	origin := auditdomain.OriginFrom(ctx)
+	_, err = log.AppendAudit(audit.Entry{
+		EntityType:     entityType,
+		EntityId:       entityId,
+		Action:         action,
+		Actor:          actor,
+		OrganizationId: organizationId,
+		IP:             origin.IP,
+		RequestId:      origin.RequestId,
+		Before:         before.State,
+		After:          after.State,
+	})
+	if err != nil {
+		return fmt.Errorf("service.recordChange: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,492 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	"tender_system/internal/models/attachment"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/service"
+	"tender_system/internal/storage"
+	"testing"
+	"time"
+)
+
+// checkErr fails the test unless err matches want, nil meaning success.
+func checkErr(t *testing.T, err, want error) {
+	t.Helper()
+
+	if want == nil && err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want != nil && !errors.Is(err, want) {
+		t.Fatalf("error %v, want %v", err, want)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreateTender(t *testing.T) {
+	valid := func(username string) tender.TenderRequest {
+		return tender.TenderRequest{
+			Name:            "Cleaning",
+			Description:     "Clean the office",
+			ServiceType:     "Delivery",
+			OrganizationId:  buyerOrg,
+			CreatorUsername: username,
+		}
+	}
+	with := func(change func(*tender.TenderRequest)) tender.TenderRequest {
+		req := valid(alice)
+		change(&req)
+		return req
+	}
+
+	cases := []struct {
+		name string
+		req  tender.TenderRequest
+		want error
+	}{
+		{name: "owner", req: valid(alice)},
+		{name: "unknown user", req: valid(nobody), want: storage.ErrUserNotFound},
+		{name: "outsider", req: valid(carol), want: storage.ErrForbidden},
+		{name: "bidder of another organization", req: valid(bob), want: storage.ErrForbidden},
+		{name: "unknown organization", req: with(func(r *tender.TenderRequest) { r.OrganizationId = missing }), want: storage.ErrBadRequest},
+		{name: "auction without settings", req: with(func(r *tender.TenderRequest) { r.Type = "ReverseAuction" }), want: storage.ErrBadRequest},
+		{name: "settings without an auction", req: with(func(r *tender.TenderRequest) { r.Auction = &auction.Settings{} }), want: storage.ErrBadRequest},
+		{name: "auction with lots", req: with(func(r *tender.TenderRequest) {
+			r.Type, r.Auction, r.Lots = "ReverseAuction", &auction.Settings{}, []lot.LotRequest{{Name: "Lot"}}
+		}), want: storage.ErrBadRequest},
+		{name: "inverted budget", req: with(func(r *tender.TenderRequest) { r.BudgetMin, r.BudgetMax = ptr(10.0), ptr(5.0) }), want: storage.ErrBadRequest},
+		{name: "publication in the past", req: with(func(r *tender.TenderRequest) { r.PublishAt = ptr(expired) }), want: storage.ErrBadRequest},
+		{name: "scheduled publication", req: with(func(r *tender.TenderRequest) { r.PublishAt = ptr(time.Now().Add(time.Hour)) })},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			tenders := service.NewTenderService(f, f.authz, nil)
+
+			resp, err := tenders.CreateTender(context.Background(), c.req)
+			checkErr(t, err, c.want)
+			if c.want == nil && resp.Type != "Standard" {
+				t.Fatalf("type %q, want Standard", resp.Type)
+			}
+		})
+	}
+}
+
+func TestRollbackTender(t *testing.T) {
+	cases := []struct {
+		name     string
+		tenderId string
+		username string
+		version  int
+		want     error
+		bumped   bool
+	}{
+		{name: "earlier version", tenderId: publishedTender, username: alice, version: 1, bumped: true},
+		{name: "current version", tenderId: publishedTender, username: alice, version: 3},
+		{name: "version zero", tenderId: publishedTender, username: alice, version: 0, want: storage.ErrBadRequest},
+		{name: "future version", tenderId: publishedTender, username: alice, version: 4, want: storage.ErrBadRequest},
+		{name: "outsider", tenderId: publishedTender, username: carol, version: 1, want: storage.ErrForbidden},
+		{name: "unknown user", tenderId: publishedTender, username: nobody, version: 1, want: storage.ErrUserNotFound},
+		{name: "unknown tender", tenderId: missing, username: alice, version: 1, want: storage.ErrNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			tenders := service.NewTenderService(f, f.authz, nil)
+			before := f.tenders[publishedTender].Version
+
+			_, err := tenders.RollbackTender(context.Background(), c.tenderId, c.username, c.version)
+			checkErr(t, err, c.want)
+			if bumped := f.tenders[publishedTender].Version != before; bumped != c.bumped {
+				t.Fatalf("new version stored: %v, want %v", bumped, c.bumped)
+			}
+		})
+	}
+}
+
+func TestCreateBid(t *testing.T) {
+	bid := func(authorType, authorId, tenderId string) bids.BidRequest {
+		return bids.BidRequest{Name: "Bid", Description: "Bid", TenderId: tenderId, AuthorType: authorType, AuthorId: authorId}
+	}
+	priced := func(price float64) bids.BidRequest {
+		req := bid("User", "user-"+bob, publishedTender)
+		req.Price = &price
+		return req
+	}
+	onLots := func(lotIds ...string) bids.BidRequest {
+		req := bid("User", "user-"+bob, lotTender)
+		req.LotIds = lotIds
+		return req
+	}
+
+	cases := []struct {
+		name      string
+		req       bids.BidRequest
+		want      error
+		conflicts int
+	}{
+		{name: "user bid", req: bid("User", "user-"+bob, publishedTender)},
+		{name: "organization bid", req: bid("Organization", supplierOrg, publishedTender)},
+		{name: "unknown user", req: bid("User", "user-"+nobody, publishedTender), want: storage.ErrUserNotFound},
+		{name: "unknown organization", req: bid("Organization", missing, publishedTender), want: storage.ErrUserNotFound},
+		{name: "user without an organization", req: bid("User", "user-"+carol, publishedTender), want: storage.ErrForbidden},
+		{name: "unknown tender", req: bid("User", "user-"+bob, missing), want: storage.ErrNotFound},
+		{name: "draft tender", req: bid("User", "user-"+bob, draftTender)},
+		{name: "closed tender", req: bid("User", "user-"+bob, closedTender), want: storage.ErrBadRequest},
+		{name: "member of the tender's organization", req: bid("User", "user-"+alice, publishedTender), want: storage.ErrForbidden, conflicts: 1},
+		{name: "own organization", req: bid("Organization", buyerOrg, publishedTender), want: storage.ErrForbidden, conflicts: 1},
+		{name: "price within the budget", req: priced(900)},
+		{name: "price over a strict budget", req: priced(1100), want: storage.ErrBadRequest},
+		{name: "lot of the tender", req: onLots("lot-1")},
+		{name: "no lot on a tender with lots", req: onLots(), want: storage.ErrBadRequest},
+		{name: "lot of another tender", req: onLots("lot-2"), want: storage.ErrBadRequest},
+		{name: "lot on a tender without lots", req: func() bids.BidRequest {
+			req := bid("User", "user-"+bob, publishedTender)
+			req.LotIds = []string{"lot-1"}
+			return req
+		}(), want: storage.ErrBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			bidService := service.NewBidService(f, f.authz, nil)
+
+			resp, err := bidService.CreateBid(context.Background(), c.req)
+			checkErr(t, err, c.want)
+			if c.want == nil && resp.Status != biddomain.Draft {
+				t.Fatalf("status %q, want %s", resp.Status, biddomain.Draft)
+			}
+			if len(f.conflicts) != c.conflicts {
+				t.Fatalf("%d conflicts recorded, want %d", len(f.conflicts), c.conflicts)
+			}
+		})
+	}
+}
+
+func TestChangeBidStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		username string
+		status   string
+		deadline *time.Time
+		want     error
+	}{
+		{name: "author withdraws", username: bob, status: biddomain.Withdrawn},
+		{name: "legacy status", username: bob, status: "Canceled"},
+		{name: "fellow responsible", username: carol, status: biddomain.Withdrawn},
+		{name: "outsider", username: alice, status: biddomain.Withdrawn, want: storage.ErrForbidden},
+		{name: "unknown user", username: nobody, status: biddomain.Withdrawn, want: storage.ErrUserNotFound},
+		{name: "decision", username: bob, status: biddomain.Approved, want: storage.ErrBadRequest},
+		{name: "same status", username: bob, status: biddomain.Submitted, want: biddomain.ErrInvalidTransition},
+		{name: "after the deadline", username: bob, status: biddomain.Withdrawn, deadline: &expired, want: biddomain.ErrInvalidTransition},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			f.responsibles["user-"+bob] = []string{supplierOrg}
+			f.responsibles["user-"+carol] = []string{supplierOrg}
+			ten := f.tenders[publishedTender]
+			ten.Deadline = c.deadline
+			f.tenders[publishedTender] = ten
+			bidService := service.NewBidService(f, f.authz, nil)
+
+			resp, err := bidService.ChangeBidStatus(context.Background(), userBid, c.status, c.username)
+			checkErr(t, err, c.want)
+			if c.want == nil && resp.Status != biddomain.Normalize(c.status) {
+				t.Fatalf("status %q, want %q", resp.Status, biddomain.Normalize(c.status))
+			}
+		})
+	}
+}
+
+func TestEditBid(t *testing.T) {
+	setStatus := func(status string) func(f *fixture) {
+		return func(f *fixture) {
+			bid := f.bids[userBid]
+			bid.Status = status
+			f.bids[userBid] = bid
+		}
+	}
+
+	cases := []struct {
+		name     string
+		username string
+		price    *float64
+		setup    func(f *fixture)
+		want     error
+	}{
+		{name: "author", username: bob, price: ptr(950.0)},
+		{name: "without a price", username: bob},
+		{name: "price over a strict budget", username: bob, price: ptr(1500.0), want: storage.ErrBadRequest},
+		{name: "tender owner", username: alice, price: ptr(950.0), want: storage.ErrForbidden},
+		{name: "unknown user", username: nobody, want: storage.ErrUserNotFound},
+		{name: "approved bid", username: bob, setup: setStatus(biddomain.Approved), want: storage.ErrConflict},
+		{name: "rejected bid", username: bob, setup: setStatus(biddomain.Rejected), want: storage.ErrConflict},
+		{name: "withdrawn bid", username: bob, setup: setStatus(biddomain.Withdrawn), want: storage.ErrConflict},
+		{
+			name:     "after the deadline",
+			username: bob,
+			setup: func(f *fixture) {
+				ten := f.tenders[publishedTender]
+				ten.Deadline = ptr(time.Now().Add(-time.Hour))
+				f.tenders[publishedTender] = ten
+			},
+			want: storage.ErrConflict,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			if c.setup != nil {
+				c.setup(f)
+			}
+			bidService := service.NewBidService(f, f.authz, nil)
+
+			_, err := bidService.EditBid(context.Background(), userBid, c.username, "Renamed", "", c.price)
+			checkErr(t, err, c.want)
+		})
+	}
+}
+
+func TestRollbackBid(t *testing.T) {
+	cases := []struct {
+		name     string
+		username string
+		version  int
+		auction  bool
+		want     error
+	}{
+		{name: "earlier version", username: bob, version: 1},
+		{name: "current version", username: bob, version: 2, want: storage.ErrBadRequest},
+		{name: "version zero", username: bob, version: 0, want: storage.ErrBadRequest},
+		{name: "tender owner", username: alice, version: 1, want: storage.ErrForbidden},
+		{name: "other price in an auction", username: bob, version: 1, auction: true, want: storage.ErrConflict},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			if c.auction {
+				f.auctions[publishedTender] = auction.Auction{TenderId: publishedTender}
+			}
+			bidService := service.NewBidService(f, f.authz, nil)
+
+			resp, err := bidService.RollbackBid(context.Background(), userBid, c.username, c.version)
+			checkErr(t, err, c.want)
+			if c.want != nil {
+				return
+			}
+
+			old := f.history[userBid][c.version-1]
+			if resp.Status != biddomain.Submitted {
+				t.Fatalf("status %q, want the current %s", resp.Status, biddomain.Submitted)
+			}
+			if resp.Name != old.Name || *resp.Price != *old.Price {
+				t.Fatalf("restored %q at %v, want %q at %v", resp.Name, *resp.Price, old.Name, *old.Price)
+			}
+		})
+	}
+}
+
+func TestAuthorizeAttachments(t *testing.T) {
+	cases := []struct {
+		name       string
+		entityType string
+		entityId   string
+		username   string
+		write      bool
+		want       error
+	}{
+		{name: "read a published tender anonymously", entityType: attachment.Tender, entityId: publishedTender},
+		{name: "read a draft tender as its owner", entityType: attachment.Tender, entityId: draftTender, username: alice},
+		{name: "read a draft tender as an outsider", entityType: attachment.Tender, entityId: draftTender, username: carol, want: storage.ErrForbidden},
+		{name: "change a tender as its owner", entityType: attachment.Tender, entityId: publishedTender, username: alice, write: true},
+		{name: "change a tender as a bidder", entityType: attachment.Tender, entityId: publishedTender, username: bob, write: true, want: storage.ErrForbidden},
+		{name: "change a tender anonymously", entityType: attachment.Tender, entityId: publishedTender, write: true, want: storage.ErrUserNotFound},
+		{name: "unknown tender", entityType: attachment.Tender, entityId: missing, username: alice, want: storage.ErrNotFound},
+		{name: "change a bid as its author", entityType: attachment.Bid, entityId: userBid, username: bob, write: true},
+		{name: "read a bid as the tender owner", entityType: attachment.Bid, entityId: userBid, username: alice},
+		{name: "change a bid as the tender owner", entityType: attachment.Bid, entityId: userBid, username: alice, write: true, want: storage.ErrForbidden},
+		{name: "read a bid as an outsider", entityType: attachment.Bid, entityId: userBid, username: carol, want: storage.ErrForbidden},
+		{name: "unknown entity type", entityType: "lot", entityId: "lot-1", username: alice, want: storage.ErrBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			attachments := service.NewAttachmentService(f, f.authz)
+
+			err := attachments.AuthorizeAttachments(c.entityType, c.entityId, c.username, c.write)
+			checkErr(t, err, c.want)
+		})
+	}
+}
+
+func TestAuditEntries(t *testing.T) {
+	f := newFixture()
+	f.responsibles["user-"+bob] = []string{supplierOrg}
+	f.responsibles["user-"+carol] = []string{supplierOrg}
+	origin := auditdomain.Origin{IP: "203.0.113.7", RequestId: "req-1"}
+	ctx := auditdomain.WithOrigin(context.Background(), origin)
+
+	_, err := service.NewTenderService(f, f.authz, nil).RollbackTender(ctx, publishedTender, alice, 1)
+	checkErr(t, err, nil)
+	_, err = service.NewBidService(f, f.authz, nil).ChangeBidStatus(context.Background(), userBid, biddomain.Withdrawn, carol)
+	checkErr(t, err, nil)
+	_, err = service.NewTenderService(f, f.authz, nil).RollbackTender(ctx, publishedTender, carol, 1)
+	checkErr(t, err, storage.ErrForbidden)
+
+	if len(f.audit) != 2 {
+		t.Fatalf("%d audit entries, want 2", len(f.audit))
+	}
+
+	rollback := f.audit[0]
+	if rollback.EntityType != auditdomain.Tender || rollback.EntityId != publishedTender || rollback.OrganizationId != buyerOrg {
+		t.Fatalf("rollback recorded as %s %s in %s", rollback.EntityType, rollback.EntityId, rollback.OrganizationId)
+	}
+	if rollback.Actor != alice || rollback.IP != origin.IP || rollback.RequestId != origin.RequestId {
+		t.Fatalf("rollback recorded by %s from %s in %s", rollback.Actor, rollback.IP, rollback.RequestId)
+	}
+	if bytes.Equal(rollback.Before, rollback.After) {
+		t.Fatal("rollback recorded without a change of state")
+	}
+
+	// The responsible acting for the author is recorded, not the author.
+	withdrawal := f.audit[1]
+	if withdrawal.EntityType != auditdomain.Bid || withdrawal.Actor != carol {
+		t.Fatalf("withdrawal recorded as %s by %s", withdrawal.EntityType, withdrawal.Actor)
+	}
+	if withdrawal.IP != "" || withdrawal.RequestId != "" {
+		t.Fatalf("withdrawal without an origin recorded from %s in %s", withdrawal.IP, withdrawal.RequestId)
+	}
+}
+
+// errDatabase stands for a failing database.
+var errDatabase = errors.New("connection refused")
+
+// brokenLookups fails the existence checks the way a database outage does.
+type brokenLookups struct {
+	*fixture
+}
+
+func (brokenLookups) OrganizationExists(string) (bool, error) {
+	return false, errDatabase
+}
+
+func (brokenLookups) EmployeeExists(string) (bool, error) {
+	return false, errDatabase
+}
+
+// TestExistenceCheckErrors checks that a failing existence check is reported
+// as the error it is rather than as a missing organization or employee.
+func TestExistenceCheckErrors(t *testing.T) {
+	f := newFixture()
+	repo := brokenLookups{f}
+
+	_, err := service.NewRoleService(repo, f.authz).ListRoles(buyerOrg, alice)
+	checkErr(t, err, errDatabase)
+
+	_, err = service.NewTenderService(repo, f.authz, nil).CreateTender(context.Background(), tender.TenderRequest{
+		Name: "Cleaning", Description: "Clean the office", ServiceType: "Delivery", OrganizationId: buyerOrg, CreatorUsername: alice,
+	})
+	checkErr(t, err, errDatabase)
+
+	bidService := service.NewBidService(repo, f.authz, nil)
+	for _, authorType := range []string{"User", "Organization"} {
+		_, err = bidService.CreateBid(context.Background(), bids.BidRequest{
+			Name: "Bid", Description: "Bid", TenderId: publishedTender, AuthorType: authorType, AuthorId: "user-" + bob,
+		})
+		checkErr(t, err, errDatabase)
+	}
+}
+
+// transactional runs changes the way a database transaction does: a failing
+// change leaves the fixture as it was. Its audit log fails with the errors
+// given.
+type transactional struct {
+	*fixture
+	failSubject error
+	failAudit   error
+}
+
+func (r transactional) Atomically(fn func(repo any) error) error {
+	tenders, bidRows, history, entries := maps.Clone(r.tenders), maps.Clone(r.bids), maps.Clone(r.history), slices.Clone(r.audit)
+	err := fn(r)
+	if err != nil {
+		r.tenders, r.bids, r.history, r.audit = tenders, bidRows, history, entries
+	}
+	return err
+}
+
+func (r transactional) AuditSubject(entityType, entityId string) (audit.Subject, error) {
+	if r.failSubject != nil {
+		return audit.Subject{}, r.failSubject
+	}
+	return r.fixture.AuditSubject(entityType, entityId)
+}
+
+func (r transactional) AppendAudit(e audit.Entry) (audit.Entry, error) {
+	if r.failAudit != nil {
+		return audit.Entry{}, r.failAudit
+	}
+	return r.fixture.AppendAudit(e)
+}
+
+// TestAuditInTransaction checks that changes are kept only together with
+// their audit entries and that a failing snapshot fails the change.
+func TestAuditInTransaction(t *testing.T) {
+	cases := []struct {
+		name string
+		repo func(f *fixture) transactional
+		want error
+	}{
+		{name: "audited", repo: func(f *fixture) transactional { return transactional{fixture: f} }},
+		{name: "failing snapshot", repo: func(f *fixture) transactional { return transactional{fixture: f, failSubject: errDatabase} }, want: errDatabase},
+		{name: "failing audit log", repo: func(f *fixture) transactional { return transactional{fixture: f, failAudit: errDatabase} }, want: errDatabase},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newFixture()
+			repo := c.repo(f)
+			version := f.bids[userBid].Version
+			tenders := len(f.tenders)
+
+			_, err := service.NewBidService(repo, f.authz, nil).EditBid(context.Background(), userBid, bob, "Renamed", "", ptr(950.0))
+			checkErr(t, err, c.want)
+			_, err = service.NewTenderService(repo, f.authz, nil).CreateTender(context.Background(), tender.TenderRequest{
+				Name: "Cleaning", Description: "Clean the office", ServiceType: "Delivery", OrganizationId: buyerOrg, CreatorUsername: alice,
+			})
+			checkErr(t, err, c.want)
+
+			changes := 2
+			if c.want != nil {
+				changes = 0
+			}
+			if got := f.bids[userBid].Version - version + len(f.tenders) - tenders; got != changes {
+				t.Fatalf("%d changes kept, want %d", got, changes)
+			}
+			if len(f.audit) != changes {
+				t.Fatalf("%d audit entries, want %d", len(f.audit), changes)
+			}
+		})
+	}
+}
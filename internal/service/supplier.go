@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/award"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/supplier"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// SupplierRepository is the data access of the SupplierService.
+type SupplierRepository interface {
+	organizationReader
+	responsibleReader
+	userFetcher
+	auditLog
+
+	GetTender(tenderId string) (tender.Tender, error)
+	GetBid(bidId string) (bids.Bid, error)
+	OrganizationExists(organizationId string) (bool, error)
+	ReadUserMemberships(userId string) ([]user.Membership, error)
+	ReadSupplierStats(authorType, authorId string) (supplier.Stats, error)
+	GetAward(tenderId, lotId string) (award.Award, error)
+	RecordDelivery(awardId string, d award.Delivery) error
+	GetContract(aw award.Award) (award.Contract, error)
+}
+
+type SupplierService struct {
+	repo       SupplierRepository
+	authorizer *authz.Authorizer
+}
+
+func NewSupplierService(repo SupplierRepository, authorizer *authz.Authorizer) *SupplierService {
+	return &SupplierService{repo: repo, authorizer: authorizer}
+}
+
+// ReadSupplierProfile sums up the history of a bid author, an organization or
+// a user, across all tenders. It is meant for reviewers weighing a bid, so
+// besides the supplier itself only users allowed to read feedback in some
+// organization may see it.
+func (s *SupplierService) ReadSupplierProfile(authorId, username string) (supplier.Profile, error) {
+	const op = "service.SupplierService.ReadSupplierProfile"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return supplier.Profile{}, storage.ErrUserNotFound
+	}
+
+	authors, err := actingAuthors(s.repo, s.authorizer, usr, "")
+	if err != nil {
+		return supplier.Profile{}, err
+	}
+
+	if !slices.Contains(authors, authorId) {
+		memberships, err := s.repo.ReadUserMemberships(usr.Id)
+		if err != nil {
+			return supplier.Profile{}, fmt.Errorf("%s: %w", op, err)
+		}
+		reviewer := false
+		for _, m := range memberships {
+			if authz.AllowsAny(m.Roles, authz.ReadFeedback) {
+				reviewer = true
+				break
+			}
+		}
+		if !reviewer {
+			return supplier.Profile{}, fmt.Errorf("%w: only reviewers may see supplier profiles", storage.ErrForbidden)
+		}
+	}
+
+	authorType := "User"
+	isOrganization, err := s.repo.OrganizationExists(authorId)
+	if err != nil {
+		return supplier.Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if isOrganization {
+		authorType = "Organization"
+	}
+
+	stats, err := s.repo.ReadSupplierStats(authorType, authorId)
+	if err != nil {
+		return supplier.Profile{}, err
+	}
+
+	return supplier.Profile{
+		AuthorType:       stats.AuthorType,
+		AuthorId:         stats.AuthorId,
+		Name:             stats.Name,
+		Bids:             stats.Bids,
+		Tenders:          stats.Tenders,
+		Decided:          stats.Decided,
+		Won:              stats.Won,
+		WinRate:          ratio(stats.Won, stats.Decided),
+		Ratings:          stats.Ratings,
+		AverageRating:    ratio(stats.RatingSum, stats.Ratings),
+		Deliveries:       stats.Deliveries,
+		OnTimeDeliveries: stats.OnTimeDeliveries,
+		OnTimeRate:       ratio(stats.OnTimeDeliveries, stats.Deliveries),
+	}, nil
+}
+
+// ReadAward returns the award of the tender, or of one of its lots when lotId
+// is set, to the members allowed to view awards in the tender's organization
+// and to the authors of the winning bid.
+func (s *SupplierService) ReadAward(tenderId, lotId, username string) (award.Award, error) {
+	const op = "service.SupplierService.ReadAward"
+
+	aw, err := s.repo.GetAward(tenderId, lotId)
+	if err != nil {
+		return award.Award{}, err
+	}
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return award.Award{}, storage.ErrUserNotFound
+	}
+
+	ten, err := s.repo.GetTender(aw.TenderId)
+	if err != nil {
+		return award.Award{}, err
+	}
+
+	ok, err := s.authorizer.Can(ten.OrganizationId, usr.Id, authz.ViewAward)
+	if err != nil {
+		return award.Award{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if ok {
+		return aw, nil
+	}
+
+	bid, err := s.repo.GetBid(aw.BidId)
+	if err != nil {
+		return award.Award{}, err
+	}
+
+	ok, err = actsForAuthor(s.repo, s.authorizer, bid, usr)
+	if err != nil {
+		return award.Award{}, err
+	}
+	if !ok {
+		return award.Award{}, storage.ErrForbidden
+	}
+
+	return aw, nil
+}
+
+// ReadContract returns the contract of the award to whoever may read the
+// award.
+func (s *SupplierService) ReadContract(tenderId, lotId, username string) (award.Contract, error) {
+	aw, err := s.ReadAward(tenderId, lotId, username)
+	if err != nil {
+		return award.Contract{}, err
+	}
+
+	return s.repo.GetContract(aw)
+}
+
+// RecordDelivery marks whether the winner of the tender, or of one of its lots
+// when lotId is set, delivered on time. Members allowed to edit the tender
+// record it once the work is done.
+func (s *SupplierService) RecordDelivery(ctx context.Context, tenderId, lotId, username string, req award.DeliveryRequest) (award.Award, error) {
+	const op = "service.SupplierService.RecordDelivery"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return award.Award{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.EditTender)
+	if err != nil {
+		return award.Award{}, err
+	}
+
+	aw, err := s.repo.GetAward(tenderId, lotId)
+	if err != nil {
+		return award.Award{}, err
+	}
+
+	delivery := award.Delivery{OnTime: *req.OnTime, RecordedBy: usr.Username, RecordedAt: time.Now().UTC()}
+	action := "delivery of award " + aw.Id + " recorded late"
+	if delivery.OnTime {
+		action = "delivery of award " + aw.Id + " recorded on time"
+	}
+	err = atomically(s.repo, func(repo SupplierRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.RecordDelivery(aw.Id, delivery)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, action, usr.Username, before)
+	})
+	if err != nil {
+		return award.Award{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	aw.Delivery = &delivery
+	return aw, nil
+}
+
+// ratio returns n/d rounded to two decimals, or nil when d is zero.
+func ratio(n, d int) *float64 {
+	if d == 0 {
+		return nil
+	}
+	r := math.Round(float64(n)/float64(d)*100) / 100
+	return &r
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/template"
+	"tender_system/internal/models/tender"
+	"time"
+)
+
+// TemplateRepository is the data access of the TemplateService.
+type TemplateRepository interface {
+	tenderCreator
+
+	ListTemplates(organizationId string) ([]template.Template, error)
+	GetTemplate(organizationId, templateId string) (template.Template, error)
+	SaveTemplate(t template.Template) (template.Template, error)
+	DeleteTemplate(organizationId, templateId string) error
+}
+
+type TemplateService struct {
+	repo       TemplateRepository
+	authorizer *authz.Authorizer
+}
+
+func NewTemplateService(repo TemplateRepository, authorizer *authz.Authorizer) *TemplateService {
+	return &TemplateService{repo: repo, authorizer: authorizer}
+}
+
+// ListTemplates shows the organization's templates to members allowed to
+// view its tenders.
+func (s *TemplateService) ListTemplates(organizationId, username string) ([]template.Template, error) {
+	_, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ViewTender)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListTemplates(organizationId)
+}
+
+func (s *TemplateService) GetTemplate(organizationId, templateId, username string) (template.Template, error) {
+	_, err := authorize(s.repo, s.authorizer, organizationId, username, authz.ViewTender)
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	return s.repo.GetTemplate(organizationId, templateId)
+}
+
+// CreateTemplate saves a template for members allowed to create the
+// organization's tenders.
+func (s *TemplateService) CreateTemplate(ctx context.Context, organizationId, username string, req template.TemplateRequest) (template.Template, error) {
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.CreateTender)
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	var t template.Template
+	err = atomically(s.repo, func(repo TemplateRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		t, err = repo.SaveTemplate(template.Template{
+			OrganizationId: organizationId,
+			Title:          req.Title,
+			Spec:           req.Spec,
+			CreatedBy:      usr.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "template "+t.Id+" created", usr.Username, before)
+	})
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TemplateService) UpdateTemplate(ctx context.Context, organizationId, templateId, username string, req template.TemplateRequest) (template.Template, error) {
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.CreateTender)
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	t, err := s.repo.GetTemplate(organizationId, templateId)
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	t.Title, t.Spec = req.Title, req.Spec
+	err = atomically(s.repo, func(repo TemplateRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		t, err = repo.SaveTemplate(t)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "template "+templateId+" edited", usr.Username, before)
+	})
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, organizationId, templateId, username string) error {
+	usr, err := authorize(s.repo, s.authorizer, organizationId, username, authz.CreateTender)
+	if err != nil {
+		return err
+	}
+
+	return atomically(s.repo, func(repo TemplateRepository) error {
+		before, err := snapshot(repo, auditdomain.Organization, organizationId)
+		if err != nil {
+			return err
+		}
+
+		err = repo.DeleteTemplate(organizationId, templateId)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Organization, organizationId, "template "+templateId+" deleted", usr.Username, before)
+	})
+}
+
+// Instantiate creates a tender from the template with the given overrides
+// applied. The deadline is counted from now.
+func (s *TemplateService) Instantiate(ctx context.Context, organizationId, templateId, username string, overrides template.Overrides) (tender.TenderResponse, error) {
+	_, err := authorize(s.repo, s.authorizer, organizationId, username, authz.CreateTender)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	t, err := s.repo.GetTemplate(organizationId, templateId)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	spec := t.Spec
+	if overrides.Name != "" {
+		spec.Name = overrides.Name
+	}
+	if overrides.Description != "" {
+		spec.Description = overrides.Description
+	}
+	if overrides.ServiceType != "" {
+		spec.ServiceType = overrides.ServiceType
+	}
+	if overrides.Criteria != nil {
+		spec.Criteria = overrides.Criteria
+	}
+	if overrides.DeadlineOffsetHours != nil {
+		spec.DeadlineOffsetHours = overrides.DeadlineOffsetHours
+	}
+	if overrides.BudgetMin != nil {
+		spec.BudgetMin = overrides.BudgetMin
+	}
+	if overrides.BudgetMax != nil {
+		spec.BudgetMax = overrides.BudgetMax
+	}
+	if overrides.Currency != "" {
+		spec.Currency = overrides.Currency
+	}
+
+	req := tenderRequest(spec, organizationId, username, time.Now().UTC())
+	if overrides.Deadline != nil {
+		req.Deadline = overrides.Deadline
+	}
+
+	return createTender(ctx, s.repo, s.authorizer, req, "tender created from template "+templateId)
+}
+
+// tenderRequest turns a template spec into a request for a tender created at
+// now.
+func tenderRequest(spec template.Spec, organizationId, username string, now time.Time) tender.TenderRequest {
+	req := tender.TenderRequest{
+		Name:             spec.Name,
+		Description:      spec.Description,
+		ServiceType:      spec.ServiceType,
+		OrganizationId:   organizationId,
+		CreatorUsername:  username,
+		Criteria:         spec.Criteria,
+		Lots:             spec.Lots,
+		Type:             spec.Type,
+		Auction:          spec.Auction,
+		BudgetMin:        spec.BudgetMin,
+		BudgetMax:        spec.BudgetMax,
+		Currency:         spec.Currency,
+		BudgetVisibility: spec.BudgetVisibility,
+		BudgetPolicy:     spec.BudgetPolicy,
+	}
+
+	if spec.DeadlineOffsetHours != nil {
+		deadline := now.Add(time.Duration(*spec.DeadlineOffsetHours) * time.Hour)
+		req.Deadline = &deadline
+	}
+
+	return req
+}
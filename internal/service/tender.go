@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tender_system/internal/authz"
+	auctiondomain "tender_system/internal/domain/auction"
+	auditdomain "tender_system/internal/domain/audit"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/storage"
+	"time"
+)
+
+// TenderRepository is the data access of the TenderService.
+type TenderRepository interface {
+	memberRoleReader
+	recipientReader
+	tenderCreator
+
+	ListCreatorTenders(username, organizationId string, limit, offset int) ([]tender.TenderResponse, error)
+	ListBudgetLines(tenderId string) ([]tender.BudgetReportLine, error)
+	ListCommitteeBids(tenderId string) ([]tender.CommitteeBid, error)
+	ListCommitteeFeedback(tenderId string) ([]tender.CommitteeFeedback, error)
+	ListCommitteeVotes(tenderId string) ([]tender.CommitteeVote, error)
+	IsOrganizationResponsible(organizationId, userId string) (bool, error)
+	ListLots(tenderId string) ([]lot.Lot, error)
+	GetAuction(tenderId string) (auction.Auction, error)
+	UpdateTenderStatus(tenderId, status, actor, reason string, guard func(from string, facts tenderdomain.Facts) error) (tender.TenderResponse, error)
+	ReadTenderFacts(tenderId string) (tenderdomain.Facts, error)
+	ListTenderTransitions(tenderId string) ([]tender.TenderTransition, error)
+	PatchTender(tenderId, name, description, serviceType string, deadline *time.Time) (tender.TenderResponse, error)
+	RollbackTender(tenderId string, version int) (tender.TenderResponse, error)
+}
+
+type TenderService struct {
+	repo       TenderRepository
+	authorizer *authz.Authorizer
+	notifier   Notifier
+}
+
+func NewTenderService(repo TenderRepository, authorizer *authz.Authorizer, notifier Notifier) *TenderService {
+	return &TenderService{repo: repo, authorizer: authorizer, notifier: notifier}
+}
+
+// ReadTenderStatus returns the status of a published tender to anyone and of
+// any other tender to members allowed to view the organization's tenders.
+func (s *TenderService) ReadTenderStatus(tenderId, username string) (string, error) {
+	const op = "service.TenderService.ReadTenderStatus"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return "", err
+	}
+
+	if ten.Status == tenderdomain.Published {
+		return ten.Status, nil
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewTender)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ten.Status, nil
+}
+
+// CreateTender creates a tender in the Created status for its creator, who
+// must be allowed to create tenders for the organization and, to have it
+// published automatically at publishAt, to publish them.
+func (s *TenderService) CreateTender(ctx context.Context, req tender.TenderRequest) (tender.TenderResponse, error) {
+	return createTender(ctx, s.repo, s.authorizer, req, "tender created")
+}
+
+// tenderCreator checks and stores new tenders.
+type tenderCreator interface {
+	userFetcher
+	auditLog
+	OrganizationExists(organizationId string) (bool, error)
+	SaveTender(ten tender.TenderRequest) (tender.TenderResponse, error)
+}
+
+// createTender checks and stores a new tender, recording its creation as
+// action.
+func createTender(ctx context.Context, repo tenderCreator, authorizer *authz.Authorizer, req tender.TenderRequest, action string) (tender.TenderResponse, error) {
+	const op = "service.createTender"
+
+	usr, err := repo.FetchUser(req.CreatorUsername)
+	if err != nil {
+		return tender.TenderResponse{}, storage.ErrUserNotFound
+	}
+
+	ok, err := repo.OrganizationExists(req.OrganizationId)
+	if err != nil {
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return tender.TenderResponse{}, fmt.Errorf("%w: organization %s does not exist", storage.ErrBadRequest, req.OrganizationId)
+	}
+
+	err = authorizer.Require(req.OrganizationId, usr.Id, authz.CreateTender)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	if req.Type == "" {
+		req.Type = "Standard"
+	}
+	if (req.Type == auctiondomain.ReverseAuction) != (req.Auction != nil) {
+		return tender.TenderResponse{}, fmt.Errorf("%w: auction settings are required for and only allowed on %s tenders", storage.ErrBadRequest, auctiondomain.ReverseAuction)
+	}
+	if req.Auction != nil && len(req.Lots) > 0 {
+		return tender.TenderResponse{}, fmt.Errorf("%w: a reverse auction cannot have lots", storage.ErrBadRequest)
+	}
+	if req.BudgetMin != nil && req.BudgetMax != nil && *req.BudgetMin > *req.BudgetMax {
+		return tender.TenderResponse{}, fmt.Errorf("%w: budgetMin exceeds budgetMax", storage.ErrBadRequest)
+	}
+	if req.BudgetVisibility == "" {
+		req.BudgetVisibility = tenderdomain.BudgetPublic
+	}
+	if req.BudgetPolicy == "" {
+		req.BudgetPolicy = tenderdomain.BudgetFlag
+	}
+
+	if req.PublishAt != nil {
+		if !req.PublishAt.After(time.Now()) {
+			return tender.TenderResponse{}, fmt.Errorf("%w: publishAt must lie in the future", storage.ErrBadRequest)
+		}
+		err = authorizer.Require(req.OrganizationId, usr.Id, authz.PublishTender)
+		if err != nil {
+			return tender.TenderResponse{}, err
+		}
+	}
+
+	var resp tender.TenderResponse
+	err = atomically(repo, func(repo tenderCreator) error {
+		resp, err = repo.SaveTender(req)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, resp.Id, action, usr.Username, audit.Subject{})
+	})
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// UpdateTenderStatus moves the tender to a new status on behalf of a member
+// allowed to publish the organization's tenders and lets everyone who bid on
+// it know. Statuses only the server sets cannot be requested.
+func (s *TenderService) UpdateTenderStatus(ctx context.Context, tenderId, status, username, reason string) (tender.TenderResponse, error) {
+	const op = "service.TenderService.UpdateTenderStatus"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.PublishTender)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	if !tenderdomain.Valid(status) || tenderdomain.IsSystem(status) {
+		return tender.TenderResponse{}, fmt.Errorf("%w: invalid status %q", storage.ErrBadRequest, status)
+	}
+
+	var resp tender.TenderResponse
+	err = atomically(s.repo, func(repo TenderRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.UpdateTenderStatus(tenderId, status, usr.Username, reason, func(from string, facts tenderdomain.Facts) error {
+			return tenderdomain.Transition(from, status, facts)
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordChange(ctx, repo, auditdomain.Tender, tenderId, "tender status changed to "+status, usr.Username, before)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	err = notifyTenderStatus(s.repo, s.notifier, tenderId, reason)
+	if err != nil {
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// ReadTenderTransitions returns the status of the tender, the statuses it may
+// move to next and the transitions it went through to members allowed to
+// view the organization's tenders.
+func (s *TenderService) ReadTenderTransitions(tenderId, username string) (tender.TenderTransitionsResponse, error) {
+	const op = "service.TenderService.ReadTenderTransitions"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.TenderTransitionsResponse{}, err
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewTender)
+	if err != nil {
+		return tender.TenderTransitionsResponse{}, err
+	}
+
+	facts, err := s.repo.ReadTenderFacts(tenderId)
+	if err != nil {
+		return tender.TenderTransitionsResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	history, err := s.repo.ListTenderTransitions(tenderId)
+	if err != nil {
+		return tender.TenderTransitionsResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tender.TenderTransitionsResponse{
+		Status:    ten.Status,
+		Available: tenderdomain.Next(ten.Status, facts),
+		History:   history,
+	}, nil
+}
+
+// PatchTender changes the non-empty fields of the tender on behalf of a
+// member allowed to edit the organization's tenders.
+func (s *TenderService) PatchTender(ctx context.Context, tenderId, username, name, description, serviceType string, deadline *time.Time) (tender.TenderResponse, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.EditTender)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	var resp tender.TenderResponse
+	err = atomically(s.repo, func(repo TenderRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.PatchTender(tenderId, name, description, serviceType, deadline)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, "tender edited", usr.Username, before)
+	})
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// RollbackTender restores the content of an earlier version of the tender as
+// a new version on behalf of a member allowed to edit the organization's
+// tenders. Rolling back to the current version changes nothing.
+func (s *TenderService) RollbackTender(ctx context.Context, tenderId, username string, version int) (tender.TenderResponse, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	usr, err := authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.EditTender)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	if version <= 0 || version > int(ten.Version) {
+		return tender.TenderResponse{}, fmt.Errorf("%w: the tender has versions 1 to %d", storage.ErrBadRequest, ten.Version)
+	}
+	if version == int(ten.Version) {
+		return toTenderResponse(ten), nil
+	}
+
+	var resp tender.TenderResponse
+	err = atomically(s.repo, func(repo TenderRepository) error {
+		before, err := snapshot(repo, auditdomain.Tender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		resp, err = repo.RollbackTender(tenderId, version)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, repo, auditdomain.Tender, tenderId, fmt.Sprintf("tender rolled back to version %d", version), usr.Username, before)
+	})
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// ReadMyTenders lists the tenders created by the user, limited to the
+// selected organization when organizationId is set.
+func (s *TenderService) ReadMyTenders(username, organizationId string, limit, offset int) ([]tender.TenderResponse, error) {
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	if organizationId != "" {
+		err = requireMember(s.repo, organizationId, usr.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.ListCreatorTenders(usr.Username, organizationId, limit, offset)
+}
+
+// ReadBudgetReport compares the price of every bid on the tender with its
+// budget. It is meant for reviewers, so only members allowed to view the
+// organization's bids may read it.
+func (s *TenderService) ReadBudgetReport(tenderId, username string) (tender.BudgetReport, error) {
+	const op = "service.TenderService.ReadBudgetReport"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.BudgetReport{}, err
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.ViewBids)
+	if err != nil {
+		return tender.BudgetReport{}, err
+	}
+
+	lines, err := s.repo.ListBudgetLines(tenderId)
+	if err != nil {
+		return tender.BudgetReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, line := range lines {
+		if line.Price == nil || ten.Budget.Max == nil {
+			continue
+		}
+		delta := *line.Price - *ten.Budget.Max
+		lines[i].DeltaToMax = &delta
+	}
+
+	return tender.BudgetReport{TenderId: ten.Id, Budget: ten.Budget, Bids: lines}, nil
+}
+
+// ReadCommitteeReport collects the bids, feedback and votes of a tender for
+// the procurement committee. Only responsibles of the tender's organization
+// may read it.
+func (s *TenderService) ReadCommitteeReport(tenderId, username string) (tender.CommitteeReport, error) {
+	const op = "service.TenderService.ReadCommitteeReport"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.CommitteeReport{}, err
+	}
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return tender.CommitteeReport{}, storage.ErrUserNotFound
+	}
+
+	ok, err := s.repo.IsOrganizationResponsible(ten.OrganizationId, usr.Id)
+	if err != nil {
+		return tender.CommitteeReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return tender.CommitteeReport{}, fmt.Errorf("%w: only responsibles of the organization may read the committee report", storage.ErrForbidden)
+	}
+
+	report := tender.CommitteeReport{Tender: ten}
+	report.Bids, err = s.repo.ListCommitteeBids(tenderId)
+	if err != nil {
+		return tender.CommitteeReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	report.Feedback, err = s.repo.ListCommitteeFeedback(tenderId)
+	if err != nil {
+		return tender.CommitteeReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	report.Votes, err = s.repo.ListCommitteeVotes(tenderId)
+	if err != nil {
+		return tender.CommitteeReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return report, nil
+}
+
+// CloneTender creates a fresh tender in the Created status from an existing
+// one, copying its description, criteria, lots, auction settings and budget.
+// A deadline keeps its distance from the creation time. Only members allowed
+// to create tenders for the organization may clone its tenders.
+func (s *TenderService) CloneTender(ctx context.Context, tenderId, username, name string) (tender.TenderResponse, error) {
+	const op = "service.TenderService.CloneTender"
+
+	ten, err := s.repo.GetTender(tenderId)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	_, err = authorize(s.repo, s.authorizer, ten.OrganizationId, username, authz.CreateTender)
+	if err != nil {
+		return tender.TenderResponse{}, err
+	}
+
+	lots, err := s.repo.ListLots(tenderId)
+	if err != nil {
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	req := tender.TenderRequest{
+		Name:             ten.Name,
+		Description:      ten.Description,
+		ServiceType:      ten.ServiceType,
+		OrganizationId:   ten.OrganizationId,
+		CreatorUsername:  username,
+		Criteria:         ten.Criteria,
+		Type:             ten.Type,
+		BudgetMin:        ten.Budget.Min,
+		BudgetMax:        ten.Budget.Max,
+		Currency:         ten.Budget.Currency,
+		BudgetVisibility: ten.Budget.Visibility,
+		BudgetPolicy:     ten.Budget.Policy,
+	}
+	if name != "" {
+		req.Name = name
+	}
+
+	if ten.Deadline != nil {
+		deadline := time.Now().UTC().Add(ten.Deadline.Sub(ten.CreatedAt))
+		req.Deadline = &deadline
+	}
+
+	for _, l := range lots {
+		req.Lots = append(req.Lots, lot.LotRequest{
+			Name:        l.Name,
+			Description: l.Description,
+			ServiceType: l.ServiceType,
+			Budget:      l.Budget,
+		})
+	}
+
+	if ten.Type == auctiondomain.ReverseAuction {
+		a, err := s.repo.GetAuction(tenderId)
+		if err != nil {
+			return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		}
+		req.Auction = &a.Settings
+	}
+
+	return createTender(ctx, s.repo, s.authorizer, req, "tender cloned from "+tenderId)
+}
+
+func toTenderResponse(ten tender.Tender) tender.TenderResponse {
+	return tender.TenderResponse{
+		Id:             ten.Id,
+		Name:           ten.Name,
+		Description:    ten.Description,
+		ServiceType:    ten.ServiceType,
+		Status:         ten.Status,
+		OrganizationId: ten.OrganizationId,
+		Type:           ten.Type,
+		Version:        ten.Version,
+		CreatedAt:      ten.CreatedAt,
+		Deadline:       ten.Deadline,
+		PublishAt:      ten.PublishAt,
+		Criteria:       ten.Criteria,
+	}
+}
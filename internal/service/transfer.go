@@ -0,0 +1,817 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"tender_system/internal/authz"
+	auditdomain "tender_system/internal/domain/audit"
+	biddomain "tender_system/internal/domain/bid"
+	conflictdomain "tender_system/internal/domain/conflict"
+	tenderdomain "tender_system/internal/domain/tender"
+	transferlib "tender_system/internal/lib/transfer"
+	"tender_system/internal/models/auction"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/conflict"
+	"tender_system/internal/models/lot"
+	"tender_system/internal/models/tender"
+	"tender_system/internal/models/transfer"
+	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// importBatch is the number of rows copied into the database at once.
+	importBatch = 500
+	// maxRowErrors stops an import early once this many rows are invalid.
+	maxRowErrors = 100
+)
+
+// errRejected aborts the import transaction once a row turned out invalid.
+var errRejected = errors.New("import rejected")
+
+// TransferStore is the bulk data access of the TransferService. It appends
+// the audit entries of imported rows too, in the transaction of the import.
+type TransferStore interface {
+	auditLog
+
+	EachTender(f transfer.TenderFilter, fn func(transfer.Tender) error) error
+	EachBid(f transfer.BidFilter, fn func(transfer.Bid) error) error
+	ExistingTenders(ids []string) ([]string, error)
+	ExistingBids(ids []string) ([]string, error)
+	ImportTenders(fill func(copy func([]transfer.Tender) error) error) error
+	ImportBids(fill func(copy func([]transfer.Bid) error) error) error
+	RecordConflicts(entityType, entityId, organizationId, actor string, findings []conflict.Finding) error
+}
+
+// TransferRepository is the data access of the TransferService.
+type TransferRepository interface {
+	conflictRuleReader
+	organizationReader
+
+	FetchUser(username string) (user.User, error)
+	GetTender(tenderId string) (tender.Tender, error)
+	ListLots(tenderId string) ([]lot.Lot, error)
+	GetAuction(tenderId string) (auction.Auction, error)
+}
+
+// TransferService exports and imports tenders and bids in bulk. Imports are
+// all or nothing: every row is validated and a single invalid one rejects
+// the whole file. Imported rows keep the status and version they were
+// exported with, so that historical data moves over as it is; what a status
+// implies, such as the decision on a bid, needs the matching permission.
+type TransferService struct {
+	repo       TransferRepository
+	authorizer *authz.Authorizer
+	store      TransferStore
+	validate   *validator.Validate
+}
+
+func NewTransferService(repo TransferRepository, authorizer *authz.Authorizer, store TransferStore) *TransferService {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+	return &TransferService{repo: repo, authorizer: authorizer, store: store, validate: validate}
+}
+
+// ExportTenders writes the tenders the user may view. When organizationId is
+// set only that organization's tenders are exported.
+func (s *TransferService) ExportTenders(username, organizationId string, f transfer.TenderFilter, format transferlib.Format, w io.Writer) error {
+	const op = "service.TransferService.ExportTenders"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return storage.ErrUserNotFound
+	}
+
+	if organizationId != "" {
+		err = s.authorizer.Require(organizationId, usr.Id, authz.ViewTender)
+		if err != nil {
+			return err
+		}
+		f.OrganizationIds = []string{organizationId}
+	} else {
+		f.OrganizationIds, err = s.permittedOrganizations(usr, authz.ViewTender)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	writer := transferlib.NewWriter(w, format, tenderCodec)
+	err = s.store.EachTender(f, writer.Write)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return writer.Flush()
+}
+
+// ExportBids writes the bids on tenders of organizations where the user may
+// view bids together with the bids the user may act as author of. When
+// organizationId is set only the bids visible while acting for that
+// organization are exported.
+func (s *TransferService) ExportBids(username, organizationId string, f transfer.BidFilter, format transferlib.Format, w io.Writer) error {
+	const op = "service.TransferService.ExportBids"
+
+	if f.TenderId != "" {
+		_, err := s.repo.GetTender(f.TenderId)
+		if err != nil {
+			return err
+		}
+	}
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return storage.ErrUserNotFound
+	}
+
+	if organizationId != "" {
+		viewer, err := s.authorizer.Can(organizationId, usr.Id, authz.ViewBids)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		bidder, err := s.authorizer.Can(organizationId, usr.Id, authz.SubmitBid)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !viewer && !bidder {
+			return fmt.Errorf("%w: may neither view nor submit bids for organization %s", storage.ErrForbidden, organizationId)
+		}
+		if viewer {
+			f.OrganizationIds = []string{organizationId}
+		}
+		if bidder {
+			f.AuthorIds = []string{organizationId}
+		}
+	} else {
+		f.OrganizationIds, err = s.permittedOrganizations(usr, authz.ViewBids)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		f.AuthorIds, err = actingAuthors(s.repo, s.authorizer, usr, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	writer := transferlib.NewWriter(w, format, bidCodec)
+	err = s.store.EachBid(f, writer.Write)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return writer.Flush()
+}
+
+// ImportTenders creates the tenders read from r. The user needs the right to
+// create tenders in every organization named, and to publish them for
+// tenders past Created. The creators have to belong to the organization.
+// Every imported tender gets its own audit entry once the import is stored.
+func (s *TransferService) ImportTenders(ctx context.Context, username string, format transferlib.Format, r io.Reader) (transfer.Report, error) {
+	const op = "service.TransferService.ImportTenders"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return transfer.Report{}, storage.ErrUserNotFound
+	}
+
+	reader, err := transferlib.NewReader(r, format, tenderCodec)
+	if err != nil {
+		return transfer.Report{}, fmt.Errorf("%w: %v", storage.ErrBadRequest, err)
+	}
+
+	imp := newImport()
+	now := time.Now().UTC()
+	// creators holds the organizations of every creator named, nil for
+	// unknown users.
+	creators := make(map[string][]string)
+
+	check := func(t *transfer.Tender) error {
+		if t.Status == "" {
+			t.Status = tenderdomain.Created
+		}
+		if t.Version == 0 {
+			t.Version = 1
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		if t.CreatorUsername == "" {
+			t.CreatorUsername = usr.Username
+		}
+		err := s.validate.Struct(t)
+		if err != nil {
+			return rowError(describe(err))
+		}
+
+		err = imp.require(s.authorizer, t.OrganizationId, usr.Id, authz.CreateTender)
+		if err != nil {
+			return err
+		}
+		if t.Status != tenderdomain.Created {
+			err = imp.require(s.authorizer, t.OrganizationId, usr.Id, authz.PublishTender)
+			if err != nil {
+				return err
+			}
+		}
+
+		organizations, ok := creators[t.CreatorUsername]
+		if !ok {
+			organizations, err = s.creatorOrganizations(t.CreatorUsername)
+			if err != nil {
+				return err
+			}
+			creators[t.CreatorUsername] = organizations
+		}
+		if organizations == nil {
+			return rowError(fmt.Sprintf("creator %s not found", t.CreatorUsername))
+		}
+		if !slices.Contains(organizations, t.OrganizationId) {
+			return rowError(fmt.Sprintf("creator %s does not belong to organization %s", t.CreatorUsername, t.OrganizationId))
+		}
+
+		return imp.claim(t.Id)
+	}
+
+	err = atomically(s.store, func(store TransferStore) error {
+		var imported []string
+		err := store.ImportTenders(func(copy func([]transfer.Tender) error) error {
+			return runImport(imp, reader, check, func(t transfer.Tender) string { return t.Id }, store.ExistingTenders, func(batch []transfer.Tender) error {
+				err := copy(batch)
+				if err != nil {
+					return err
+				}
+				for _, t := range batch {
+					imported = append(imported, t.Id)
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+		return recordImports(ctx, store, auditdomain.Tender, imported, "tender imported", usr.Username)
+	})
+	return imp.finish(op, err)
+}
+
+// ImportBids creates the bids read from r under the rules that apply to a
+// single new bid: the user has to be allowed to act as the author, the price
+// has to fit the budget and no conflict rule may block the bid. Open bids
+// need a tender accepting bids, withdrawn and decided ones a tender that was
+// published, and decided ones also the right to vote on the tender's bids.
+// Bids on tenders with lots or priced bids on auction tenders cannot be
+// imported, as the file carries neither lots nor auction state. Every
+// imported bid gets its own audit entry once the import is stored.
+func (s *TransferService) ImportBids(ctx context.Context, username string, format transferlib.Format, r io.Reader) (transfer.Report, error) {
+	const op = "service.TransferService.ImportBids"
+
+	usr, err := s.repo.FetchUser(username)
+	if err != nil {
+		return transfer.Report{}, storage.ErrUserNotFound
+	}
+
+	authors, err := actingAuthors(s.repo, s.authorizer, usr, "")
+	if err != nil {
+		return transfer.Report{}, err
+	}
+	// A user bids in their own name only while belonging to an organization.
+	if len(authors) == 1 {
+		organizations, err := s.repo.ReadUserOrganizations(usr.Id)
+		if err != nil {
+			return transfer.Report{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(organizations) == 0 {
+			authors = nil
+		}
+	}
+
+	reader, err := transferlib.NewReader(r, format, bidCodec)
+	if err != nil {
+		return transfer.Report{}, fmt.Errorf("%w: %v", storage.ErrBadRequest, err)
+	}
+
+	imp := newImport()
+	now := time.Now().UTC()
+	targets := make(map[string]*bidTarget)
+
+	check := func(b *transfer.Bid) error {
+		b.Status = biddomain.Normalize(b.Status)
+		if b.Status == "" {
+			b.Status = biddomain.Draft
+		}
+		if b.Version == 0 {
+			b.Version = 1
+		}
+		if b.CreatedAt.IsZero() {
+			b.CreatedAt = now
+		}
+		err := s.validate.Struct(b)
+		if err != nil {
+			return rowError(describe(err))
+		}
+
+		// Users bid in their own name, organizations need the submit right.
+		if (b.AuthorType == "User") != (b.AuthorId == usr.Id) || !slices.Contains(authors, b.AuthorId) {
+			return rowError(fmt.Sprintf("may not bid as %s %s", strings.ToLower(b.AuthorType), b.AuthorId))
+		}
+
+		target, ok := targets[b.TenderId]
+		if !ok {
+			target, err = s.bidTarget(b.TenderId)
+			if err != nil {
+				return err
+			}
+			targets[b.TenderId] = target
+		}
+		if target == nil {
+			return rowError(fmt.Sprintf("tender %s not found", b.TenderId))
+		}
+		switch b.Status {
+		case biddomain.Draft, biddomain.Submitted:
+			if !tenderdomain.AcceptsBids(target.tender.Status) {
+				return rowError(fmt.Sprintf("tender %s is %s", b.TenderId, strings.ToLower(target.tender.Status)))
+			}
+		default:
+			if target.tender.Status == tenderdomain.Created {
+				return rowError(fmt.Sprintf("tender %s was never published, its bids cannot be %s", b.TenderId, strings.ToLower(b.Status)))
+			}
+		}
+		if b.Status == biddomain.Approved || b.Status == biddomain.Rejected {
+			err = imp.require(s.authorizer, target.tender.OrganizationId, usr.Id, authz.Vote)
+			if err != nil {
+				return err
+			}
+		}
+		if target.lots {
+			return rowError(fmt.Sprintf("tender %s has lots, bids on it have to name them", b.TenderId))
+		}
+
+		if b.Price != nil {
+			if target.auction {
+				return rowError(fmt.Sprintf("tender %s runs an auction, priced bids have to be placed in it", b.TenderId))
+			}
+			outOfBudget, refusal := tenderdomain.CheckPrice(target.tender.Budget, *b.Price)
+			if refusal != "" {
+				return rowError(refusal)
+			}
+			b.OutOfBudget = outOfBudget
+		}
+
+		findings, blocked, err := bidConflicts(s.repo, target.tender, b.AuthorType, b.AuthorId)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return rowError(conflictdomain.Describe(findings))
+		}
+		b.Conflicts = findings
+
+		return imp.claim(b.Id)
+	}
+
+	// The bids and the conflicts that did not block them are recorded once
+	// the bids are stored, when every bid has its id.
+	err = atomically(s.store, func(store TransferStore) error {
+		var (
+			imported   []string
+			conflicted []transfer.Bid
+		)
+		err := store.ImportBids(func(copy func([]transfer.Bid) error) error {
+			return runImport(imp, reader, check, func(b transfer.Bid) string { return b.Id }, store.ExistingBids, func(batch []transfer.Bid) error {
+				err := copy(batch)
+				if err != nil {
+					return err
+				}
+				for _, b := range batch {
+					imported = append(imported, b.Id)
+					if len(b.Conflicts) > 0 {
+						conflicted = append(conflicted, b)
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordImports(ctx, store, auditdomain.Bid, imported, "bid imported", usr.Username)
+		if err != nil {
+			return err
+		}
+		for _, b := range conflicted {
+			err = store.RecordConflicts(auditdomain.Bid, b.Id, targets[b.TenderId].tender.OrganizationId, usr.Username, b.Conflicts)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return imp.finish(op, err)
+}
+
+// recordImports appends the audit entry of every imported entity.
+func recordImports(ctx context.Context, log auditLog, entityType string, ids []string, action, actor string) error {
+	for _, id := range ids {
+		err := recordChange(ctx, log, entityType, id, action, actor, audit.Subject{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// creatorOrganizations lists the organizations of the user named as a
+// tender's creator, returning nil when there is no such user.
+func (s *TransferService) creatorOrganizations(username string) ([]string, error) {
+	creator, err := s.repo.FetchUser(username)
+	if err != nil {
+		return nil, nil
+	}
+
+	organizations, err := s.repo.ReadUserOrganizations(creator.Id)
+	if err != nil {
+		return nil, err
+	}
+	if organizations == nil {
+		organizations = []string{}
+	}
+	return organizations, nil
+}
+
+// bidTarget is what an import needs to know about the tender bids are
+// placed on.
+type bidTarget struct {
+	tender  tender.Tender
+	lots    bool
+	auction bool
+}
+
+// bidTarget looks the tender up, returning nil when it does not exist.
+func (s *TransferService) bidTarget(tenderId string) (*bidTarget, error) {
+	ten, err := s.repo.GetTender(tenderId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := s.repo.ListLots(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.GetAuction(tenderId)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	return &bidTarget{tender: ten, lots: len(lots) > 0, auction: err == nil}, nil
+}
+
+// permittedOrganizations lists the organizations where the user holds perm.
+func (s *TransferService) permittedOrganizations(usr user.User, perm authz.Permission) ([]string, error) {
+	organizations, err := s.repo.ReadUserOrganizations(usr.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(organizations))
+	for _, orgId := range organizations {
+		ok, err := s.authorizer.Can(orgId, usr.Id, perm)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, orgId)
+		}
+	}
+
+	return result, nil
+}
+
+// rowError is a reason to reject a single row.
+type rowError string
+
+func (e rowError) Error() string {
+	return string(e)
+}
+
+// importState collects the outcome of an import while it runs.
+type importState struct {
+	report  transfer.Report
+	allowed map[string]error
+	ids     map[string]bool
+}
+
+func newImport() *importState {
+	return &importState{
+		report:  transfer.Report{Errors: []transfer.RowError{}},
+		allowed: make(map[string]error),
+		ids:     make(map[string]bool),
+	}
+}
+
+// reject records an invalid row and reports whether the import may go on.
+func (imp *importState) reject(row int, reason string) bool {
+	imp.report.Errors = append(imp.report.Errors, transfer.RowError{Row: row, Reason: reason})
+	if len(imp.report.Errors) >= maxRowErrors {
+		imp.report.Reason = fmt.Sprintf("stopped after %d invalid rows", maxRowErrors)
+		return false
+	}
+	return true
+}
+
+// require checks a permission once per organization.
+func (imp *importState) require(authorizer *authz.Authorizer, organizationId, userId string, perm authz.Permission) error {
+	key := organizationId + "/" + string(perm)
+	err, ok := imp.allowed[key]
+	if !ok {
+		err = authorizer.Require(organizationId, userId, perm)
+		imp.allowed[key] = err
+	}
+	if errors.Is(err, storage.ErrForbidden) {
+		return rowError(fmt.Sprintf("missing permission %s in organization %s", perm, organizationId))
+	}
+	return err
+}
+
+// claim rejects ids given to more than one row of the file.
+func (imp *importState) claim(id string) error {
+	if id == "" {
+		return nil
+	}
+	if imp.ids[id] {
+		return rowError(fmt.Sprintf("id %s appears more than once", id))
+	}
+	imp.ids[id] = true
+	return nil
+}
+
+func (imp *importState) finish(op string, err error) (transfer.Report, error) {
+	if errors.Is(err, errRejected) {
+		imp.report.Imported = 0
+		if imp.report.Reason == "" {
+			imp.report.Reason = "the file contains invalid rows, nothing was imported"
+		}
+		return imp.report, fmt.Errorf("%w: %s", storage.ErrBadRequest, imp.report.Reason)
+	}
+	if err != nil {
+		return transfer.Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return imp.report, nil
+}
+
+// runImport reads every row, checks it and copies the valid rows in batches.
+// Once a row is rejected nothing more is copied and errRejected rolls back
+// what was.
+func runImport[T any](imp *importState, reader *transferlib.Reader[T], check func(*T) error, id func(T) string,
+	existing func([]string) ([]string, error), copy func([]T) error) error {
+
+	batch := make([]T, 0, importBatch)
+	rows := make([]int, 0, importBatch)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		defer func() {
+			batch, rows = batch[:0], rows[:0]
+		}()
+
+		ids := make([]string, 0, len(batch))
+		for _, v := range batch {
+			if id(v) != "" {
+				ids = append(ids, id(v))
+			}
+		}
+		if len(ids) > 0 {
+			taken, err := existing(ids)
+			if err != nil {
+				return err
+			}
+			for i, v := range batch {
+				for _, t := range taken {
+					if id(v) == t && !imp.reject(rows[i], fmt.Sprintf("id %s already exists", t)) {
+						return errRejected
+					}
+				}
+			}
+		}
+
+		if len(imp.report.Errors) > 0 {
+			return nil
+		}
+		err := copy(batch)
+		if err != nil {
+			return err
+		}
+		imp.report.Imported += len(batch)
+		return nil
+	}
+
+	for {
+		v, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var malformed *transferlib.RowError
+		if errors.As(err, &malformed) {
+			if !imp.reject(malformed.Row, malformed.Err.Error()) {
+				return errRejected
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = check(&v)
+		var invalid rowError
+		if errors.As(err, &invalid) {
+			if !imp.reject(reader.Row(), invalid.Error()) {
+				return errRejected
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, v)
+		rows = append(rows, reader.Row())
+		if len(batch) == importBatch {
+			err = flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := flush()
+	if err != nil {
+		return err
+	}
+	if len(imp.report.Errors) > 0 {
+		return errRejected
+	}
+	return nil
+}
+
+// describe turns validation errors into a reason naming the JSON fields.
+func describe(err error) string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err.Error()
+	}
+
+	reasons := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		switch fe.Tag() {
+		case "required":
+			reasons = append(reasons, fe.Field()+" is required")
+		case "eq":
+			reasons = append(reasons, fmt.Sprintf("%s must be %s", fe.Field(), fe.Param()))
+		case "oneof":
+			reasons = append(reasons, fmt.Sprintf("%s must be one of %s", fe.Field(), fe.Param()))
+		case "uuid":
+			reasons = append(reasons, fe.Field()+" must be a UUID")
+		case "max":
+			reasons = append(reasons, fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param()))
+		case "gt":
+			reasons = append(reasons, fmt.Sprintf("%s must be above %s", fe.Field(), fe.Param()))
+		case "gte":
+			reasons = append(reasons, fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param()))
+		default:
+			reasons = append(reasons, fe.Field()+" is invalid")
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
+
+var tenderCodec = transferlib.Codec[transfer.Tender]{
+	Columns: []string{"id", "name", "description", "serviceType", "status", "organizationId", "creatorUsername", "version", "createdAt", "deadline"},
+	Values: func(t transfer.Tender) []string {
+		return []string{t.Id, t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationId, t.CreatorUsername,
+			strconv.Itoa(int(t.Version)), formatTime(&t.CreatedAt), formatTime(t.Deadline)}
+	},
+	Parse: func(cells map[string]string) (transfer.Tender, error) {
+		t := transfer.Tender{
+			Id:              cells["id"],
+			Name:            cells["name"],
+			Description:     cells["description"],
+			ServiceType:     cells["serviceType"],
+			Status:          cells["status"],
+			OrganizationId:  cells["organizationId"],
+			CreatorUsername: cells["creatorUsername"],
+		}
+
+		version, err := parseInt("version", cells["version"])
+		if err != nil {
+			return t, err
+		}
+		t.Version = int32(version)
+
+		createdAt, err := parseTime("createdAt", cells["createdAt"])
+		if err != nil {
+			return t, err
+		}
+		if createdAt != nil {
+			t.CreatedAt = *createdAt
+		}
+
+		t.Deadline, err = parseTime("deadline", cells["deadline"])
+		return t, err
+	},
+}
+
+var bidCodec = transferlib.Codec[transfer.Bid]{
+	Columns: []string{"id", "name", "description", "tenderId", "authorType", "authorId", "status", "price", "version", "createdAt"},
+	Values: func(b transfer.Bid) []string {
+		price := ""
+		if b.Price != nil {
+			price = strconv.FormatFloat(*b.Price, 'f', -1, 64)
+		}
+		return []string{b.Id, b.Name, b.Description, b.TenderId, b.AuthorType, b.AuthorId, b.Status,
+			price, strconv.Itoa(b.Version), formatTime(&b.CreatedAt)}
+	},
+	Parse: func(cells map[string]string) (transfer.Bid, error) {
+		b := transfer.Bid{
+			Id:          cells["id"],
+			Name:        cells["name"],
+			Description: cells["description"],
+			TenderId:    cells["tenderId"],
+			AuthorType:  cells["authorType"],
+			AuthorId:    cells["authorId"],
+			Status:      cells["status"],
+		}
+
+		if s := strings.TrimSpace(cells["price"]); s != "" {
+			price, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return b, fmt.Errorf("price %q is not a number", s)
+			}
+			b.Price = &price
+		}
+
+		var err error
+		b.Version, err = parseInt("version", cells["version"])
+		if err != nil {
+			return b, err
+		}
+
+		createdAt, err := parseTime("createdAt", cells["createdAt"])
+		if err != nil {
+			return b, err
+		}
+		if createdAt != nil {
+			b.CreatedAt = *createdAt
+		}
+
+		return b, nil
+	},
+}
+
+// Empty cells stand for absent values, which get their defaults on import.
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(column, s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q is not an RFC 3339 time", column, s)
+	}
+	return &t, nil
+}
+
+func parseInt(column, s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not an integer", column, s)
+	}
+	return n, nil
+}
@@ -0,0 +1,239 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"tender_system/internal/storage/blob"
+	"testing"
+	"time"
+)
+
+const (
+	accessKey = "test-access"
+	secretKey = "test-secret"
+	region    = "eu-central-1"
+	bucket    = "attachments"
+)
+
+// fakeS3 is an S3 stand-in serving path-style object requests from memory.
+// It refuses requests whose Signature Version 4 does not match the secret.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+type object struct {
+	body        []byte
+	contentType string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	t.Helper()
+
+	s := &fakeS3{objects: make(map[string]object)}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !validSignature(r) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(body)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		s.objects[r.URL.Path] = object{body: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		obj, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.body)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// validSignature recomputes the Signature Version 4 of an unsigned payload
+// request the way S3 does.
+func validSignature(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	amzDate := r.Header.Get("X-Amz-Date")
+	if r.Header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" || len(amzDate) != len("20060102T150405Z") {
+		return false
+	}
+	date := amzDate[:8]
+	scope := date + "/" + region + "/s3/aws4_request"
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		"host:" + r.Host + "\nx-amz-content-sha256:UNSIGNED-PAYLOAD\nx-amz-date:" + amzDate + "\n",
+		"host;x-amz-content-sha256;x-amz-date",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+
+	want := "AWS4-HMAC-SHA256 Credential=" + accessKey + "/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=" + hex.EncodeToString(key)
+	return auth == want
+}
+
+func newStore(t *testing.T, endpoint, secret string) *blob.S3Store {
+	t.Helper()
+
+	store, err := blob.NewS3(blob.S3Config{
+		Endpoint:  endpoint,
+		Bucket:    bucket,
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestS3Store(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	store := newStore(t, srv.URL, secretKey)
+	ctx := context.Background()
+	key := "tender/0b6f/contract final.pdf"
+	content := []byte("%PDF-1.7 signed contract")
+
+	err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf")
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	obj, ok := fake.objects["/"+bucket+"/"+key]
+	if !ok {
+		t.Fatalf("object not stored path-style, have %v", fake.objects)
+	}
+	if obj.contentType != "application/pdf" {
+		t.Fatalf("content type %q, want application/pdf", obj.contentType)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("read %q, want %q", got, content)
+	}
+
+	err = store.Delete(ctx, key)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	_, err = store.Get(ctx, key)
+	if !errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("get after delete: %v, want %v", err, blob.ErrNotFound)
+	}
+
+	// Deleting is idempotent, so cleaning up after a failed upload may
+	// repeat it.
+	err = store.Delete(ctx, key)
+	if err != nil {
+		t.Fatalf("second delete: %v", err)
+	}
+}
+
+func TestS3StoreEndpointPath(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	store := newStore(t, srv.URL+"/storage/", secretKey)
+
+	err := store.Put(context.Background(), "bid/1/offer.txt", strings.NewReader("offer"), 5, "text/plain")
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, ok := fake.objects["/storage/"+bucket+"/bid/1/offer.txt"]; !ok {
+		t.Fatalf("object not stored under the endpoint path, have %v", fake.objects)
+	}
+}
+
+func TestS3StoreErrors(t *testing.T) {
+	_, srv := newFakeS3(t)
+	store := newStore(t, srv.URL, "wrong-secret")
+	ctx := context.Background()
+
+	err := store.Put(ctx, "tender/1/a.txt", strings.NewReader("a"), 1, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("put with a wrong secret: %v, want a 403", err)
+	}
+
+	_, err = store.Get(ctx, "tender/1/a.txt")
+	if err == nil || errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("get with a wrong secret: %v, want an error other than %v", err, blob.ErrNotFound)
+	}
+
+	err = store.Delete(ctx, "tender/1/a.txt")
+	if err == nil {
+		t.Fatal("delete with a wrong secret succeeded")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	_, err = newStore(t, srv.URL, secretKey).Get(ctx, "tender/1/a.txt")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("get after the deadline: %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNewS3(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  blob.S3Config
+	}{
+		{name: "relative endpoint", cfg: blob.S3Config{Endpoint: "minio:9000", Bucket: bucket}},
+		{name: "no bucket", cfg: blob.S3Config{Endpoint: "http://minio:9000"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := blob.NewS3(c.cfg)
+			if err == nil {
+				t.Fatal("invalid config accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"tender_system/internal/models/attachment"
+)
+
+const (
+	AttachmentTender = attachment.Tender
+	AttachmentBid    = attachment.Bid
+)
+
+// AddAttachment archives the current version of the tender or bid, attaches
+// the file and bumps the version in one transaction.
+func (s *Storage) AddAttachment(att attachment.Attachment) (attachment.Attachment, error) {
+	const op = "storage.postgres.AddAttachment"
+
+	err := s.inTx(func(tx *Storage) error {
+		err := tx.archiveEntity(att.EntityType, att.EntityId)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.db.Prepare(`
+		INSERT INTO attachment(entityType, entityId, fileName, contentType, size, checksum, storageKey, uploadedBy)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, createdAt
+		`)
+		if err != nil {
+			return err
+		}
+
+		err = stmt.QueryRow(
+			att.EntityType,
+			att.EntityId,
+			att.FileName,
+			att.ContentType,
+			att.Size,
+			att.Checksum,
+			att.StorageKey,
+			att.UploadedBy,
+		).Scan(&att.Id, &att.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		return tx.bumpEntityVersion(att.EntityType, att.EntityId)
+	})
+	if err != nil {
+		return attachment.Attachment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return att, nil
+}
+
+func (s *Storage) ListAttachments(entityType, entityId string) ([]attachment.Attachment, error) {
+	const op = "storage.postgres.ListAttachments"
+	result := make([]attachment.Attachment, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, entityType, entityId, fileName, contentType, size, checksum, storageKey, uploadedBy, createdAt
+	FROM attachment
+	WHERE entityType = $1 AND entityId = $2 AND active
+	ORDER BY createdAt
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(entityType, entityId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var att attachment.Attachment
+		err = rows.Scan(
+			&att.Id,
+			&att.EntityType,
+			&att.EntityId,
+			&att.FileName,
+			&att.ContentType,
+			&att.Size,
+			&att.Checksum,
+			&att.StorageKey,
+			&att.UploadedBy,
+			&att.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, att)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) GetAttachment(entityType, entityId, attachmentId string) (attachment.Attachment, error) {
+	const op = "storage.postgres.GetAttachment"
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, entityType, entityId, fileName, contentType, size, checksum, storageKey, uploadedBy, createdAt
+	FROM attachment
+	WHERE entityType = $1 AND entityId = $2 AND id = $3 AND active
+	`)
+	if err != nil {
+		return attachment.Attachment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var att attachment.Attachment
+	err = stmt.QueryRow(entityType, entityId, attachmentId).Scan(
+		&att.Id,
+		&att.EntityType,
+		&att.EntityId,
+		&att.FileName,
+		&att.ContentType,
+		&att.Size,
+		&att.Checksum,
+		&att.StorageKey,
+		&att.UploadedBy,
+		&att.CreatedAt,
+	)
+	if err != nil {
+		return attachment.Attachment{}, ErrNotFound
+	}
+
+	return att, nil
+}
+
+// RemoveAttachment only detaches the file from the current version. The blob
+// and metadata are kept so that rolling back to an older version restores it.
+// Archiving, detaching and bumping the version happen in one transaction.
+func (s *Storage) RemoveAttachment(entityType, entityId, attachmentId string) error {
+	const op = "storage.postgres.RemoveAttachment"
+
+	err := s.inTx(func(tx *Storage) error {
+		err := tx.archiveEntity(entityType, entityId)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.db.Prepare(`
+		UPDATE attachment
+		SET active = FALSE
+		WHERE entityType = $1 AND entityId = $2 AND id = $3 AND active
+		`)
+		if err != nil {
+			return err
+		}
+
+		res, err := stmt.Exec(entityType, entityId, attachmentId)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrNotFound
+		}
+
+		return tx.bumpEntityVersion(entityType, entityId)
+	})
+	if errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// archiveEntity copies the current tender or bid into its history table
+// together with the attachment set and, for tenders, the lots, the same way
+// PatchTender and EditBid do. The entity row stays locked until the
+// transaction ends, so concurrent changes archive one version each.
+func (s *Storage) archiveEntity(entityType, entityId string) error {
+	var query, versionQuery string
+	switch entityType {
+	case AttachmentTender:
+		query = `
+		INSERT INTO tenderHistory(tenderId, name, description, serviceType, status, version)
+		SELECT id, name, description, serviceType, status, version
+		FROM tender
+		WHERE id = $1
+		`
+		versionQuery = `SELECT version FROM tender WHERE id = $1 FOR UPDATE`
+	case AttachmentBid:
+		query = `
+		INSERT INTO bidHistory(bidId, name, description, status, version, price, outOfBudget)
+		SELECT id, name, description, status, version, price, outOfBudget
+		FROM bid
+		WHERE id = $1
+		`
+		versionQuery = `SELECT version FROM bid WHERE id = $1 FOR UPDATE`
+	default:
+		return ErrBadRequest
+	}
+
+	stmt, err := s.db.Prepare(versionQuery)
+	if err != nil {
+		return err
+	}
+
+	var version int
+	err = stmt.QueryRow(entityId).Scan(&version)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	stmt, err = s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(entityId)
+	if err != nil {
+		return err
+	}
+
+	err = s.snapshotAttachments(entityId, version)
+	if err != nil {
+		return err
+	}
+
+	if entityType == AttachmentTender {
+		return s.snapshotLots(entityId, version)
+	}
+	return nil
+}
+
+func (s *Storage) bumpEntityVersion(entityType, entityId string) error {
+	var query string
+	switch entityType {
+	case AttachmentTender:
+		query = `UPDATE tender SET version = version + 1 WHERE id = $1`
+	case AttachmentBid:
+		query = `UPDATE bid SET version = version + 1 WHERE id = $1`
+	default:
+		return ErrBadRequest
+	}
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(entityId)
+	return err
+}
+
+// snapshotAttachments records which attachments belong to the given version
+// of a tender or bid. It must be called next to every history insert.
+func (s *Storage) snapshotAttachments(entityId string, version int) error {
+	stmt, err := s.db.Prepare(`
+	INSERT INTO attachmentHistory(entityId, version, attachmentId)
+	SELECT entityId, $2, id
+	FROM attachment
+	WHERE entityId = $1 AND active
+	ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(entityId, version)
+	return err
+}
+
+// restoreAttachments makes the attachment set of the given historical
+// version the current one.
+func (s *Storage) restoreAttachments(entityId string, version int) error {
+	stmt, err := s.db.Prepare(`
+	UPDATE attachment
+	SET active = id IN (
+		SELECT attachmentId
+		FROM attachmentHistory
+		WHERE entityId = $1 AND version = $2
+	)
+	WHERE entityId = $1
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(entityId, version)
+	return err
+}
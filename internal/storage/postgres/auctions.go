@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	auctiondomain "tender_system/internal/domain/auction"
+	"tender_system/internal/models/auction"
+	"time"
+)
+
+const auctionColumns = `
+	tenderId, startPrice, minDecrement, roundSeconds, maxRounds, extensionSeconds,
+	status, round, roundEndsAt, roundBids, bestPrice, coalesce(bestBidId::text, ''), coalesce(proposedBidId::text, '')
+	`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAuction(row scanner) (auction.Auction, error) {
+	var a auction.Auction
+	err := row.Scan(
+		&a.TenderId,
+		&a.Settings.StartPrice,
+		&a.Settings.MinDecrement,
+		&a.Settings.RoundSeconds,
+		&a.Settings.MaxRounds,
+		&a.Settings.ExtensionSeconds,
+		&a.State.Status,
+		&a.State.Round,
+		&a.State.RoundEndsAt,
+		&a.State.RoundBids,
+		&a.State.BestPrice,
+		&a.State.BestBidId,
+		&a.State.ProposedBidId,
+	)
+	return a, err
+}
+
+func (s *Storage) saveAuction(tenderId string, settings auction.Settings) error {
+	stmt, err := s.db.Prepare(`
+	INSERT INTO auction(tenderId, startPrice, minDecrement, roundSeconds, maxRounds, extensionSeconds, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(
+		tenderId,
+		settings.StartPrice,
+		settings.MinDecrement,
+		settings.RoundSeconds,
+		settings.MaxRounds,
+		settings.ExtensionSeconds,
+		auctiondomain.Pending,
+	)
+	return err
+}
+
+func (s *Storage) GetAuction(tenderId string) (auction.Auction, error) {
+	const op = "storage.postgres.GetAuction"
+
+	stmt, err := s.db.Prepare(`SELECT ` + auctionColumns + ` FROM auction WHERE tenderId = $1`)
+	if err != nil {
+		return auction.Auction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a, err := scanAuction(stmt.QueryRow(tenderId))
+	if err != nil {
+		return auction.Auction{}, ErrNotFound
+	}
+
+	return a, nil
+}
+
+// ListExpiredAuctions returns the running auctions whose current round ended
+// before now.
+func (s *Storage) ListExpiredAuctions(now time.Time) ([]auction.Auction, error) {
+	const op = "storage.postgres.ListExpiredAuctions"
+	result := make([]auction.Auction, 0)
+
+	stmt, err := s.db.Prepare(`SELECT ` + auctionColumns + ` FROM auction WHERE status = $1 AND roundEndsAt <= $2`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(auctiondomain.Running, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanAuction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+// UpdateAuction stores next as the state of the auction, provided nobody
+// changed it since prev was read. Otherwise it returns ErrConflict.
+func (s *Storage) UpdateAuction(tenderId string, prev, next auction.State) error {
+	const op = "storage.postgres.UpdateAuction"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE auction
+	SET status = $2, round = $3, roundEndsAt = $4, roundBids = $5, bestPrice = $6,
+		bestBidId = NULLIF($7, '')::uuid, proposedBidId = NULLIF($8, '')::uuid
+	WHERE tenderId = $1 AND status = $9 AND round = $10 AND roundBids = $11
+		AND bestPrice IS NOT DISTINCT FROM $12::numeric
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(
+		tenderId,
+		next.Status,
+		next.Round,
+		next.RoundEndsAt,
+		next.RoundBids,
+		next.BestPrice,
+		next.BestBidId,
+		next.ProposedBidId,
+		prev.Status,
+		prev.Round,
+		prev.RoundBids,
+		prev.BestPrice,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: the auction has changed, try again", ErrConflict)
+	}
+
+	return nil
+}
+
+// startAuction opens the first round of the tender's auction, if it has one
+// that has not started yet.
+func (s *Storage) startAuction(tenderId string) error {
+	a, err := s.GetAuction(tenderId)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	next := auctiondomain.Start(a.Settings, a.State, time.Now().UTC())
+	if next.Status == a.State.Status {
+		return nil
+	}
+
+	return s.UpdateAuction(tenderId, a.State, next)
+}
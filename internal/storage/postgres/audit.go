@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	auditdomain "tender_system/internal/domain/audit"
+	"tender_system/internal/models/audit"
+	"time"
+)
+
+// auditLock is the advisory lock key serializing appends to the audit chain.
+const auditLock = 7_250_001
+
+const auditColumns = `
+	id, createdAt, entityType, entityId, action, actor, coalesce(organizationId, ''),
+	coalesce(ip, ''), coalesce(requestId, ''), before, after, prevHash, hash
+	`
+
+func scanAuditEntry(row scanner) (audit.Entry, error) {
+	var e audit.Entry
+	var before, after sql.NullString
+	err := row.Scan(
+		&e.Id,
+		&e.CreatedAt,
+		&e.EntityType,
+		&e.EntityId,
+		&e.Action,
+		&e.Actor,
+		&e.OrganizationId,
+		&e.IP,
+		&e.RequestId,
+		&before,
+		&after,
+		&e.PrevHash,
+		&e.Hash,
+	)
+	if before.Valid {
+		e.Before = json.RawMessage(before.String)
+	}
+	if after.Valid {
+		e.After = json.RawMessage(after.String)
+	}
+	return e, err
+}
+
+func nullableJSON(raw json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+	return string(raw)
+}
+
+// AppendAudit adds the entry to the end of the audit chain.
+func (s *Storage) AppendAudit(e audit.Entry) (audit.Entry, error) {
+	const op = "storage.postgres.AppendAudit"
+
+	err := s.inTx(func(tx *Storage) error {
+		_, err := tx.db.Exec(`SELECT pg_advisory_xact_lock($1)`, auditLock)
+		if err != nil {
+			return err
+		}
+
+		var last audit.Entry
+		err = tx.db.QueryRow(`SELECT id, hash FROM audit ORDER BY id DESC LIMIT 1`).Scan(&last.Id, &last.Hash)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		e.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		e = auditdomain.Chain(last, e)
+
+		_, err = tx.db.Exec(`
+		INSERT INTO audit(id, createdAt, entityType, entityId, action, actor, organizationId, ip, requestId, before, after, prevHash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12, $13)
+		`,
+			e.Id,
+			e.CreatedAt,
+			e.EntityType,
+			e.EntityId,
+			e.Action,
+			e.Actor,
+			e.OrganizationId,
+			e.IP,
+			e.RequestId,
+			nullableJSON(e.Before),
+			nullableJSON(e.After),
+			e.PrevHash,
+			e.Hash,
+		)
+		return err
+	})
+	if err != nil {
+		return audit.Entry{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return e, nil
+}
+
+// ListAudit pages through the audit entries matching the filter, newest
+// first. Empty filter fields match everything.
+func (s *Storage) ListAudit(f audit.Filter) ([]audit.Entry, error) {
+	const op = "storage.postgres.ListAudit"
+	result := make([]audit.Entry, 0)
+
+	stmt, err := s.db.Prepare(`SELECT ` + auditColumns + `
+	FROM audit
+	WHERE ($1 = '' OR organizationId = $1)
+		AND ($2 = '' OR entityType = $2)
+		AND ($3 = '' OR entityId = $3)
+		AND ($4 = '' OR actor = $4)
+		AND ($5::timestamp IS NULL OR createdAt >= $5)
+		AND ($6::timestamp IS NULL OR createdAt < $6)
+	ORDER BY id DESC
+	LIMIT $7
+	OFFSET $8
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(f.OrganizationId, f.EntityType, f.EntityId, f.Actor, utcOrNil(f.From), utcOrNil(f.To), f.Limit, f.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+// ReadAuditChain returns up to limit entries following afterId, oldest first.
+func (s *Storage) ReadAuditChain(afterId int64, limit int) ([]audit.Entry, error) {
+	const op = "storage.postgres.ReadAuditChain"
+	result := make([]audit.Entry, 0)
+
+	stmt, err := s.db.Prepare(`SELECT ` + auditColumns + ` FROM audit WHERE id > $1 ORDER BY id LIMIT $2`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(afterId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+// AuditSubject reads the current state of an audited entity as JSON together
+// with the organization it belongs to. Employees may be given by id or
+// username.
+func (s *Storage) AuditSubject(entityType, entityId string) (audit.Subject, error) {
+	const op = "storage.postgres.AuditSubject"
+
+	var query string
+	switch entityType {
+	case auditdomain.Tender:
+		query = `
+		SELECT t.id::text, t.organizationId::text,
+			to_jsonb(t) || jsonb_build_object(
+				'lots', coalesce((SELECT jsonb_agg(to_jsonb(l) ORDER BY l.createdAt) FROM lot l WHERE l.tenderId = t.id AND l.active), '[]'),
+				'auction', (SELECT to_jsonb(a) FROM auction a WHERE a.tenderId = t.id),
+				'recusals', coalesce((SELECT jsonb_agg(jsonb_build_object('userId', r.userId, 'reason', r.reason) ORDER BY r.createdAt) FROM recusal r WHERE r.tenderId = t.id), '[]'),
+				'recurrence', (SELECT jsonb_build_object('frequency', r.frequency, 'publish', r.publish, 'nextRunAt', r.nextRunAt) FROM recurrence r WHERE r.tenderId = t.id)
+			)
+		FROM tender t
+		WHERE t.id::text = $1
+		`
+	case auditdomain.Bid:
+		query = `
+		SELECT b.id::text, t.organizationId::text,
+			to_jsonb(b) || jsonb_build_object(
+				'feedback', coalesce((SELECT jsonb_agg(to_jsonb(f) ORDER BY f.createdAt) FROM feedback f WHERE f.bidId = b.id), '[]'),
+				'votes', coalesce((SELECT jsonb_agg(jsonb_build_object('username', v.username, 'decision', v.decision, 'lotId', v.lotId)) FROM voted v WHERE v.bidId = b.id), '[]')
+			)
+		FROM bid b
+		JOIN tender t ON t.id = b.tenderId
+		WHERE b.id::text = $1
+		`
+	case auditdomain.Organization:
+		query = `
+		SELECT o.id::text, o.id::text,
+			to_jsonb(o) || jsonb_build_object(
+				'responsibles', coalesce((SELECT jsonb_agg(r.user_id ORDER BY r.user_id) FROM organization_responsible r WHERE r.organization_id = o.id), '[]'),
+				'roles', coalesce((SELECT jsonb_agg(jsonb_build_object('userId', r.user_id, 'role', r.role) ORDER BY r.user_id, r.role) FROM organization_role r WHERE r.organization_id = o.id), '[]'),
+				'conflictRules', coalesce((SELECT jsonb_object_agg(c.rule, c.action) FROM conflictRule c WHERE c.organizationId = o.id), '{}'),
+				'templates', coalesce((SELECT jsonb_agg(jsonb_build_object('id', t.id, 'title', t.title, 'spec', t.spec) ORDER BY t.title) FROM tenderTemplate t WHERE t.organizationId = o.id), '[]')
+			)
+		FROM organization o
+		WHERE o.id::text = $1
+		`
+	case auditdomain.Employee:
+		query = `
+		SELECT e.id::text, '', to_jsonb(e)
+		FROM employee e
+		WHERE e.id::text = $1 OR e.username = $1
+		`
+	default:
+		return audit.Subject{}, ErrBadRequest
+	}
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return audit.Subject{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var subject audit.Subject
+	var state string
+	err = stmt.QueryRow(entityId).Scan(&subject.EntityId, &subject.OrganizationId, &state)
+	if err == sql.ErrNoRows {
+		return audit.Subject{}, ErrNotFound
+	}
+	if err != nil {
+		return audit.Subject{}, fmt.Errorf("%s: %w", op, err)
+	}
+	subject.State = json.RawMessage(state)
+
+	return subject, nil
+}
+
+func utcOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	biddomain "tender_system/internal/domain/bid"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/award"
+	"tender_system/internal/models/bids"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AwardBid records the outcome of an approval quorum in one transaction that
+// holds the tender row lock, so that concurrent votes and status changes of
+// the tender wait for it. guard is handed the locked tender status first and
+// may refuse the award. Then the decision is closed, the bid approved, the
+// tender, or its lot when lotId is not empty, awarded to it and, once no lot
+// is left unawarded, the tender moved to Awarded on behalf of actor.
+func (s *Storage) AwardBid(bid *bids.BidResponse, lotId, actor string, guard func(status string) error) (award.Outcome, error) {
+	const op = "storage.postgres.AwardBid"
+	var result award.Outcome
+
+	err := s.inTx(func(tx *Storage) error {
+		var status string
+		err := tx.db.QueryRow(`SELECT status FROM tender WHERE id = $1 FOR UPDATE`, bid.TenderId).Scan(&status)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		err = guard(status)
+		if err != nil {
+			return err
+		}
+
+		err = tx.CloseDecision(bid.Id, lotId)
+		if err != nil {
+			return err
+		}
+
+		if biddomain.Normalize(bid.Status) != biddomain.Approved {
+			err = tx.DecideBid(bid, biddomain.Approved)
+			if err != nil {
+				return err
+			}
+			result.BidApproved = true
+		}
+
+		err = tx.awardTender(bid.TenderId, lotId, bid.Id)
+		if err != nil {
+			return err
+		}
+
+		if lotId != "" {
+			remaining, err := tx.CountUnawardedLots(bid.TenderId)
+			if err != nil {
+				return err
+			}
+			if remaining > 0 {
+				return nil
+			}
+		}
+
+		err = tx.ChangeTenderStatus(bid.TenderId, tenderdomain.Awarded, actor, fmt.Sprintf("bid %s approved", bid.Id))
+		if err != nil {
+			return err
+		}
+		result.TenderAwarded = true
+		return nil
+	})
+	if err != nil {
+		return award.Outcome{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// awardTender records the winning bid of a tender, or of one of its lots when
+// lotId is not empty, together with everyone who approved it. Competing bids
+// that are still open and have nothing left to compete for are rejected.
+func (s *Storage) awardTender(tenderId, lotId, bidId string) error {
+	stmt, err := s.db.Prepare(`
+	INSERT INTO award(tenderId, lotId, bidId, approvers)
+	SELECT $1, NULLIF($2, '')::uuid, $3, coalesce(array_agg(username ORDER BY username), '{}')
+	FROM voted
+	WHERE bidId = $3 AND lotId IS NOT DISTINCT FROM NULLIF($2, '')::uuid AND decision = 'Approved'
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(tenderId, lotId, bidId)
+	if err != nil {
+		return err
+	}
+
+	stmt, err = s.db.Prepare(`
+	SELECT id, name, status, authorType, authorId, version, createdAt
+	FROM bid b
+	WHERE tenderId = $1 AND id != $2 AND status IN ('Draft', 'Created', 'Submitted', 'Published')
+		AND ($3 = '' OR NOT EXISTS (
+			SELECT 1 FROM bidLot bl
+			WHERE bl.bidId = b.id
+				AND NOT EXISTS (SELECT 1 FROM award a WHERE a.lotId = bl.lotId)
+				AND NOT EXISTS (
+					SELECT 1 FROM voted v
+					WHERE v.bidId = bl.bidId AND v.lotId = bl.lotId AND v.decision = 'Rejected'
+				)
+		))
+	`)
+	if err != nil {
+		return err
+	}
+
+	rows, err := stmt.Query(tenderId, bidId, lotId)
+	if err != nil {
+		return err
+	}
+
+	competing := make([]bids.BidResponse, 0)
+	for rows.Next() {
+		var bid bids.BidResponse
+		err = rows.Scan(&bid.Id, &bid.Name, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		competing = append(competing, bid)
+	}
+	rows.Close()
+
+	for i := range competing {
+		err = s.DecideBid(&competing[i], biddomain.Rejected)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err = s.db.Prepare(`
+	UPDATE decisions
+	SET status = 'Closed'
+	WHERE bidId IN (SELECT id FROM bid WHERE tenderId = $1)
+		AND ($2 = '' OR lotId::text = $2)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(tenderId, lotId)
+	return err
+}
+
+// GetAward returns the award of the tender, or of one of its lots when lotId
+// is not empty.
+func (s *Storage) GetAward(tenderId, lotId string) (award.Award, error) {
+	const op = "storage.postgres.GetAward"
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, tenderId, bidId, coalesce(lotId::text, ''), approvers, createdAt,
+		deliveredOnTime, coalesce(deliveryRecordedBy, ''), deliveryRecordedAt
+	FROM award
+	WHERE tenderId = $1 AND lotId IS NOT DISTINCT FROM NULLIF($2, '')::uuid
+	`)
+	if err != nil {
+		return award.Award{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var result award.Award
+	var onTime *bool
+	var recordedBy string
+	var recordedAt *time.Time
+	err = stmt.QueryRow(tenderId, lotId).Scan(
+		&result.Id,
+		&result.TenderId,
+		&result.BidId,
+		&result.LotId,
+		pq.Array(&result.Approvers),
+		&result.CreatedAt,
+		&onTime,
+		&recordedBy,
+		&recordedAt,
+	)
+	if err != nil {
+		return award.Award{}, ErrNotFound
+	}
+
+	if onTime != nil && recordedAt != nil {
+		result.Delivery = &award.Delivery{OnTime: *onTime, RecordedBy: recordedBy, RecordedAt: *recordedAt}
+	}
+
+	return result, nil
+}
+
+// RecordDelivery marks whether the award was delivered on time, replacing any
+// earlier record.
+func (s *Storage) RecordDelivery(awardId string, d award.Delivery) error {
+	const op = "storage.postgres.RecordDelivery"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE award
+	SET deliveredOnTime = $2, deliveryRecordedBy = $3, deliveryRecordedAt = $4
+	WHERE id = $1
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(awardId, d.OnTime, d.RecordedBy, d.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetContract gathers what the contract of the award names: the tender, its
+// organization, the lot and the winning bid with its author.
+func (s *Storage) GetContract(aw award.Award) (award.Contract, error) {
+	const op = "storage.postgres.GetContract"
+
+	stmt, err := s.db.Prepare(`
+	SELECT t.name, coalesce(t.description, ''), coalesce(l.serviceType, t.serviceType), o.name,
+		coalesce(l.name, ''),
+		b.name, coalesce(b.description, ''), b.authorType, b.version,
+		coalesce(ao.name, e.username, '')
+	FROM tender t
+	JOIN organization o ON o.id = t.organizationId
+	JOIN bid b ON b.id = $2
+	LEFT JOIN lot l ON l.id::text = $3
+	LEFT JOIN organization ao ON b.authorType = 'Organization' AND ao.id = b.authorId
+	LEFT JOIN employee e ON b.authorType = 'User' AND e.id = b.authorId
+	WHERE t.id = $1
+	`)
+	if err != nil {
+		return award.Contract{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := award.Contract{Award: aw}
+	err = stmt.QueryRow(aw.TenderId, aw.BidId, aw.LotId).Scan(
+		&result.TenderName,
+		&result.TenderDescription,
+		&result.ServiceType,
+		&result.OrganizationName,
+		&result.LotName,
+		&result.BidName,
+		&result.BidDescription,
+		&result.BidAuthorType,
+		&result.BidVersion,
+		&result.BidAuthorName,
+	)
+	if err != nil {
+		return award.Contract{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
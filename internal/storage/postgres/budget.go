@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"fmt"
+	"tender_system/internal/models/tender"
+)
+
+// ListBudgetLines lists the bids of a tender with their price, cheapest
+// first; unpriced bids come last.
+func (s *Storage) ListBudgetLines(tenderId string) ([]tender.BudgetReportLine, error) {
+	const op = "storage.postgres.ListBudgetLines"
+	result := make([]tender.BudgetReportLine, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, status, price, outOfBudget
+	FROM bid
+	WHERE tenderId = $1
+	ORDER BY price NULLS LAST, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line tender.BudgetReportLine
+		err = rows.Scan(&line.BidId, &line.Name, &line.Status, &line.Price, &line.OutOfBudget)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, line)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"fmt"
+	"tender_system/internal/models/tender"
+)
+
+// ListCommitteeBids lists every bid on the tender with its author's name and
+// the number of votes cast on it, oldest first.
+func (s *Storage) ListCommitteeBids(tenderId string) ([]tender.CommitteeBid, error) {
+	const op = "storage.postgres.ListCommitteeBids"
+	result := make([]tender.CommitteeBid, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT b.id, b.name, coalesce(b.authorType, ''), coalesce(b.authorId::text, ''), coalesce(o.name, e.username, ''),
+		b.version, coalesce(b.status, ''), b.price,
+		(SELECT count(*) FROM voted v WHERE v.bidId = b.id), b.createdAt
+	FROM bid b
+	LEFT JOIN organization o ON b.authorType = 'Organization' AND o.id = b.authorId
+	LEFT JOIN employee e ON b.authorType = 'User' AND e.id = b.authorId
+	WHERE b.tenderId = $1
+	ORDER BY b.createdAt, b.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bid tender.CommitteeBid
+		err = rows.Scan(&bid.BidId, &bid.Name, &bid.AuthorType, &bid.AuthorId, &bid.Author,
+			&bid.Version, &bid.Status, &bid.Price, &bid.VotesCast, &bid.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, bid)
+	}
+
+	return result, nil
+}
+
+// ListCommitteeFeedback lists the feedback left on the tender's bids,
+// grouped by bid and oldest first. Deleted comments are left out.
+func (s *Storage) ListCommitteeFeedback(tenderId string) ([]tender.CommitteeFeedback, error) {
+	const op = "storage.postgres.ListCommitteeFeedback"
+	result := make([]tender.CommitteeFeedback, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT f.bidId, b.name, coalesce(e.username, ''), f.side, f.description, f.rating, f.createdAt
+	FROM feedback f
+	JOIN bid b ON b.id = f.bidId
+	LEFT JOIN employee e ON e.id = f.authorId
+	WHERE b.tenderId = $1 AND f.deletedAt IS NULL
+	ORDER BY b.name, b.id, f.createdAt, f.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f tender.CommitteeFeedback
+		err = rows.Scan(&f.BidId, &f.BidName, &f.Author, &f.Side, &f.Description, &f.Rating, &f.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+// ListCommitteeVotes lists the votes cast on the tender's bids next to the
+// decision each one counts towards.
+func (s *Storage) ListCommitteeVotes(tenderId string) ([]tender.CommitteeVote, error) {
+	const op = "storage.postgres.ListCommitteeVotes"
+	result := make([]tender.CommitteeVote, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT v.bidId, b.name, coalesce(v.lotId::text, ''), coalesce(v.username, ''), coalesce(v.decision, ''),
+		coalesce(d.status, ''), coalesce(d.numApproved, 0)
+	FROM voted v
+	JOIN bid b ON b.id = v.bidId
+	LEFT JOIN decisions d ON d.bidId = v.bidId AND d.lotId IS NOT DISTINCT FROM v.lotId
+	WHERE b.tenderId = $1
+	ORDER BY b.name, b.id, v.lotId NULLS FIRST, v.username
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v tender.CommitteeVote
+		err = rows.Scan(&v.BidId, &v.BidName, &v.LotId, &v.Username, &v.Vote, &v.Decision, &v.NumApproved)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, v)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"tender_system/internal/models/audit"
+	"tender_system/internal/models/conflict"
+)
+
+// ReadConflictRules returns the rules the organization changed from their
+// defaults.
+func (s *Storage) ReadConflictRules(organizationId string) ([]conflict.Rule, error) {
+	const op = "storage.postgres.ReadConflictRules"
+	result := make([]conflict.Rule, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT rule, action
+	FROM conflictRule
+	WHERE organizationId = $1
+	ORDER BY rule
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r conflict.Rule
+		err = rows.Scan(&r.Rule, &r.Action)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) SaveConflictRules(organizationId string, rules []conflict.Rule) error {
+	const op = "storage.postgres.SaveConflictRules"
+
+	err := s.inTx(func(tx *Storage) error {
+		stmt, err := tx.db.Prepare(`
+		INSERT INTO conflictRule(organizationId, rule, action)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organizationId, rule) DO UPDATE SET action = EXCLUDED.action
+		`)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range rules {
+			_, err = stmt.Exec(organizationId, r.Rule, r.Action)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveRecusal records that the user will not vote on the tender's bids,
+// updating the reason of an earlier declaration.
+func (s *Storage) SaveRecusal(r conflict.Recusal) (conflict.Recusal, error) {
+	const op = "storage.postgres.SaveRecusal"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO recusal(tenderId, userId, reason)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (tenderId, userId) DO UPDATE SET reason = EXCLUDED.reason
+	RETURNING createdAt
+	`)
+	if err != nil {
+		return conflict.Recusal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = stmt.QueryRow(r.TenderId, r.UserId, r.Reason).Scan(&r.CreatedAt)
+	if err != nil {
+		return conflict.Recusal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return r, nil
+}
+
+func (s *Storage) ListRecusals(tenderId string) ([]conflict.Recusal, error) {
+	const op = "storage.postgres.ListRecusals"
+	result := make([]conflict.Recusal, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT r.tenderId, r.userId, e.username, r.reason, r.createdAt
+	FROM recusal r
+	JOIN employee e ON e.id = r.userId
+	WHERE r.tenderId = $1
+	ORDER BY r.createdAt
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r conflict.Recusal
+		err = rows.Scan(&r.TenderId, &r.UserId, &r.Username, &r.Reason, &r.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// HasVotedOnTender reports whether the user voted on any bid of the tender.
+func (s *Storage) HasVotedOnTender(tenderId, userId string) (bool, error) {
+	const op = "storage.postgres.HasVotedOnTender"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM voted v
+	JOIN bid b ON b.id = v.bidId
+	WHERE b.tenderId = $1 AND v.user_id = $2
+	`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(tenderId, userId).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+// RecordConflicts writes one audit entry per conflict finding against the
+// entity the bid or vote concerned.
+func (s *Storage) RecordConflicts(entityType, entityId, organizationId, actor string, findings []conflict.Finding) error {
+	for _, f := range findings {
+		after, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.AppendAudit(audit.Entry{
+			EntityType:     entityType,
+			EntityId:       entityId,
+			Action:         fmt.Sprintf("conflict of interest %s: %s", f.Rule, f.Action),
+			Actor:          actor,
+			OrganizationId: organizationId,
+			After:          after,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
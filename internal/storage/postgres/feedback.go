@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"fmt"
+	"tender_system/internal/models/bids"
+	"time"
+)
+
+const commentColumns = `
+	f.id, f.bidId, coalesce(f.parentId::text, ''), coalesce(f.authorId::text, ''), coalesce(e.username, ''),
+	f.side, f.description, f.rating, f.deletedAt IS NOT NULL, f.createdAt, f.editedAt
+	`
+
+func scanComment(row scanner) (bids.Comment, error) {
+	var c bids.Comment
+	err := row.Scan(
+		&c.Id,
+		&c.BidId,
+		&c.ParentId,
+		&c.AuthorId,
+		&c.Author,
+		&c.Side,
+		&c.Description,
+		&c.Rating,
+		&c.Deleted,
+		&c.CreatedAt,
+		&c.EditedAt,
+	)
+	return c, err
+}
+
+// SaveComment adds a comment to the feedback thread of its bid.
+func (s *Storage) SaveComment(c bids.Comment) (bids.Comment, error) {
+	const op = "storage.postgres.SaveComment"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO feedback(bidId, parentId, authorId, side, description, rating, createdAt)
+	VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, '')::uuid, $4, $5, $6, $7)
+	RETURNING id
+	`)
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id string
+	err = stmt.QueryRow(c.BidId, c.ParentId, c.AuthorId, c.Side, c.Description, c.Rating, time.Now().UTC()).Scan(&id)
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.GetComment(c.BidId, id)
+}
+
+func (s *Storage) GetComment(bidId, commentId string) (bids.Comment, error) {
+	const op = "storage.postgres.GetComment"
+
+	stmt, err := s.db.Prepare(`
+	SELECT ` + commentColumns + `
+	FROM feedback f
+	LEFT JOIN employee e ON e.id = f.authorId
+	WHERE f.bidId = $1 AND f.id::text = $2
+	`)
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c, err := scanComment(stmt.QueryRow(bidId, commentId))
+	if err != nil {
+		return bids.Comment{}, ErrNotFound
+	}
+
+	return c, nil
+}
+
+// ListComments returns the feedback thread of the bid, oldest first.
+func (s *Storage) ListComments(bidId string) ([]bids.Comment, error) {
+	const op = "storage.postgres.ListComments"
+	result := make([]bids.Comment, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT ` + commentColumns + `
+	FROM feedback f
+	LEFT JOIN employee e ON e.id = f.authorId
+	WHERE f.bidId = $1
+	ORDER BY f.createdAt, f.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(bidId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// UpdateComment changes the text of a comment and, unless rating is nil, its
+// rating.
+func (s *Storage) UpdateComment(commentId, description string, rating *int) (bids.Comment, error) {
+	const op = "storage.postgres.UpdateComment"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE feedback
+	SET description = $2, rating = coalesce($3, rating), editedAt = $4
+	WHERE id = $1 AND deletedAt IS NULL
+	RETURNING bidId
+	`)
+	if err != nil {
+		return bids.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var bidId string
+	err = stmt.QueryRow(commentId, description, rating, time.Now().UTC()).Scan(&bidId)
+	if err != nil {
+		return bids.Comment{}, ErrNotFound
+	}
+
+	return s.GetComment(bidId, commentId)
+}
+
+// DeleteComment blanks the comment but keeps it in place, so that replies to
+// it stay in the thread.
+func (s *Storage) DeleteComment(commentId string) error {
+	const op = "storage.postgres.DeleteComment"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE feedback
+	SET description = '', rating = NULL, deletedAt = $2
+	WHERE id = $1 AND deletedAt IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(commentId, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
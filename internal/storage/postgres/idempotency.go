@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"tender_system/internal/models/idempotency"
+	"time"
+)
+
+// ReserveIdempotencyKey claims the key for a new request. An expired claim,
+// or a reservation whose lease ran out before its request completed, is taken
+// over; otherwise the existing record is returned and reserved is false.
+func (s *Storage) ReserveIdempotencyKey(key, fingerprint string, now, lockedUntil, expiresAt time.Time) (idempotency.Record, bool, error) {
+	const op = "storage.postgres.ReserveIdempotencyKey"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO idempotencyKey(key, fingerprint, createdAt, lockedUntil, expiresAt)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint,
+		status = NULL,
+		contentType = NULL,
+		body = NULL,
+		createdAt = EXCLUDED.createdAt,
+		lockedUntil = EXCLUDED.lockedUntil,
+		expiresAt = EXCLUDED.expiresAt
+	WHERE idempotencyKey.expiresAt <= $3
+		OR (idempotencyKey.status IS NULL AND idempotencyKey.lockedUntil <= $3)
+	RETURNING ` + idempotencyColumns)
+	if err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rec, err := scanIdempotencyRecord(stmt.QueryRow(key, fingerprint, now.UTC(), lockedUntil.UTC(), expiresAt.UTC()))
+	if err == nil {
+		return rec, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return idempotency.Record{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err = s.db.Prepare(`SELECT ` + idempotencyColumns + ` FROM idempotencyKey WHERE key = $1`)
+	if err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rec, err = scanIdempotencyRecord(stmt.QueryRow(key))
+	if err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rec, false, nil
+}
+
+const idempotencyColumns = `
+	key, fingerprint, coalesce(status, 0), coalesce(contentType, ''), coalesce(body, ''), createdAt, lockedUntil, expiresAt
+	`
+
+func scanIdempotencyRecord(row scanner) (idempotency.Record, error) {
+	var rec idempotency.Record
+	var lockedUntil sql.NullTime
+	err := row.Scan(&rec.Key, &rec.Fingerprint, &rec.Status, &rec.ContentType, &rec.Body, &rec.CreatedAt, &lockedUntil, &rec.ExpiresAt)
+	rec.LockedUntil = lockedUntil.Time
+	return rec, err
+}
+
+// SaveIdempotentResponse keeps the response of the reserved request. A
+// reservation is told apart by its creation time, as the reservation
+// returned it, so that a request outliving its lease does not overwrite the
+// response of the retry that took over.
+func (s *Storage) SaveIdempotentResponse(rec idempotency.Record, status int, contentType string, body []byte) error {
+	const op = "storage.postgres.SaveIdempotentResponse"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE idempotencyKey
+	SET status = $3, contentType = NULLIF($4, ''), body = $5, lockedUntil = NULL
+	WHERE key = $1 AND createdAt = $2
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(rec.Key, rec.CreatedAt, status, contentType, body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets the reservation so that the request can be
+// retried.
+func (s *Storage) ReleaseIdempotencyKey(rec idempotency.Record) error {
+	const op = "storage.postgres.ReleaseIdempotencyKey"
+
+	stmt, err := s.db.Prepare(`DELETE FROM idempotencyKey WHERE key = $1 AND createdAt = $2`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(rec.Key, rec.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys removes the keys that expired by now.
+func (s *Storage) PurgeIdempotencyKeys(now time.Time) error {
+	const op = "storage.postgres.PurgeIdempotencyKeys"
+
+	stmt, err := s.db.Prepare(`DELETE FROM idempotencyKey WHERE expiresAt <= $1`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(now.UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
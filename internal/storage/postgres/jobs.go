@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"tender_system/internal/models/job"
+	"time"
+)
+
+const backgroundJobColumns = `
+	id, kind, coalesce(subjectId::text, ''), payload, status, attempts, maxAttempts, runAt, coalesce(lastError, ''), lockedUntil, createdAt, updatedAt
+	`
+
+func scanBackgroundJob(row scanner) (job.Job, error) {
+	var j job.Job
+	var payload string
+	var lockedUntil sql.NullTime
+	err := row.Scan(&j.Id, &j.Kind, &j.SubjectId, &payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.RunAt, &j.LastError, &lockedUntil, &j.CreatedAt, &j.UpdatedAt)
+	j.Payload = []byte(payload)
+	j.LockedUntil = lockedUntil.Time
+	return j, err
+}
+
+// JobQueue is the background job queue kept in the backgroundJob table.
+type JobQueue struct {
+	db *sql.DB
+}
+
+func (s *Storage) JobQueue() *JobQueue {
+	return &JobQueue{db: s.conn}
+}
+
+func (q *JobQueue) Enqueue(j job.Job) (job.Job, error) {
+	const op = "storage.postgres.JobQueue.Enqueue"
+
+	j, err := enqueueJob(q.db, j)
+	if err != nil {
+		return job.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return j, nil
+}
+
+// EnqueueJob adds the job to the background job queue along with the
+// storage's other changes, so that a job scheduled in a transaction only
+// runs once it commits.
+func (s *Storage) EnqueueJob(j job.Job) (job.Job, error) {
+	const op = "storage.postgres.EnqueueJob"
+
+	j, err := enqueueJob(s.db, j)
+	if err != nil {
+		return job.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return j, nil
+}
+
+// enqueueJob inserts the job. A job with a subject replaces the subject's
+// pending job of the same kind, which moves to the new time and payload.
+func enqueueJob(db dbtx, j job.Job) (job.Job, error) {
+	stmt, err := db.Prepare(`
+	INSERT INTO backgroundJob(kind, subjectId, payload, maxAttempts, runAt)
+	VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5)
+	ON CONFLICT (kind, subjectId) WHERE status = 'Pending'
+	DO UPDATE SET
+		payload = EXCLUDED.payload,
+		maxAttempts = EXCLUDED.maxAttempts,
+		runAt = EXCLUDED.runAt,
+		attempts = 0,
+		lastError = NULL,
+		updatedAt = CURRENT_TIMESTAMP
+	RETURNING ` + backgroundJobColumns)
+	if err != nil {
+		return job.Job{}, err
+	}
+
+	return scanBackgroundJob(stmt.QueryRow(j.Kind, j.SubjectId, string(j.Payload), j.MaxAttempts, j.RunAt.UTC()))
+}
+
+// CancelJobs withdraws the subject's pending jobs of the kind.
+func (s *Storage) CancelJobs(kind, subjectId string) error {
+	const op = "storage.postgres.CancelJobs"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE backgroundJob
+	SET status = 'Cancelled', updatedAt = $3
+	WHERE kind = $1 AND subjectId = $2 AND status = 'Pending'
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(kind, subjectId, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Claim takes up to limit jobs of the kind that are due or whose earlier
+// claim expired. Rows locked by another replica's claim are skipped rather
+// than waited for.
+func (q *JobQueue) Claim(kind string, limit int, now, lockedUntil time.Time) ([]job.Job, error) {
+	const op = "storage.postgres.JobQueue.Claim"
+	result := make([]job.Job, 0)
+
+	stmt, err := q.db.Prepare(`
+	UPDATE backgroundJob
+	SET status = 'Running', attempts = attempts + 1, lockedUntil = $4, updatedAt = $3
+	WHERE id IN (
+		SELECT id
+		FROM backgroundJob
+		WHERE kind = $1
+			AND ((status = 'Pending' AND runAt <= $3) OR (status = 'Running' AND lockedUntil < $3))
+		ORDER BY runAt
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + backgroundJobColumns)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(kind, limit, now.UTC(), lockedUntil.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		j, err := scanBackgroundJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// Complete marks the claimed job done. Like Retry and Fail it only applies
+// while the claim lasts: once the job was claimed again after its lease ran
+// out, the earlier run's outcome is dropped with ErrConflict.
+func (q *JobQueue) Complete(j job.Job) error {
+	const op = "storage.postgres.JobQueue.Complete"
+
+	stmt, err := q.db.Prepare(`
+	UPDATE backgroundJob
+	SET status = 'Done', lockedUntil = NULL, lastError = NULL, updatedAt = $4
+	WHERE id = $1 AND status = 'Running' AND attempts = $2 AND lockedUntil = $3
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(j.Id, j.Attempts, j.LockedUntil, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claimHeld(op, j, res)
+}
+
+// Retry returns the claimed job to the queue to run again at runAt.
+func (q *JobQueue) Retry(j job.Job, lastError string, runAt time.Time) error {
+	const op = "storage.postgres.JobQueue.Retry"
+
+	stmt, err := q.db.Prepare(`
+	UPDATE backgroundJob
+	SET status = 'Pending', lockedUntil = NULL, lastError = $4, runAt = $5, updatedAt = $6
+	WHERE id = $1 AND status = 'Running' AND attempts = $2 AND lockedUntil = $3
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(j.Id, j.Attempts, j.LockedUntil, lastError, runAt.UTC(), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claimHeld(op, j, res)
+}
+
+// Fail gives the claimed job up.
+func (q *JobQueue) Fail(j job.Job, lastError string) error {
+	const op = "storage.postgres.JobQueue.Fail"
+
+	stmt, err := q.db.Prepare(`
+	UPDATE backgroundJob
+	SET status = 'Failed', lockedUntil = NULL, lastError = $4, updatedAt = $5
+	WHERE id = $1 AND status = 'Running' AND attempts = $2 AND lockedUntil = $3
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(j.Id, j.Attempts, j.LockedUntil, lastError, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claimHeld(op, j, res)
+}
+
+// claimHeld reports ErrConflict when an update matched no claim. A claim is
+// told apart by the attempt it counted and the lease it took, both as Claim
+// returned them.
+func claimHeld(op string, j job.Job, res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w: the claim of attempt %d on job %s was lost", op, ErrConflict, j.Attempts, j.Id)
+	}
+	return nil
+}
+
+// ListFailed pages through the failed jobs, most recently failed first,
+// limited to one kind when kind is set.
+func (q *JobQueue) ListFailed(kind string, limit, offset int) ([]job.Job, error) {
+	const op = "storage.postgres.JobQueue.ListFailed"
+	result := make([]job.Job, 0)
+
+	stmt, err := q.db.Prepare(`
+	SELECT ` + backgroundJobColumns + `
+	FROM backgroundJob
+	WHERE status = 'Failed' AND ($1 = '' OR kind = $1)
+	ORDER BY updatedAt DESC
+	LIMIT $2
+	OFFSET $3
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(kind, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		j, err := scanBackgroundJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"fmt"
+	"tender_system/internal/models/lot"
+
+	"github.com/lib/pq"
+)
+
+func (s *Storage) ListLots(tenderId string) ([]lot.Lot, error) {
+	const op = "storage.postgres.ListLots"
+	result := make([]lot.Lot, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, tenderId, name, description, serviceType, budget, createdAt
+	FROM lot
+	WHERE tenderId = $1 AND active
+	ORDER BY createdAt, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l lot.Lot
+		err = rows.Scan(&l.Id, &l.TenderId, &l.Name, &l.Description, &l.ServiceType, &l.Budget, &l.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, l)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) GetLot(tenderId, lotId string) (lot.Lot, error) {
+	const op = "storage.postgres.GetLot"
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, tenderId, name, description, serviceType, budget, createdAt
+	FROM lot
+	WHERE tenderId = $1 AND id::text = $2 AND active
+	`)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var l lot.Lot
+	err = stmt.QueryRow(tenderId, lotId).Scan(&l.Id, &l.TenderId, &l.Name, &l.Description, &l.ServiceType, &l.Budget, &l.CreatedAt)
+	if err != nil {
+		return lot.Lot{}, ErrNotFound
+	}
+
+	return l, nil
+}
+
+// AddLot adds a lot to the tender as a new tender version.
+func (s *Storage) AddLot(tenderId string, req lot.LotRequest) (lot.Lot, error) {
+	const op = "storage.postgres.AddLot"
+
+	err := s.archiveEntity(AttachmentTender, tenderId)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := s.insertLot(tenderId, req)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.bumpEntityVersion(AttachmentTender, tenderId)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// UpdateLot stores the changed lot as a new tender version.
+func (s *Storage) UpdateLot(l lot.Lot) (lot.Lot, error) {
+	const op = "storage.postgres.UpdateLot"
+
+	err := s.archiveEntity(AttachmentTender, l.TenderId)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`
+	UPDATE lot
+	SET name = $2, description = $3, serviceType = $4, budget = $5
+	WHERE id = $1
+	`)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(l.Id, l.Name, l.Description, l.ServiceType, l.Budget)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.bumpEntityVersion(AttachmentTender, l.TenderId)
+	if err != nil {
+		return lot.Lot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return l, nil
+}
+
+// RemoveLot drops a lot from the current tender version. Older versions
+// keep it, so a rollback brings it back.
+func (s *Storage) RemoveLot(tenderId, lotId string) error {
+	const op = "storage.postgres.RemoveLot"
+
+	err := s.archiveEntity(AttachmentTender, tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`
+	UPDATE lot
+	SET active = FALSE
+	WHERE tenderId = $1 AND id = $2
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(tenderId, lotId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.bumpEntityVersion(AttachmentTender, tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) insertLot(tenderId string, req lot.LotRequest) (lot.Lot, error) {
+	stmt, err := s.db.Prepare(`
+	INSERT INTO lot(tenderId, name, description, serviceType, budget)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, tenderId, name, description, serviceType, budget, createdAt
+	`)
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	var l lot.Lot
+	err = stmt.QueryRow(tenderId, req.Name, req.Description, req.ServiceType, req.Budget).Scan(&l.Id, &l.TenderId, &l.Name, &l.Description, &l.ServiceType, &l.Budget, &l.CreatedAt)
+	if err != nil {
+		return lot.Lot{}, err
+	}
+
+	return l, nil
+}
+
+// snapshotLots records the lots of the given tender version. It must be
+// called next to every tenderHistory insert.
+func (s *Storage) snapshotLots(tenderId string, version int) error {
+	stmt, err := s.db.Prepare(`
+	INSERT INTO lotHistory(tenderId, version, lotId, name, description, serviceType, budget)
+	SELECT tenderId, $2, id, name, description, serviceType, budget
+	FROM lot
+	WHERE tenderId = $1 AND active
+	ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(tenderId, version)
+	return err
+}
+
+// restoreLots makes the lots of the given historical tender version the
+// current ones.
+func (s *Storage) restoreLots(tenderId string, version int) error {
+	stmt, err := s.db.Prepare(`
+	UPDATE lot l
+	SET active = h.lotId IS NOT NULL,
+		name = coalesce(h.name, l.name),
+		description = coalesce(h.description, l.description),
+		serviceType = coalesce(h.serviceType, l.serviceType),
+		budget = CASE WHEN h.lotId IS NULL THEN l.budget ELSE h.budget END
+	FROM lot l2
+	LEFT JOIN lotHistory h
+	ON h.lotId = l2.id AND h.tenderId = $1 AND h.version = $2
+	WHERE l.id = l2.id AND l.tenderId = $1
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(tenderId, version)
+	return err
+}
+
+func (s *Storage) SaveBidLots(bidId string, lotIds []string) error {
+	const op = "storage.postgres.SaveBidLots"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO bidLot(bidId, lotId)
+	SELECT $1, unnest($2::uuid[])
+	ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(bidId, pq.Array(lotIds))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ReadBidLots(bidId string) ([]string, error) {
+	const op = "storage.postgres.ReadBidLots"
+	result := make([]string, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT lotId
+	FROM bidLot
+	WHERE bidId = $1
+	ORDER BY lotId
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(bidId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lotId string
+		err = rows.Scan(&lotId)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, lotId)
+	}
+
+	return result, nil
+}
+
+// CountOpenBidLots counts the lots a bid still competes for, i.e. those not
+// awarded yet on which the bid has not been rejected.
+func (s *Storage) CountOpenBidLots(bidId string) (int, error) {
+	const op = "storage.postgres.CountOpenBidLots"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM bidLot bl
+	WHERE bl.bidId = $1
+		AND NOT EXISTS (SELECT 1 FROM award a WHERE a.lotId = bl.lotId)
+		AND NOT EXISTS (
+			SELECT 1 FROM voted v
+			WHERE v.bidId = bl.bidId AND v.lotId = bl.lotId AND v.decision = 'Rejected'
+		)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(bidId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (s *Storage) CountUnawardedLots(tenderId string) (int, error) {
+	const op = "storage.postgres.CountUnawardedLots"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM lot l
+	WHERE l.tenderId = $1 AND l.active
+		AND NOT EXISTS (SELECT 1 FROM award a WHERE a.lotId = l.id)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(tenderId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/notification"
+
+	"github.com/lib/pq"
+)
+
+// ReadNotificationSettings returns the user's preferences, or the defaults if
+// they never set any.
+func (s *Storage) ReadNotificationSettings(userId string) (notification.Settings, error) {
+	const op = "storage.postgres.ReadNotificationSettings"
+
+	stmt, err := s.db.Prepare(`
+	SELECT language, inApp, email, coalesce(emailAddress, ''), muted
+	FROM notificationSettings
+	WHERE userId = $1
+	`)
+	if err != nil {
+		return notification.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	settings := notification.DefaultSettings()
+	err = stmt.QueryRow(userId).Scan(&settings.Language, &settings.InApp, &settings.Email, &settings.EmailAddress, pq.Array(&settings.Muted))
+	if err == sql.ErrNoRows {
+		return notification.DefaultSettings(), nil
+	}
+	if err != nil {
+		return notification.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, nil
+}
+
+func (s *Storage) SaveNotificationSettings(userId string, settings notification.Settings) (notification.Settings, error) {
+	const op = "storage.postgres.SaveNotificationSettings"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO notificationSettings(userId, language, inApp, email, emailAddress, muted)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+	ON CONFLICT (userId) DO UPDATE
+	SET language = $2, inApp = $3, email = $4, emailAddress = NULLIF($5, ''), muted = $6
+	`)
+	if err != nil {
+		return notification.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if settings.Muted == nil {
+		settings.Muted = []string{}
+	}
+
+	_, err = stmt.Exec(userId, settings.Language, settings.InApp, settings.Email, settings.EmailAddress, pq.Array(settings.Muted))
+	if err != nil {
+		return notification.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, nil
+}
+
+func (s *Storage) SaveNotification(n notification.Notification) (notification.Notification, error) {
+	const op = "storage.postgres.SaveNotification"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO notification(userId, event, tenderId, bidId, subject, body)
+	VALUES ($1, $2, NULLIF($3, '')::uuid, NULLIF($4, '')::uuid, $5, $6)
+	RETURNING id, read, createdAt
+	`)
+	if err != nil {
+		return notification.Notification{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = stmt.QueryRow(n.UserId, n.Event, n.TenderId, n.BidId, n.Subject, n.Body).Scan(&n.Id, &n.Read, &n.CreatedAt)
+	if err != nil {
+		return notification.Notification{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+// ListNotifications pages through the user's inbox, newest first.
+func (s *Storage) ListNotifications(userId string, unreadOnly bool, limit, offset int) ([]notification.Notification, error) {
+	const op = "storage.postgres.ListNotifications"
+	result := make([]notification.Notification, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, userId, event, coalesce(tenderId::text, ''), coalesce(bidId::text, ''), subject, body, read, createdAt
+	FROM notification
+	WHERE userId = $1 AND (NOT $2 OR NOT read)
+	ORDER BY createdAt DESC
+	LIMIT $3
+	OFFSET $4
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(userId, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n notification.Notification
+		err = rows.Scan(&n.Id, &n.UserId, &n.Event, &n.TenderId, &n.BidId, &n.Subject, &n.Body, &n.Read, &n.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, n)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) MarkNotificationRead(userId, notificationId string) error {
+	const op = "storage.postgres.MarkNotificationRead"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE notification
+	SET read = TRUE
+	WHERE userId = $1 AND id::text = $2
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(userId, notificationId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) MarkAllNotificationsRead(userId string) error {
+	const op = "storage.postgres.MarkAllNotificationsRead"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE notification
+	SET read = TRUE
+	WHERE userId = $1 AND NOT read
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListTenderBidAuthors returns every distinct author of a bid on the tender.
+func (s *Storage) ListTenderBidAuthors(tenderId string) ([]bids.Author, error) {
+	const op = "storage.postgres.ListTenderBidAuthors"
+	result := make([]bids.Author, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT DISTINCT authorType, authorId
+	FROM bid
+	WHERE tenderId = $1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author bids.Author
+		err = rows.Scan(&author.Type, &author.Id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, author)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,453 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"tender_system/internal/models/organization"
+	"tender_system/internal/models/user"
+
+	"github.com/lib/pq"
+)
+
+// isUniqueViolation reports whether err was caused by a UNIQUE constraint.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func (s *Storage) ListOrganizations(limit, offset int) ([]organization.Organization, error) {
+	const op = "storage.postgres.ListOrganizations"
+	result := make([]organization.Organization, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, coalesce(description, ''), coalesce(type::text, ''), created_at, updated_at
+	FROM organization
+	ORDER BY name
+	LIMIT $1
+	OFFSET $2
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var org organization.Organization
+		err = rows.Scan(&org.Id, &org.Name, &org.Description, &org.Type, &org.CreatedAt, &org.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, org)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) GetOrganization(organizationId string) (organization.Organization, error) {
+	const op = "storage.postgres.GetOrganization"
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, coalesce(description, ''), coalesce(type::text, ''), created_at, updated_at
+	FROM organization
+	WHERE id = $1
+	`)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var org organization.Organization
+	err = stmt.QueryRow(organizationId).Scan(&org.Id, &org.Name, &org.Description, &org.Type, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		return organization.Organization{}, ErrNotFound
+	}
+
+	return org, nil
+}
+
+// SaveOrganization creates an organization and makes creatorId its first
+// responsible.
+func (s *Storage) SaveOrganization(req organization.OrganizationRequest, creatorId string) (organization.Organization, error) {
+	const op = "storage.postgres.SaveOrganization"
+
+	stmt, err := s.db.Prepare(`
+	WITH org AS (
+		INSERT INTO organization(name, description, type)
+		VALUES ($1, $2, $3::organization_type)
+		RETURNING id, name, coalesce(description, '') AS description, type::text AS type, created_at, updated_at
+	), responsible AS (
+		INSERT INTO organization_responsible(organization_id, user_id)
+		SELECT id, $4 FROM org
+	)
+	SELECT id, name, description, type, created_at, updated_at
+	FROM org
+	`)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var org organization.Organization
+	err = stmt.QueryRow(req.Name, req.Description, req.Type, creatorId).Scan(&org.Id, &org.Name, &org.Description, &org.Type, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, nil
+}
+
+func (s *Storage) UpdateOrganization(org organization.Organization) (organization.Organization, error) {
+	const op = "storage.postgres.UpdateOrganization"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE organization
+	SET name = $2, description = $3, type = $4::organization_type, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING updated_at
+	`)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = stmt.QueryRow(org.Id, org.Name, org.Description, org.Type).Scan(&org.UpdatedAt)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, nil
+}
+
+func (s *Storage) DeleteOrganization(organizationId string) error {
+	const op = "storage.postgres.DeleteOrganization"
+
+	stmt, err := s.db.Prepare(`
+	DELETE FROM organization
+	WHERE id = $1
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(organizationId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountOpenTenders counts the tenders of an organization that have not been
+// cancelled or awarded yet.
+func (s *Storage) CountOpenTenders(organizationId string) (int, error) {
+	const op = "storage.postgres.CountOpenTenders"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM tender
+	WHERE organizationId = $1 AND status NOT IN ('Cancelled', 'Awarded')
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(organizationId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (s *Storage) ListResponsibles(organizationId string, limit, offset int) ([]organization.Responsible, error) {
+	const op = "storage.postgres.ListResponsibles"
+	result := make([]organization.Responsible, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT r.id, r.organization_id, r.user_id, e.username
+	FROM organization_responsible r
+	JOIN employee e
+	ON e.id = r.user_id
+	WHERE r.organization_id = $1
+	ORDER BY e.username
+	LIMIT $2
+	OFFSET $3
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(organizationId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resp organization.Responsible
+		err = rows.Scan(&resp.Id, &resp.OrganizationId, &resp.UserId, &resp.Username)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, resp)
+	}
+
+	return result, nil
+}
+
+// AddResponsible makes userId a responsible of the organization. Adding an
+// existing responsible again returns the existing assignment.
+func (s *Storage) AddResponsible(organizationId, userId string) (organization.Responsible, error) {
+	const op = "storage.postgres.AddResponsible"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO organization_responsible(organization_id, user_id)
+	SELECT $1, $2
+	WHERE NOT EXISTS (
+		SELECT 1 FROM organization_responsible
+		WHERE organization_id = $1 AND user_id = $2
+	)
+	`)
+	if err != nil {
+		return organization.Responsible{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(organizationId, userId)
+	if err != nil {
+		return organization.Responsible{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err = s.db.Prepare(`
+	SELECT r.id, r.organization_id, r.user_id, e.username
+	FROM organization_responsible r
+	JOIN employee e
+	ON e.id = r.user_id
+	WHERE r.organization_id = $1 AND r.user_id = $2
+	LIMIT 1
+	`)
+	if err != nil {
+		return organization.Responsible{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var resp organization.Responsible
+	err = stmt.QueryRow(organizationId, userId).Scan(&resp.Id, &resp.OrganizationId, &resp.UserId, &resp.Username)
+	if err != nil {
+		return organization.Responsible{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// RemoveResponsible removes userId from the responsibles of the organization
+// unless guard, given what the organization has left, refuses.
+func (s *Storage) RemoveResponsible(organizationId, userId string, guard func(organizationId string, responsibles, openTenders int) error) error {
+	const op = "storage.postgres.RemoveResponsible"
+
+	err := s.inTx(func(tx *Storage) error {
+		err := tx.guardResponsibles(organizationId, guard)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.db.Exec(`
+		DELETE FROM organization_responsible
+		WHERE organization_id = $1 AND user_id = $2
+		`, organizationId, userId)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// guardResponsibles locks the organization's row, so that responsibles are
+// removed from it one at a time, and hands guard how many responsibles and
+// open tenders it has.
+func (s *Storage) guardResponsibles(organizationId string, guard func(organizationId string, responsibles, openTenders int) error) error {
+	var id string
+	err := s.db.QueryRow(`
+	SELECT id
+	FROM organization
+	WHERE id = $1
+	FOR UPDATE
+	`, organizationId).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	responsibles, err := s.CountOrganizationResponsibles(organizationId)
+	if err != nil {
+		return err
+	}
+	open, err := s.CountOpenTenders(organizationId)
+	if err != nil {
+		return err
+	}
+
+	return guard(organizationId, responsibles, open)
+}
+
+// ReadResponsibleOrganizations lists the organizations userId is a
+// responsible of.
+func (s *Storage) ReadResponsibleOrganizations(userId string) ([]string, error) {
+	const op = "storage.postgres.ReadResponsibleOrganizations"
+	result := make([]string, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT DISTINCT organization_id
+	FROM organization_responsible
+	WHERE user_id = $1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orgId string
+		err = rows.Scan(&orgId)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, orgId)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) ListEmployees(limit, offset int) ([]user.User, error) {
+	const op = "storage.postgres.ListEmployees"
+	result := make([]user.User, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, username, coalesce(first_name, ''), coalesce(last_name, ''), created_at, updated_at
+	FROM employee
+	ORDER BY username
+	LIMIT $1
+	OFFSET $2
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var usr user.User
+		err = rows.Scan(&usr.Id, &usr.Username, &usr.FirstName, &usr.LastName, &usr.CreatedAt, &usr.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, usr)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) SaveEmployee(req user.EmployeeRequest) (user.User, error) {
+	const op = "storage.postgres.SaveEmployee"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO employee(username, first_name, last_name)
+	VALUES ($1, $2, $3)
+	RETURNING id, username, first_name, last_name, created_at, updated_at
+	`)
+	if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var usr user.User
+	err = stmt.QueryRow(req.Username, req.FirstName, req.LastName).Scan(&usr.Id, &usr.Username, &usr.FirstName, &usr.LastName, &usr.CreatedAt, &usr.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return user.User{}, fmt.Errorf("%w: username %s is taken", ErrConflict, req.Username)
+		}
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usr, nil
+}
+
+func (s *Storage) UpdateEmployee(usr user.User) (user.User, error) {
+	const op = "storage.postgres.UpdateEmployee"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE employee
+	SET first_name = $2, last_name = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING updated_at
+	`)
+	if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = stmt.QueryRow(usr.Id, usr.FirstName, usr.LastName).Scan(&usr.UpdatedAt)
+	if err != nil {
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usr, nil
+}
+
+// DeleteEmployee deletes the employee unless guard refuses for one of the
+// organizations the employee is a responsible of.
+func (s *Storage) DeleteEmployee(userId string, guard func(organizationId string, responsibles, openTenders int) error) error {
+	const op = "storage.postgres.DeleteEmployee"
+
+	err := s.inTx(func(tx *Storage) error {
+		organizations, err := tx.ReadResponsibleOrganizations(userId)
+		if err != nil {
+			return err
+		}
+
+		// Locked in a fixed order so that two deletions cannot deadlock.
+		slices.Sort(organizations)
+		for _, orgId := range organizations {
+			err = tx.guardResponsibles(orgId, guard)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.db.Exec(`
+		DELETE FROM employee
+		WHERE id = $1
+		`, userId)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
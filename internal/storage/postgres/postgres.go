@@ -2,24 +2,69 @@ package postgres
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
+	scheduledomain "tender_system/internal/domain/schedule"
+	tenderdomain "tender_system/internal/domain/tender"
 	"tender_system/internal/models/bids"
 	"tender_system/internal/models/tender"
 	"tender_system/internal/models/user"
+	"tender_system/internal/storage"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Storage struct {
-	db *sql.DB
+	db   dbtx
+	conn *sql.DB
+	tx   *sql.Tx
+}
+
+// dbtx is what the queries run on: the connection pool, or a transaction on
+// a Storage bound to one by inTx.
+type dbtx interface {
+	Prepare(query string) (*sql.Stmt, error)
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// inTx runs fn with a copy of the storage whose queries share one
+// transaction, committed when fn succeeds. Called on a storage that is
+// already bound to a transaction it runs fn within that one.
+func (s *Storage) inTx(fn func(tx *Storage) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(&Storage{db: tx, conn: s.conn, tx: tx})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Atomically runs fn in one transaction, handing it a Storage bound to it,
+// so that services can compose several changes into one.
+func (s *Storage) Atomically(fn func(repo any) error) error {
+	return s.inTx(func(tx *Storage) error {
+		return fn(tx)
+	})
 }
 
 var (
-	ErrBadRequest   = errors.New("bad request")
-	ErrUserNotFound = errors.New("user doesn't exist or is invalid")
-	ErrForbidden    = errors.New("not enough access rights")
-	ErrNotFound     = errors.New("404 Not Found")
+	ErrBadRequest   = storage.ErrBadRequest
+	ErrUserNotFound = storage.ErrUserNotFound
+	ErrForbidden    = storage.ErrForbidden
+	ErrNotFound     = storage.ErrNotFound
+	ErrConflict     = storage.ErrConflict
 )
 
 func New(storagePath string) (*Storage, error) {
@@ -52,6 +97,19 @@ func New(storagePath string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS deadline TIMESTAMP;
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	stmt, err = db.Prepare(`
 	CREATE TABLE IF NOT EXISTS tenderHistory (
 		tenderId UUID,
@@ -188,1693 +246,1501 @@ func New(storagePath string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
-}
-
-func (s *Storage) SaveTender(ten tender.TenderRequest) (tender.TenderResponse, error) {
-	const op = "storage.postgres.SaveTender"
-
-	var user_id string
-	stmt, err := s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username = $1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS attachment (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		entityType VARCHAR(10) NOT NULL,
+		entityId UUID NOT NULL,
+		fileName VARCHAR(255) NOT NULL,
+		contentType VARCHAR(255) NOT NULL,
+		size BIGINT NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		storageKey VARCHAR(500) NOT NULL,
+		uploadedBy VARCHAR(100),
+		active BOOLEAN DEFAULT TRUE,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer func() {
-		stmt.Close()
-	}()
-
-	err = stmt.QueryRow(ten.CreatorUsername).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var trash int
-	stmt, err = s.db.Prepare(`
-	SELECT 1 
-	FROM organization
-	WHERE id = $1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS attachmentHistory (
+		entityId UUID NOT NULL,
+		version INT NOT NULL,
+		attachmentId UUID REFERENCES attachment(id) ON DELETE CASCADE,
+		PRIMARY KEY(entityId, version, attachmentId)
+	);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(ten.OrganizationId).Scan(&trash)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrBadRequest
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM organization_responsible
-	WHERE organization_id=$1 AND user_id=$2
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS tenderTransition (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		tenderId UUID REFERENCES tender(id) ON DELETE CASCADE,
+		fromStatus VARCHAR(50) NOT NULL,
+		toStatus VARCHAR(50) NOT NULL,
+		actor VARCHAR(100) NOT NULL,
+		reason VARCHAR(500) NOT NULL DEFAULT '',
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	err = stmt.QueryRow(ten.OrganizationId, user_id).Scan(&trash)
+
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var result tender.TenderResponse
-
-	stmt, err = s.db.Prepare(`
-	INSERT INTO tender(name, description, serviceType, status, organizationId)
-	VALUES ($1, $2, $3, 'Created', $4)
-	RETURNING id, name, description, status, serviceType, version, createdAt
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS award (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		tenderId UUID UNIQUE REFERENCES tender(id) ON DELETE CASCADE,
+		bidId UUID REFERENCES bid(id) ON DELETE CASCADE,
+		approvers TEXT[] NOT NULL DEFAULT '{}',
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`)
 
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(
-		ten.Name,
-		ten.Description,
-		ten.ServiceType,
-		ten.OrganizationId,
-	).Scan(&result.Id, &result.Name, &result.Description, &result.Status, &result.ServiceType, &result.Version, &result.CreatedAt)
-
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO tenderHolder(tenderId, creatorUsername)
-	VALUES ($1, $2)
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS organization_role (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		organization_id UUID REFERENCES organization(id) ON DELETE CASCADE,
+		user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+		role VARCHAR(50) NOT NULL,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(organization_id, user_id, role)
+	);
 	`)
 
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(result.Id, ten.CreatorUsername)
-
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return result, nil
-
-}
-
-func (s *Storage) ReadTenders(limit, offset int, serviceType string) ([]tender.TenderResponse, error) {
-	const op = "storage.postgres.ReadTenders"
-	result := make([]tender.TenderResponse, 0)
-	var query string
-	if serviceType == "" {
-		query = `
-		SELECT id, name, description, status, serviceType, version, createdAt
-		FROM tender
-		LIMIT $1
-		OFFSET $2
-		`
-	} else {
-		query = fmt.Sprintf(`
-	SELECT id, name, description, status, serviceType, version, createdAt
-	FROM tender
-	WHERE serviceType='%s'
-	LIMIT $1
-	OFFSET $2
-	`, serviceType)
-	}
-	stmt, err := s.db.Prepare(query)
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS lot (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		tenderId UUID REFERENCES tender(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		description VARCHAR(500) NOT NULL,
+		serviceType VARCHAR(50) NOT NULL,
+		budget NUMERIC(18, 2),
+		active BOOLEAN DEFAULT TRUE,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`)
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := stmt.Query(limit, offset)
+	_, err = stmt.Exec()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	for rows.Next() {
-		var ten tender.TenderResponse
-
-		err := rows.Scan(&ten.Id, &ten.Name, &ten.Description, &ten.Status, &ten.ServiceType, &ten.Version, &ten.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
-
-		result = append(result, ten)
-	}
-
-	return result, nil
-}
-
-func (s *Storage) ReadMyTenders(username string, limit int, offset int) ([]tender.TenderResponse, error) {
-	const op = "storage.postgres.ReadMyTenders"
-	result := make([]tender.TenderResponse, 0)
-	var user_id string
-	stmt, err := s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username = $1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS lotHistory (
+		tenderId UUID NOT NULL,
+		version INT NOT NULL,
+		lotId UUID REFERENCES lot(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		description VARCHAR(500) NOT NULL,
+		serviceType VARCHAR(50) NOT NULL,
+		budget NUMERIC(18, 2),
+		PRIMARY KEY(tenderId, version, lotId)
+	);
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer func() {
-		stmt.Close()
-	}()
-
-	err = stmt.QueryRow(username).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT t.id, name, description, status, serviceType, version, createdAt
-	FROM tender t
-	INNER JOIN tenderHolder th
-	ON th.tenderId = t.id
-	WHERE creatorUsername=$1
-	LIMIT $2
-	OFFSET $3;
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS bidLot (
+		bidId UUID REFERENCES bid(id) ON DELETE CASCADE,
+		lotId UUID REFERENCES lot(id) ON DELETE CASCADE,
+		PRIMARY KEY(bidId, lotId)
+	);
 	`)
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := stmt.Query(username, limit, offset)
+	_, err = stmt.Exec()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	for rows.Next() {
-		var ten tender.TenderResponse
-
-		err := rows.Scan(&ten.Id, &ten.Name, &ten.Description, &ten.Status, &ten.ServiceType, &ten.Version, &ten.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
+	stmt, err = db.Prepare(`
+	ALTER TABLE decisions ADD COLUMN IF NOT EXISTS lotId UUID REFERENCES lot(id) ON DELETE CASCADE;
+	`)
 
-		result = append(result, ten)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return result, nil
-}
-
-func (s *Storage) ReadTenderStatus(tenderId, username string) (string, error) {
-	const op = "storage.postgres.ReadTenderStatus"
-	var status, organization_id string
-
-	stmt, err := s.db.Prepare(`
-	SELECT status, organizationId
-	FROM tender 
-	WHERE id=$1
-	`)
+	_, err = stmt.Exec()
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(tenderId).Scan(&status, &organization_id)
+	stmt, err = db.Prepare(`
+	ALTER TABLE voted ADD COLUMN IF NOT EXISTS lotId UUID REFERENCES lot(id) ON DELETE CASCADE;
+	`)
+
 	if err != nil {
-		return "", ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if status == "Published" {
-		return status, nil
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var user_id string
-	stmt, err = s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username = $1
+	stmt, err = db.Prepare(`
+	ALTER TABLE award ADD COLUMN IF NOT EXISTS lotId UUID REFERENCES lot(id) ON DELETE CASCADE;
 	`)
+
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer func() {
-		stmt.Close()
-	}()
-
-	err = stmt.QueryRow(username).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return "", ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var trash int
-
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM organization_responsible
-	WHERE organization_id=$1 AND user_id=$2
+	stmt, err = db.Prepare(`
+	ALTER TABLE award DROP CONSTRAINT IF EXISTS award_tenderid_key;
 	`)
+
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	err = stmt.QueryRow(organization_id, user_id).Scan(&trash)
+
+	_, err = stmt.Exec()
 	if err != nil {
-		return "", ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return status, nil
-}
-
-func (s *Storage) CheckOrganizationResponsible(username, organization_id string) (bool, error) {
-	const op = "storage.postgres.CheckOrganizationResponsible"
-
-	stmt, err := s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
+	stmt, err = db.Prepare(`
+	CREATE UNIQUE INDEX IF NOT EXISTS award_tender_lot ON award(tenderId, coalesce(lotId, '00000000-0000-0000-0000-000000000000'));
 	`)
 
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var user_id string
-
-	err = stmt.QueryRow(username).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT * 
-	FROM organization_responsible
-	WHERE organization_id=$1 AND user_id=$2
+	stmt, err = db.Prepare(`
+	DELETE FROM voted v
+	USING voted o
+	WHERE o.bidId = v.bidId AND o.lotId IS NOT DISTINCT FROM v.lotId AND o.user_id = v.user_id AND o.id < v.id;
 	`)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
-	}
 
-	var responsible user.OrganizationResponsible
-	err = stmt.QueryRow(organization_id, user_id).Scan(&responsible.Id, &responsible.OrganizationId, &responsible.UserId)
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if responsible.OrganizationId != organization_id || responsible.UserId != user_id {
-		return false, nil
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return true, nil
-}
-
-func (s *Storage) FetchUser(username string) (user.User, error) {
-	const op = "storage.postgres.FetchUser"
-	var usr user.User
-
-	stmt, err := s.db.Prepare(`
-	SELECT id, username, first_name, last_name, created_at, updated_at
-	FROM employee
-	WHERE username=$1
+	stmt, err = db.Prepare(`
+	CREATE UNIQUE INDEX IF NOT EXISTS voted_bid_lot_user ON voted(bidId, coalesce(lotId, '00000000-0000-0000-0000-000000000000'), user_id);
 	`)
+
 	if err != nil {
-		return user.User{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(username).Scan(&usr.Id, &usr.Username, &usr.FirstName, &usr.LastName, &usr.CreatedAt, &usr.UpdatedAt)
+	_, err = stmt.Exec()
 	if err != nil {
-		return user.User{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return usr, nil
-
-}
-
-func (s *Storage) FetchUserOrganization(username string) (string, error) {
-	const op = "storage.postgres.FetchUserOrganization"
-	var orgId string
-
-	stmt, err := s.db.Prepare(`
-	SELECT organization_id
-	FROM organization_responsible a
-	JOIN employee b
-	ON a.user_id = b.id
-	WHERE username = $1
+	stmt, err = db.Prepare(`
+	UPDATE decisions d
+	SET status = 'Closed'
+	WHERE status <> 'Closed' AND EXISTS (
+		SELECT 1 FROM decisions o
+		WHERE o.bidId = d.bidId AND o.lotId IS NOT DISTINCT FROM d.lotId AND o.status = 'Closed'
+	);
 	`)
+
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(username).Scan(&orgId)
+	_, err = stmt.Exec()
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return orgId, nil
-}
-
-func (s *Storage) UpdateTenderStatus(tenderId, status, username string) (tender.TenderResponse, error) {
-	const op = "storage.postgres.UpdateTenderStatus"
-
-	stmt, err := s.db.Prepare(`
-	SELECT id, name, description, serviceType, status, version, createdAt, organizationId
-	FROM tender 
-	WHERE id = $1
+	stmt, err = db.Prepare(`
+	DELETE FROM decisions d
+	USING decisions o
+	WHERE o.bidId = d.bidId AND o.lotId IS NOT DISTINCT FROM d.lotId
+		AND (coalesce(o.numApproved, 0), o.id) > (coalesce(d.numApproved, 0), d.id);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var ten tender.TenderResponse
-	var organization_id string
-	err = stmt.QueryRow(tenderId).Scan(&ten.Id, &ten.Name, &ten.Description, &ten.ServiceType, &ten.Status, &ten.Version, &ten.CreatedAt, &organization_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var user_id string
-	stmt, err = s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username = $1
+	stmt, err = db.Prepare(`
+	CREATE UNIQUE INDEX IF NOT EXISTS decisions_bid_lot ON decisions(bidId, coalesce(lotId, '00000000-0000-0000-0000-000000000000'));
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer func() {
-		stmt.Close()
-	}()
-
-	err = stmt.QueryRow(username).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var trash int
-
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM organization_responsible
-	WHERE organization_id=$1 AND user_id=$2
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'Standard';
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	err = stmt.QueryRow(organization_id, user_id).Scan(&trash)
+
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO tenderHistory(tenderId, name, description, serviceType, status, version)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	stmt, err = db.Prepare(`
+	ALTER TABLE bid ADD COLUMN IF NOT EXISTS price NUMERIC(18, 2);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(ten.Id, ten.Name, ten.Description, ten.ServiceType, ten.Status, ten.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	UPDATE tender 
-	SET status = $1, version = version + 1
-	WHERE id = $2
-	RETURNING status, version
+	stmt, err = db.Prepare(`
+	ALTER TABLE bidHistory ADD COLUMN IF NOT EXISTS price NUMERIC(18, 2);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(status, ten.Id).Scan(&ten.Status, &ten.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return ten, nil
-}
-
-func (s *Storage) PatchTender(tenderId, username, name, description, serviceType string) (tender.TenderResponse, error) {
-	const op = "storage.postgres.PatchTender"
-	var result tender.TenderResponse
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS auction (
+		tenderId UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+		startPrice NUMERIC(18, 2) NOT NULL,
+		minDecrement NUMERIC(18, 2) NOT NULL,
+		roundSeconds INT NOT NULL,
+		maxRounds INT NOT NULL,
+		extensionSeconds INT NOT NULL DEFAULT 0,
+		status VARCHAR(20) NOT NULL,
+		round INT NOT NULL DEFAULT 0,
+		roundEndsAt TIMESTAMP,
+		roundBids INT NOT NULL DEFAULT 0,
+		bestPrice NUMERIC(18, 2),
+		bestBidId UUID REFERENCES bid(id) ON DELETE SET NULL,
+		proposedBidId UUID REFERENCES bid(id) ON DELETE SET NULL
+	);
+	`)
 
-	query := "UPDATE tender SET version = version + 1"
-	if name != "" {
-		query = fmt.Sprintf("%s, name = '%s'", query, name)
-	}
-	if description != "" {
-		query = fmt.Sprintf("%s, description = '%s'", query, description)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if serviceType != "" {
-		query = fmt.Sprintf("%s, serviceType = '%s'", query, serviceType)
+
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	stmt, err := s.db.Prepare(`
-	SELECT t.id, t.name, t.description, t.serviceType, t.status, t.version, t.createdAt, organizationId
-	FROM tender t
-	INNER JOIN tenderHolder th
-	ON t.id = th.tenderId
-	WHERE t.id = $1
+
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS budgetMin NUMERIC(18, 2);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var ten tender.TenderResponse
-	var organization_id string
-	err = stmt.QueryRow(tenderId).Scan(&ten.Id, &ten.Name, &ten.Description, &ten.ServiceType, &ten.Status, &ten.Version, &ten.CreatedAt, &organization_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	var user_id string
-	stmt, err = s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username = $1
+
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS budgetMax NUMERIC(18, 2);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer func() {
-		stmt.Close()
-	}()
-
-	err = stmt.QueryRow(username).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var trash int
-
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM organization_responsible
-	WHERE organization_id=$1 AND user_id=$2
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	err = stmt.QueryRow(organization_id, user_id).Scan(&trash)
+
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO tenderHistory(tenderId, name, description, serviceType, status, version)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS budgetVisibility VARCHAR(10) NOT NULL DEFAULT 'Public';
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(ten.Id, ten.Name, ten.Description, ten.ServiceType, ten.Status, ten.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	query = fmt.Sprintf("%s WHERE id = '%s' RETURNING id, name, description, serviceType, status, version, createdAt", query, tenderId)
-	stmt, err = s.db.Prepare(query)
+
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS budgetPolicy VARCHAR(10) NOT NULL DEFAULT 'Flag';
+	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow().Scan(&result.Id, &result.Name, &result.Description, &result.ServiceType, &result.Status, &result.Version, &result.CreatedAt)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	return result, nil
-}
 
-func (s *Storage) RollbackTender(tenderId, username string, version int) (tender.TenderResponse, error) {
-	const op = "storage.postgres.RollbackTender"
-	var result tender.TenderResponse
-	stmt, err := s.db.Prepare(`
-	SELECT t.id, t.name, t.description, t.serviceType, t.status, t.version, t.createdAt, organizationId
-	FROM tender t
-	INNER JOIN tenderHolder th
-	ON t.id = th.tenderId
-	WHERE t.id = $1
+	stmt, err = db.Prepare(`
+	ALTER TABLE bid ADD COLUMN IF NOT EXISTS outOfBudget BOOLEAN NOT NULL DEFAULT FALSE;
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var ten tender.TenderResponse
-	var organization_id string
-	err = stmt.QueryRow(tenderId).Scan(&ten.Id, &ten.Name, &ten.Description, &ten.ServiceType, &ten.Status, &ten.Version, &ten.CreatedAt, &organization_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	var user_id string
-	stmt, err = s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username = $1
+
+	stmt, err = db.Prepare(`
+	ALTER TABLE bidHistory ADD COLUMN IF NOT EXISTS outOfBudget BOOLEAN NOT NULL DEFAULT FALSE;
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer func() {
-		stmt.Close()
-	}()
-
-	err = stmt.QueryRow(username).Scan(&user_id)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var trash int
-
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM organization_responsible
-	WHERE organization_id=$1 AND user_id=$2
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS audit (
+		id BIGINT PRIMARY KEY,
+		createdAt TIMESTAMP NOT NULL,
+		entityType VARCHAR(20) NOT NULL,
+		entityId VARCHAR(100) NOT NULL,
+		action VARCHAR(200) NOT NULL,
+		actor VARCHAR(100) NOT NULL,
+		organizationId VARCHAR(36),
+		ip VARCHAR(64),
+		requestId VARCHAR(100),
+		before TEXT,
+		after TEXT,
+		prevHash VARCHAR(64) NOT NULL,
+		hash VARCHAR(64) NOT NULL
+	);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	err = stmt.QueryRow(organization_id, user_id).Scan(&trash)
+
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if version > int(ten.Version) || version <= 0 {
-		return tender.TenderResponse{}, ErrBadRequest
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS audit_entity ON audit(entityType, entityId);
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if version == int(ten.Version) {
-		return ten, nil
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO tenderHistory(tenderId, name, description, serviceType, status, version)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS audit_actor ON audit(actor);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(ten.Id, ten.Name, ten.Description, ten.ServiceType, ten.Status, ten.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT tenderId, name, description, serviceType, status
-	FROM tenderHistory
-	WHERE version = $1 AND tenderId = $2
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS audit_created ON audit(createdAt);
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(version, tenderId).Scan(&ten.Id, &ten.Name, &ten.Description, &ten.ServiceType, &ten.Status)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	UPDATE tender
-	SET name = $1, description = $2, serviceType = $3, status = $4, version = version + 1
-	WHERE id = $5
-	RETURNING id, name, description, status, serviceType, version, createdAt
+	stmt, err = db.Prepare(`
+	CREATE OR REPLACE FUNCTION audit_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'the audit log is append-only';
+	END;
+	$$ LANGUAGE plpgsql;
 	`)
+
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(ten.Name, ten.Description, ten.ServiceType, ten.Status, tenderId).Scan(&result.Id, &result.Name, &result.Description, &result.Status, &result.ServiceType, &result.Version, &result.CreatedAt)
+	_, err = stmt.Exec()
 	if err != nil {
-		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return result, nil
-}
-
-func (s *Storage) SaveBid(bid bids.BidRequest) (bids.BidResponse, error) {
-	const op = "storage.postgres.SaveBid"
-	var resp bids.BidResponse
-	var uuid, query string
+	stmt, err = db.Prepare(`
+	DROP TRIGGER IF EXISTS audit_append_only ON audit;
+	`)
 
-	if bid.AuthorType == "Organization" {
-		query = `SELECT id FROM organization WHERE id = $1`
-	} else {
-		query = `SELECT username FROM employee WHERE id = $1`
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err := s.db.Prepare(query)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(bid.AuthorId).Scan(&uuid)
+	stmt, err = db.Prepare(`
+	CREATE TRIGGER audit_append_only BEFORE UPDATE OR DELETE ON audit
+	FOR EACH ROW EXECUTE FUNCTION audit_append_only();
+	`)
+
 	if err != nil {
-		return bids.BidResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if bid.AuthorType == "User" {
-		_, err = s.FetchUserOrganization(uuid)
-		if err != nil {
-			return bids.BidResponse{}, ErrForbidden
-		}
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT status 
-	FROM tender
-	WHERE id = $1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS notification (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		userId UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+		event VARCHAR(50) NOT NULL,
+		tenderId UUID REFERENCES tender(id) ON DELETE CASCADE,
+		bidId UUID REFERENCES bid(id) ON DELETE CASCADE,
+		subject VARCHAR(300) NOT NULL,
+		body TEXT NOT NULL,
+		read BOOLEAN NOT NULL DEFAULT FALSE,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	var trash string
-	err = stmt.QueryRow(bid.TenderId).Scan(&trash)
+
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
-	}
-	if trash == "Closed" {
-		return bids.BidResponse{}, fmt.Errorf("the tender is closed")
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO bid(name, description, status, tenderId, authorType, authorId)
-	VALUES ($1, $2, 'Created', $3, $4, $5)
-	RETURNING id, name, status, authorType, authorId, version, createdAt
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS notification_user ON notification(userId, createdAt);
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(
-		bid.Name,
-		bid.Description,
-		bid.TenderId,
-		bid.AuthorType,
-		bid.AuthorId,
-	).Scan(
-		&resp.Id,
-		&resp.Name,
-		&resp.Status,
-		&resp.AuthorType,
-		&resp.AuthorId,
-		&resp.Version,
-		&resp.CreatedAt,
-	)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return resp, nil
-}
-
-func (s *Storage) ReadMyBids(username string, limit int, offset int) ([]bids.BidResponse, error) {
-	const op = "storage.postgres.ReadMyBids"
-	var uuid string
-	var resp = make([]bids.BidResponse, 0)
-
-	stmt, err := s.db.Prepare(`
-	SELECT id 
-	FROM employee
-	WHERE username=$1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS notificationSettings (
+		userId UUID PRIMARY KEY REFERENCES employee(id) ON DELETE CASCADE,
+		language VARCHAR(2) NOT NULL DEFAULT 'en',
+		inApp BOOLEAN NOT NULL DEFAULT TRUE,
+		email BOOLEAN NOT NULL DEFAULT FALSE,
+		emailAddress VARCHAR(200),
+		muted TEXT[] NOT NULL DEFAULT '{}'
+	);
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(username).Scan(&uuid)
+	_, err = stmt.Exec()
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt
-	FROM bid 
-	WHERE authorType='User' AND authorId=$1
-	LIMIT $2
-	OFFSET $3
+	stmt, err = db.Prepare(`
+	ALTER TABLE feedback
+		ADD COLUMN IF NOT EXISTS parentId UUID REFERENCES feedback(id) ON DELETE CASCADE,
+		ADD COLUMN IF NOT EXISTS authorId UUID,
+		ADD COLUMN IF NOT EXISTS side VARCHAR(10) NOT NULL DEFAULT 'Tender',
+		ADD COLUMN IF NOT EXISTS editedAt TIMESTAMP,
+		ADD COLUMN IF NOT EXISTS deletedAt TIMESTAMP;
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := stmt.Query(uuid, limit, offset)
+	_, err = stmt.Exec()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var bid bids.BidResponse
-	for rows.Next() {
-		err = rows.Scan(
-			&bid.Id,
-			&bid.Name,
-			&bid.Status,
-			&bid.AuthorType,
-			&bid.AuthorId,
-			&bid.Version,
-			&bid.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
-		resp = append(resp, bid)
-	}
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS feedback_bid ON feedback(bidId, createdAt);
+	`)
 
-	return resp, nil
-}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-func (s *Storage) ReadTenderBids(username string, tenderId string, limit int, offset int) ([]bids.BidResponse, error) {
-	const op = "storage.postgres.ReadTenderBids"
-	var resp []bids.BidResponse
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	stmt, err := s.db.Prepare(`
-	SELECT organizationId
-	FROM tender
-	WHERE id=$1
+	stmt, err = db.Prepare(`
+	ALTER TABLE feedback ADD COLUMN IF NOT EXISTS rating SMALLINT CHECK (rating BETWEEN 1 AND 5);
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var tName string
-	err = stmt.QueryRow(tenderId).Scan(&tName)
+	_, err = stmt.Exec()
 	if err != nil {
-		return nil, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
+	stmt, err = db.Prepare(`
+	ALTER TABLE award
+		ADD COLUMN IF NOT EXISTS deliveredOnTime BOOLEAN,
+		ADD COLUMN IF NOT EXISTS deliveryRecordedBy VARCHAR(50),
+		ADD COLUMN IF NOT EXISTS deliveryRecordedAt TIMESTAMP;
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var uuid string
-	err = stmt.QueryRow(username).Scan(&uuid)
+	_, err = stmt.Exec()
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt
-	FROM bid 
-	WHERE tenderId=$1
-	LIMIT $2
-	OFFSET $3
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS conflictRule (
+		organizationId UUID REFERENCES organization(id) ON DELETE CASCADE,
+		rule VARCHAR(50) NOT NULL,
+		action VARCHAR(10) NOT NULL,
+		PRIMARY KEY (organizationId, rule)
+	);
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := stmt.Query(tenderId, limit, offset)
+	_, err = stmt.Exec()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	flag := false
-	organization_id, err := s.FetchUserOrganization(username)
+
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS recusal (
+		tenderId UUID REFERENCES tender(id) ON DELETE CASCADE,
+		userId UUID REFERENCES employee(id) ON DELETE CASCADE,
+		reason VARCHAR(500) NOT NULL,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (tenderId, userId)
+	);
+	`)
+
 	if err != nil {
-		flag = true
-	}
-	var bid bids.BidResponse
-	for rows.Next() {
-		err = rows.Scan(
-			&bid.Id,
-			&bid.Name,
-			&bid.Status,
-			&bid.AuthorType,
-			&bid.AuthorId,
-			&bid.Version,
-			&bid.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
-		if bid.AuthorId == uuid || bid.AuthorId == organization_id || organization_id == tName {
-			resp = append(resp, bid)
-			flag = false
-		}
-	}
-	if flag {
-		return nil, ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return resp, nil
-}
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-func (s *Storage) GetBidStatus(bidId, username string) (string, error) {
-	const op = "storage.postgres.GetBidStatus"
-	var status, authorId, authorType, tenderId string
-	stmt, err := s.db.Prepare(`
-	SELECT status, authorType, authorId, tenderId
-	FROM bid
-	WHERE id=$1
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS criteria TEXT[] NOT NULL DEFAULT '{}';
 	`)
+
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(bidId).Scan(&status, &authorType, &authorId, &tenderId)
+	_, err = stmt.Exec()
 	if err != nil {
-		return "", ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if authorType == "User" {
-		var uuid string
-		stmt, err := s.db.Prepare(`
-		SELECT id
-		FROM employee
-		WHERE username=$1
-		`)
-		if err != nil {
-			return "", fmt.Errorf("%s: %w", op, err)
-		}
-
-		err = stmt.QueryRow(username).Scan(&uuid)
-		if err != nil {
-			return "", ErrUserNotFound
-		}
-		if uuid != authorId {
-			var uname string
-			stmt, err := s.db.Prepare(`
-			SELECT creatorUsername
-			FROM tenderHolder
-			WHERE tenderId=$1
-			`)
-			if err != nil {
-				return "", fmt.Errorf("%s: %w", op, err)
-			}
-
-			err = stmt.QueryRow(tenderId).Scan(&uname)
-			if err != nil {
-				return "", fmt.Errorf("%s: %w", op, err)
-			}
-			if uname != username {
-				return "", ErrForbidden
-			}
-		}
-	} else {
-		stmt, err := s.db.Prepare(`
-		SELECT username
-		FROM organization_responsible a 
-		JOIN employee b ON a.user_id=b.id
-		WHERE a.organization_id=$1
-		`)
-		if err != nil {
-			return "", fmt.Errorf("%s: %w", op, err)
-		}
-
-		rows, err := stmt.Query(authorId)
-		if err != nil {
-			return "", fmt.Errorf("%s: %w", op, err)
-		}
-		flag := false
-		for rows.Next() {
-			var uname string
-			err = rows.Scan(&uname)
-			if err != nil {
-				return "", fmt.Errorf("%s: %w", op, err)
-			}
-			if uname == username {
-				flag = true
-				break
-			}
-		}
-		if !flag {
-			var uname string
-			stmt, err := s.db.Prepare(`
-			SELECT creatorUsername
-			FROM tenderHolder
-			WHERE tenderId=$1
-			`)
-			if err != nil {
-				return "", fmt.Errorf("%s: %w", op, err)
-			}
-
-			err = stmt.QueryRow(tenderId).Scan(&uname)
-			if err != nil {
-				return "", fmt.Errorf("%s: %w", op, err)
-			}
-			if uname != username {
-				return "", ErrForbidden
-			}
-		}
-	}
-
-	return status, nil
-}
-
-func (s *Storage) ChangeBidStatus(bidId, status, username string) (bids.BidResponse, error) {
-	const op = "storage.postgres.ChangeBidStatus"
-	var bid bids.BidResponse
-	var tenderId, description string
-	stmt, err := s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt, tenderId, description
-	FROM bid
-	WHERE id=$1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS tenderTemplate (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		organizationId UUID REFERENCES organization(id) ON DELETE CASCADE,
+		title VARCHAR(100) NOT NULL,
+		spec JSONB NOT NULL,
+		createdBy VARCHAR(50) NOT NULL,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (organizationId, title)
+	);
 	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
 
-	err = stmt.QueryRow(bidId).Scan(&bid.Id, &bid.Name, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt, &tenderId, &description)
 	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if bid.AuthorType == "User" {
-		var uuid string
-		stmt, err := s.db.Prepare(`
-		SELECT id
-		FROM employee
-		WHERE username=$1
-		`)
-		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-		}
-
-		err = stmt.QueryRow(username).Scan(&uuid)
-		if err != nil {
-			return bids.BidResponse{}, ErrUserNotFound
-		}
-		if uuid != bid.AuthorId {
-			var uname1, uname2 string
-			stmt, err := s.db.Prepare(`
-			select e1.user_id as e1_id, e2.user_id as e2_id 
-			from organization_responsible e1 
-			join organization_responsible e2 
-			on e1.organization_id = e2.organization_id
-				and e1.id != e2.id 
-			where e1.user_id=$1 and e2.user_id=$2
-			`)
-			if err != nil {
-				return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-			}
-
-			err = stmt.QueryRow(bid.AuthorId, uuid).Scan(&uname1, &uname2)
-			if err != nil {
-				return bids.BidResponse{}, ErrForbidden
-			}
-		}
-	} else {
-		stmt, err := s.db.Prepare(`
-		SELECT username
-		FROM organization_responsible a 
-		JOIN employee b ON a.user_id=b.id
-		WHERE a.organization_id=$1
-		`)
-		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-		}
-
-		rows, err := stmt.Query(bid.AuthorId)
-		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-		}
-		flag := false
-		for rows.Next() {
-			var uname string
-			err = rows.Scan(&uname)
-			if err != nil {
-				return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-			}
-			if uname == username {
-				flag = true
-				break
-			}
-		}
-		if !flag {
-			return bids.BidResponse{}, ErrForbidden
-		}
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO bidHistory(bidId, name, description, status, version)
-	VALUES ($1, $2, $3, $4, $5)
+	stmt, err = db.Prepare(`
+	ALTER TABLE tender ADD COLUMN IF NOT EXISTS publishAt TIMESTAMP;
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(bid.Id, bid.Name, description, bid.Status, bid.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	UPDATE bid
-	SET version = version + 1, status=$1
-	WHERE id=$2
-	RETURNING id, name, status, authorType, authorId, version, createdAt
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS recurrence (
+		tenderId UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+		frequency VARCHAR(20) NOT NULL,
+		publish BOOLEAN NOT NULL DEFAULT FALSE,
+		nextRunAt TIMESTAMP NOT NULL,
+		lastTenderId UUID,
+		createdBy VARCHAR(50) NOT NULL,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(status, bidId).Scan(&bid.Id, &bid.Name, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return bid, nil
-}
-
-func (s *Storage) EditBid(bidId, username, name, desc string) (bids.BidResponse, error) {
-	const op = "storage.postgres.EditBid"
-	var bid bids.BidResponse
-	var tenderId, description string
-	stmt, err := s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt, tenderId, description
-	FROM bid
-	WHERE id=$1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS backgroundJob (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		kind VARCHAR(50) NOT NULL,
+		payload JSONB NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'Pending',
+		attempts INT NOT NULL DEFAULT 0,
+		maxAttempts INT NOT NULL,
+		runAt TIMESTAMP NOT NULL,
+		lockedUntil TIMESTAMP,
+		lastError TEXT,
+		createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
 
-	err = stmt.QueryRow(bidId).Scan(&bid.Id, &bid.Name, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt, &tenderId, &description)
 	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if bid.AuthorType == "User" {
-		var uuid string
-		stmt, err := s.db.Prepare(`
-		SELECT id
-		FROM employee
-		WHERE username=$1
-		`)
-		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-		}
-
-		err = stmt.QueryRow(username).Scan(&uuid)
-		if err != nil {
-			return bids.BidResponse{}, ErrUserNotFound
-		}
-		if uuid != bid.AuthorId {
-			var uname1, uname2 string
-			stmt, err := s.db.Prepare(`
-			select e1.user_id as e1_id, e2.user_id as e2_id 
-			from organization_responsible e1 
-			join organization_responsible e2 
-			on e1.organization_id = e2.organization_id
-				and e1.id != e2.id 
-			where e1.user_id=$1 and e2.user_id=$2
-			`)
-			if err != nil {
-				return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-			}
-
-			err = stmt.QueryRow(bid.AuthorId, uuid).Scan(&uname1, &uname2)
-			if err != nil {
-				return bids.BidResponse{}, ErrForbidden
-			}
-		}
-
-	} else {
-		stmt, err := s.db.Prepare(`
-		SELECT username
-		FROM organization_responsible a 
-		JOIN employee b ON a.user_id=b.id
-		WHERE a.organization_id=$1
-		`)
-		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-		}
-
-		rows, err := stmt.Query(bid.AuthorId)
-		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-		}
-		flag := false
-		for rows.Next() {
-			var uname string
-			err = rows.Scan(&uname)
-			if err != nil {
-				return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-			}
-			if uname == username {
-				flag = true
-				break
-			}
-		}
-		if !flag {
-			return bids.BidResponse{}, ErrForbidden
-		}
+	_, err = stmt.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO bidHistory(bidId, name, description, status, version)
-	VALUES ($1, $2, $3, $4, $5)
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS backgroundJob_due ON backgroundJob(kind, runAt) WHERE status IN ('Pending', 'Running');
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(bid.Id, bid.Name, description, bid.Status, bid.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	query := `
-	UPDATE bid
-	SET version = version + 1
-	`
-	if desc != "" {
-		query = fmt.Sprintf("%s, description = '%s'", query, desc)
-	}
-	if name != "" {
-		query = fmt.Sprintf("%s, name = '%s'", query, name)
-	}
+	stmt, err = db.Prepare(`
+	ALTER TABLE backgroundJob ADD COLUMN IF NOT EXISTS subjectId UUID;
+	`)
 
-	query = fmt.Sprintf("%s RETURNING id, name, status, authorType, authorId, version, createdAt", query)
-	var resp bids.BidResponse
-	stmt, err = s.db.Prepare(query)
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow().Scan(
-		&resp.Id,
-		&resp.Name,
-		&resp.Status,
-		&resp.AuthorType,
-		&resp.AuthorId,
-		&resp.Version,
-		&resp.CreatedAt,
-	)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return resp, nil
-}
-
-// func (s *Storage) SubmitDecision() (bids.BidResponse, error) {
-
-// }
-
-func (s *Storage) LeaveFeedback(bidId, bidFeedback, username string) (bids.BidResponse, error) {
-	const op = "storage.postgres.LeaveFeedback"
-	var uuid string
-	stmt, err := s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
+	stmt, err = db.Prepare(`
+	CREATE UNIQUE INDEX IF NOT EXISTS backgroundJob_pending_subject ON backgroundJob(kind, subjectId) WHERE status = 'Pending';
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(username).Scan(&uuid)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var bid bids.BidResponse
-	var tenderId, description string
-	stmt, err = s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt, tenderId, description
-	FROM bid
-	WHERE id=$1
+	stmt, err = db.Prepare(`
+	DO $$
+	BEGIN
+		IF to_regclass('scheduledjob') IS NOT NULL THEN
+			INSERT INTO backgroundJob(kind, subjectId, payload, attempts, maxAttempts, runAt, lastError, createdAt)
+			SELECT kind, subjectId, json_build_object('subjectId', subjectId, 'actor', actor), attempts, 5, runAt, lastError, createdAt
+			FROM scheduledJob
+			WHERE status IN ('Pending', 'Running')
+			ON CONFLICT DO NOTHING;
+
+			DROP TABLE scheduledJob;
+		END IF;
+	END $$;
 	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
 
-	err = stmt.QueryRow(bidId).Scan(&bid.Id, &bid.Name, &bid.Status, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt, &tenderId, &description)
 	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM organization_responsible o
-	JOIN employee e
-	ON o.user_id=e.id
-	WHERE username = $1
-	AND organization_id = (
-		SELECT t.organizationId
-		FROM tender t
-		JOIN bid b ON t.id = b.tenderId
-		WHERE b.id = $2
-	);
-  	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
-	var trash int
-	err = stmt.QueryRow(username, bidId).Scan(&trash)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, ErrForbidden
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO feedback(bidId, description)
-	VALUES ($1, $2)
-	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
-	_, err = stmt.Exec(bidId, bidFeedback)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
-	stmt, err = s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt
-	FROM bid
-	WHERE id = $1
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS idempotencyKey (
+		key VARCHAR(255) PRIMARY KEY,
+		fingerprint CHAR(64) NOT NULL,
+		status INT,
+		contentType VARCHAR(100),
+		body BYTEA,
+		createdAt TIMESTAMP NOT NULL,
+		expiresAt TIMESTAMP NOT NULL
+	);
 	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
-	var resp bids.BidResponse
-	err = stmt.QueryRow(bidId).Scan(
-		&resp.Id,
-		&resp.Name,
-		&resp.Status,
-		&resp.AuthorType,
-		&resp.AuthorId,
-		&resp.Version,
-		&resp.CreatedAt,
-	)
-	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
-	}
-	return resp, nil
-}
 
-func (s *Storage) RollbackBid(bidId, username string, version int) (bids.BidResponse, error) {
-	const op = "storage.postgres.RollbackBid"
-	var uuid string
-	stmt, err := s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
-	`)
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(username).Scan(&uuid)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, ErrUserNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	stmt, err = s.db.Prepare(`
-	SELECT id, name, description, status, version
-	FROM bid
-	WHERE id = $1
+
+	stmt, err = db.Prepare(`
+	CREATE INDEX IF NOT EXISTS idempotencyKey_expires ON idempotencyKey(expiresAt);
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var bid, resp bids.BidResponse
-	var description string
-	err = stmt.QueryRow(bidId).Scan(
-		&bid.Id,
-		&bid.Name,
-		&description,
-		&bid.Status,
-		&bid.Version,
-	)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	INSERT INTO bidHistory(bidId, name, description, status, version)
-	VALUES ($1, $2, $3, $4, $5)
+	stmt, err = db.Prepare(`
+	ALTER TABLE idempotencyKey ADD COLUMN IF NOT EXISTS lockedUntil TIMESTAMP;
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(bid.Id, bid.Name, description, bid.Status, bid.Version)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT  bidId, name, description, status
-	FROM bidHistory
-	WHERE bidId=$1 AND version=$2
+	stmt, err = db.Prepare(`
+	UPDATE idempotencyKey SET lockedUntil = createdAt + INTERVAL '5 minutes' WHERE status IS NULL AND lockedUntil IS NULL;
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(bidId, version).Scan(
-		&bid.Id,
-		&bid.Name,
-		&description,
-		&bid.Status,
-	)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	UPDATE bid
-	SET version = version + 1, name = $1, description = $2, status = $3
-	WHERE id = $4
-	RETURNING id, name, status, authorType, authorId, version, createdAt
+	stmt, err = db.Prepare(`
+	CREATE TABLE IF NOT EXISTS rateLimitBucket (
+		key VARCHAR(400) PRIMARY KEY,
+		tokens DOUBLE PRECISION NOT NULL,
+		updatedAt TIMESTAMP NOT NULL
+	);
 	`)
+
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(bid.Name, description, bid.Status, bidId).Scan(
-		&resp.Id,
-		&resp.Name,
-		&resp.Status,
-		&resp.AuthorType,
-		&resp.AuthorId,
-		&resp.Version,
-		&resp.CreatedAt,
-	)
+	_, err = stmt.Exec()
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return resp, nil
+	return &Storage{db: db, conn: db}, nil
 }
 
-func (s *Storage) GetTenderReviews(tenderId, authorUsername, requesterUsername string, limit, offset int) ([]bids.BidReviewResponse, error) {
-	const op = "storage.postgres.GetTenderReviews"
-	var resp bids.BidReviewResponse
-	var response []bids.BidReviewResponse
+// SaveTender stores a new tender in the Created status together with its
+// lots, auction settings and scheduled publication.
+func (s *Storage) SaveTender(ten tender.TenderRequest) (tender.TenderResponse, error) {
+	const op = "storage.postgres.SaveTender"
 
-	var uuid string
-	stmt, err := s.db.Prepare(`
-	SELECT id
-	FROM tender
-	WHERE id=$1
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	criteria := ten.Criteria
+	if criteria == nil {
+		criteria = []string{}
 	}
 
-	err = stmt.QueryRow(tenderId).Scan(&uuid)
-	if err != nil {
-		return nil, ErrNotFound
-	}
+	var result tender.TenderResponse
+	err := s.inTx(func(tx *Storage) error {
+		stmt, err := tx.db.Prepare(`
+		INSERT INTO tender(name, description, serviceType, status, organizationId, deadline, type,
+			budgetMin, budgetMax, currency, budgetVisibility, budgetPolicy, criteria, publishAt)
+		VALUES ($1, $2, $3, 'Created', $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13)
+		RETURNING id, name, description, status, serviceType, organizationId, version, createdAt, deadline, type,
+			budgetMin, budgetMax, coalesce(currency, ''), criteria, publishAt
+		`)
+		if err != nil {
+			return err
+		}
 
-	stmt, err = s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+		err = stmt.QueryRow(
+			ten.Name,
+			ten.Description,
+			ten.ServiceType,
+			ten.OrganizationId,
+			ten.Deadline,
+			ten.Type,
+			ten.BudgetMin,
+			ten.BudgetMax,
+			ten.Currency,
+			ten.BudgetVisibility,
+			ten.BudgetPolicy,
+			pq.Array(criteria),
+			utcOrNil(ten.PublishAt),
+		).Scan(&result.Id, &result.Name, &result.Description, &result.Status, &result.ServiceType, &result.OrganizationId, &result.Version, &result.CreatedAt, &result.Deadline, &result.Type,
+			&result.BudgetMin, &result.BudgetMax, &result.Currency, pq.Array(&result.Criteria), &result.PublishAt)
+		if err != nil {
+			return err
+		}
 
-	err = stmt.QueryRow(authorUsername).Scan(&uuid)
-	if err != nil {
-		return nil, ErrUserNotFound
-	}
+		stmt, err = tx.db.Prepare(`
+		INSERT INTO tenderHolder(tenderId, creatorUsername)
+		VALUES ($1, $2)
+		`)
+		if err != nil {
+			return err
+		}
 
-	stmt, err = s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+		_, err = stmt.Exec(result.Id, ten.CreatorUsername)
+		if err != nil {
+			return err
+		}
 
-	err = stmt.QueryRow(requesterUsername).Scan(&uuid)
-	if err != nil {
-		return nil, ErrUserNotFound
-	}
+		for _, req := range ten.Lots {
+			_, err = tx.insertLot(result.Id, req)
+			if err != nil {
+				return err
+			}
+		}
 
-	stmt, err = s.db.Prepare(`
-	select 1 from tender t join organization_responsible o on t.organizationId = o.organization_id where o.user_id=$1 and t.id=$2
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+		if ten.Auction != nil {
+			err = tx.saveAuction(result.Id, *ten.Auction)
+			if err != nil {
+				return err
+			}
+		}
+
+		if ten.PublishAt != nil {
+			_, err = tx.EnqueueJob(scheduledomain.NewJob(scheduledomain.PublishTender, result.Id, ten.CreatorUsername, *ten.PublishAt))
+			if err != nil {
+				return err
+			}
+		}
 
-	var trash int
-	err = stmt.QueryRow(uuid, tenderId).Scan(&trash)
+		return nil
+	})
 	if err != nil {
-		return nil, ErrForbidden
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	select f.id, f.description, f.createdAt from bid b 
-	inner join feedback f
-	on b.id = f.bidId
-	inner join employee e
-	on e.id=b.authorId
-	where tenderId=$1 and e.username=$4
-	limit $2
-	offset $3;
+	return result, nil
+}
+
+func (s *Storage) ReadTenders(limit, offset int, serviceType string) ([]tender.TenderResponse, error) {
+	const op = "storage.postgres.ReadTenders"
+	result := make([]tender.TenderResponse, 0)
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, description, status, serviceType, organizationId, version, createdAt, deadline,
+		CASE WHEN budgetVisibility = 'Public' THEN budgetMin END,
+		CASE WHEN budgetVisibility = 'Public' THEN budgetMax END,
+		CASE WHEN budgetVisibility = 'Public' THEN coalesce(currency, '') ELSE '' END
+	FROM tender
+	WHERE ($3 = '' OR serviceType = $3)
+	LIMIT $1
+	OFFSET $2
 	`)
+
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := stmt.Query(tenderId, limit, offset, authorUsername)
+	rows, err := stmt.Query(limit, offset, serviceType)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+
 	for rows.Next() {
-		err = rows.Scan(
-			&resp.Id,
-			&resp.Description,
-			&resp.CreatedAt,
-		)
+		var ten tender.TenderResponse
+
+		err := rows.Scan(&ten.Id, &ten.Name, &ten.Description, &ten.Status, &ten.ServiceType, &ten.OrganizationId, &ten.Version, &ten.CreatedAt, &ten.Deadline,
+			&ten.BudgetMin, &ten.BudgetMax, &ten.Currency)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		response = append(response, resp)
+
+		result = append(result, ten)
 	}
-	return response, nil
+
+	return result, nil
 }
 
-func (s *Storage) SubmitDecision(bidId, decision, username string) (bids.BidResponse, error) {
-	const op = "storage.postgres.SubmitDecision"
+func (s *Storage) FetchUser(username string) (user.User, error) {
+	const op = "storage.postgres.FetchUser"
+	var usr user.User
 
-	var bid bids.BidResponse
-	var tenderId string
 	stmt, err := s.db.Prepare(`
-	SELECT id, name, status, authorType, authorId, version, createdAt, tenderId
-	FROM bid
-	WHERE id=$1
+	SELECT id, username, coalesce(first_name, ''), coalesce(last_name, ''), created_at, updated_at
+	FROM employee
+	WHERE username=$1
 	`)
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = stmt.QueryRow(bidId).Scan(
-		&bid.Id,
-		&bid.Name,
-		&bid.Status,
-		&bid.AuthorType,
-		&bid.AuthorId,
-		&bid.Version,
-		&bid.CreatedAt,
-		&tenderId,
-	)
+	err = stmt.QueryRow(username).Scan(&usr.Id, &usr.Username, &usr.FirstName, &usr.LastName, &usr.CreatedAt, &usr.UpdatedAt)
 	if err != nil {
-		return bids.BidResponse{}, ErrNotFound
+		return user.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err = s.db.Prepare(`
-	SELECT id
-	FROM employee
-	WHERE username=$1
-	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
+	return usr, nil
 
-	var uuid string
+}
 
-	err = stmt.QueryRow(username).Scan(&uuid)
-	if err != nil {
-		return bids.BidResponse{}, ErrUserNotFound
-	}
+// UpdateTenderStatus moves the tender to status as a new version, keeping the
+// previous one in tenderHistory, and records the transition. guard is called
+// with the current status and facts under the tender row lock; its error
+// aborts the change. Publishing starts the tender's reverse auction.
+func (s *Storage) UpdateTenderStatus(tenderId, status, actor, reason string, guard func(from string, facts tenderdomain.Facts) error) (tender.TenderResponse, error) {
+	const op = "storage.postgres.UpdateTenderStatus"
 
-	stmt, err = s.db.Prepare(`
-	SELECT count(*)
-	FROM tender t
-	JOIN organization_responsible o
-	ON t.organizationId = o.organization_id
-	WHERE o.user_id = $1 AND t.id=$2
-	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
+	var ten tender.TenderResponse
+	err := s.inTx(func(tx *Storage) error {
+		err := tx.db.QueryRow(`
+		SELECT id, name, description, serviceType, status, organizationId, version, createdAt, deadline
+		FROM tender
+		WHERE id = $1
+		FOR UPDATE
+		`, tenderId).Scan(&ten.Id, &ten.Name, &ten.Description, &ten.ServiceType, &ten.Status, &ten.OrganizationId, &ten.Version, &ten.CreatedAt, &ten.Deadline)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
 
-	var tr, count int
-	err = stmt.QueryRow(uuid, tenderId).Scan(&count)
-	if err != nil || count < 1 {
-		return bids.BidResponse{}, ErrForbidden
-	}
+		facts, err := tx.ReadTenderFacts(tenderId)
+		if err != nil {
+			return err
+		}
 
-	stmt, err = s.db.Prepare(`
-	SELECT 1
-	FROM voted
-	WHERE user_id=$1
-	`)
-	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
-	}
+		from := ten.Status
+		err = guard(from, facts)
+		if err != nil {
+			return err
+		}
 
-	err = stmt.QueryRow(uuid).Scan(&tr)
-	if err == nil {
-		return bids.BidResponse{}, ErrForbidden
-	}
+		err = tx.archiveEntity(AttachmentTender, tenderId)
+		if err != nil {
+			return err
+		}
 
-	stmt, err = s.db.Prepare(`
-	SELECT numApproved, status
-	FROM decisions
-	WHERE bidId = $1
-	`)
+		stmt, err := tx.db.Prepare(`
+		UPDATE tender
+		SET status = $1, version = version + 1
+		WHERE id = $2
+		RETURNING status, version
+		`)
+		if err != nil {
+			return err
+		}
+
+		err = stmt.QueryRow(status, tenderId).Scan(&ten.Status, &ten.Version)
+		if err != nil {
+			return err
+		}
+
+		err = tx.recordTenderTransition(tenderId, from, ten.Status, actor, reason)
+		if err != nil {
+			return err
+		}
+
+		if ten.Status == tenderdomain.Published {
+			return tx.startAuction(tenderId)
+		}
+		return nil
+	})
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var numApproved int
-	var status string
-	err = stmt.QueryRow(bidId).Scan(&numApproved, &status)
-	if err != nil {
-		stmt, err = s.db.Prepare(`
-		INSERT INTO decisions(status, bidId, numApproved)
-		VALUES ($1, $2, $3)
+	return ten, nil
+}
+
+// PatchTender changes the non-empty fields of the tender as a new version,
+// keeping the previous one in tenderHistory.
+func (s *Storage) PatchTender(tenderId, name, description, serviceType string, deadline *time.Time) (tender.TenderResponse, error) {
+	const op = "storage.postgres.PatchTender"
+
+	var result tender.TenderResponse
+	err := s.inTx(func(tx *Storage) error {
+		err := tx.archiveEntity(AttachmentTender, tenderId)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.db.Prepare(`
+		UPDATE tender
+		SET name = coalesce(NULLIF($2, ''), name),
+			description = coalesce(NULLIF($3, ''), description),
+			serviceType = coalesce(NULLIF($4, ''), serviceType),
+			deadline = coalesce($5, deadline),
+			version = version + 1
+		WHERE id = $1
+		RETURNING id, name, description, serviceType, status, organizationId, version, createdAt, deadline
 		`)
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 
-		st := "Pending"
-		numApproved := 1
-		if decision == "Rejected" {
-			st = "Closed"
-			numApproved = 0
+		return stmt.QueryRow(tenderId, name, description, serviceType, utcOrNil(deadline)).Scan(
+			&result.Id, &result.Name, &result.Description, &result.ServiceType, &result.Status, &result.OrganizationId, &result.Version, &result.CreatedAt, &result.Deadline)
+	})
+	if err != nil {
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// RollbackTender restores the name, description, service type, attachments
+// and lots of an earlier version as a new version. Only the content is
+// rolled back: the status moves through its own transitions alone, so the
+// tender keeps the one it has.
+func (s *Storage) RollbackTender(tenderId string, version int) (tender.TenderResponse, error) {
+	const op = "storage.postgres.RollbackTender"
+
+	var result tender.TenderResponse
+	err := s.inTx(func(tx *Storage) error {
+		var name, description, serviceType string
+		err := tx.db.QueryRow(`
+		SELECT name, description, serviceType
+		FROM tenderHistory
+		WHERE tenderId = $1 AND version = $2
+		`, tenderId, version).Scan(&name, &description, &serviceType)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: the tender has no version %d", ErrNotFound, version)
+		}
+		if err != nil {
+			return err
 		}
 
-		_, err = stmt.Exec(st, bidId, numApproved)
+		err = tx.archiveEntity(AttachmentTender, tenderId)
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
 		}
 
-		stmt, err = s.db.Prepare(`
-		INSERT INTO voted(username, user_id, decision, bidId)
-		VALUES ($1, $2, $3, $4)
+		stmt, err := tx.db.Prepare(`
+		UPDATE tender
+		SET name = $1, description = $2, serviceType = $3, version = version + 1
+		WHERE id = $4
+		RETURNING id, name, description, status, serviceType, organizationId, version, createdAt, deadline
 		`)
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
-		_, err = stmt.Exec(username, uuid, decision, bidId)
+
+		err = stmt.QueryRow(name, description, serviceType, tenderId).Scan(
+			&result.Id, &result.Name, &result.Description, &result.Status, &result.ServiceType, &result.OrganizationId, &result.Version, &result.CreatedAt, &result.Deadline)
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
 		}
 
-		return bid, nil
-	}
+		err = tx.restoreAttachments(tenderId, version)
+		if err != nil {
+			return err
+		}
 
-	if status == "Closed" {
-		return bids.BidResponse{}, ErrForbidden
+		return tx.restoreLots(tenderId, version)
+	})
+	if err != nil {
+		return tender.TenderResponse{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if (numApproved >= count || numApproved >= 3) && decision == "Approved" {
-		stmt, err = s.db.Prepare(`
-		UPDATE decisions
-		SET numApproved = numApproved + 1, status = 'Closed'
+	return result, nil
+}
+
+// SaveBid stores a new bid in the Draft status together with the lots it
+// targets.
+func (s *Storage) SaveBid(bid bids.BidRequest) (bids.BidResponse, error) {
+	const op = "storage.postgres.SaveBid"
+
+	var resp bids.BidResponse
+	err := s.inTx(func(tx *Storage) error {
+		stmt, err := tx.db.Prepare(`
+		INSERT INTO bid(name, description, status, tenderId, authorType, authorId, price, outOfBudget)
+		VALUES ($1, $2, 'Draft', $3, $4, $5, $6, $7)
+		RETURNING id, name, description, tenderId, status, authorType, authorId, version, createdAt, price
 		`)
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 
-		_, err = stmt.Exec()
+		err = stmt.QueryRow(
+			bid.Name,
+			bid.Description,
+			bid.TenderId,
+			bid.AuthorType,
+			bid.AuthorId,
+			bid.Price,
+			bid.OutOfBudget,
+		).Scan(
+			&resp.Id,
+			&resp.Name,
+			&resp.Description,
+			&resp.TenderId,
+			&resp.Status,
+			&resp.AuthorType,
+			&resp.AuthorId,
+			&resp.Version,
+			&resp.CreatedAt,
+			&resp.Price,
+		)
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
+		}
+
+		if len(bid.LotIds) == 0 {
+			return nil
+		}
+
+		resp.LotIds = bid.LotIds
+		return tx.SaveBidLots(resp.Id, bid.LotIds)
+	})
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+const bidReturning = `RETURNING id, name, description, tenderId, status, authorType, authorId, version, createdAt, price`
+
+func scanBidResponse(row scanner) (bids.BidResponse, error) {
+	var resp bids.BidResponse
+	err := row.Scan(
+		&resp.Id,
+		&resp.Name,
+		&resp.Description,
+		&resp.TenderId,
+		&resp.Status,
+		&resp.AuthorType,
+		&resp.AuthorId,
+		&resp.Version,
+		&resp.CreatedAt,
+		&resp.Price,
+	)
+	return resp, err
+}
+
+// ChangeBidStatus moves the bid to status as a new version, keeping the
+// previous one in bidHistory. guard is called with the current status under
+// the bid row lock; its error aborts the change.
+func (s *Storage) ChangeBidStatus(bidId, status string, guard func(from string) error) (bids.BidResponse, error) {
+	const op = "storage.postgres.ChangeBidStatus"
+
+	var resp bids.BidResponse
+	err := s.inTx(func(tx *Storage) error {
+		var from string
+		err := tx.db.QueryRow(`SELECT status FROM bid WHERE id = $1 FOR UPDATE`, bidId).Scan(&from)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
 		}
-		stmt, err = s.db.Prepare(`
-		UPDATE tender
-		SET status='Closed'
-		WHERE id=$1
-		`)
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 
-		_, err = stmt.Exec(tenderId)
+		err = guard(from)
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
 		}
 
-		stmt, err = s.db.Prepare(`
-		INSERT INTO voted(username, user_id, decision, bidId)
-		VALUES ($1, $2, $3, $4)
-		`)
+		err = tx.archiveEntity(AttachmentBid, bidId)
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
-		_, err = stmt.Exec(username, uuid, decision, bidId)
+
+		resp, err = scanBidResponse(tx.db.QueryRow(`
+		UPDATE bid
+		SET status = $1, version = version + 1
+		WHERE id = $2
+		`+bidReturning, status, bidId))
+		return err
+	})
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// EditBid changes the non-empty name or description and, when price is set,
+// the price of a bid as a new version, keeping the previous one in
+// bidHistory. outOfBudget is only stored together with a new price.
+func (s *Storage) EditBid(bidId, name, desc string, price *float64, outOfBudget bool) (bids.BidResponse, error) {
+	const op = "storage.postgres.EditBid"
+
+	var resp bids.BidResponse
+	err := s.inTx(func(tx *Storage) error {
+		err := tx.archiveEntity(AttachmentBid, bidId)
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
 		}
 
-		return bid, nil
+		resp, err = scanBidResponse(tx.db.QueryRow(`
+		UPDATE bid
+		SET name = coalesce(NULLIF($2, ''), name),
+			description = coalesce(NULLIF($3, ''), description),
+			price = coalesce($4, price),
+			outOfBudget = CASE WHEN $4::numeric IS NULL THEN outOfBudget ELSE $5 END,
+			version = version + 1
+		WHERE id = $1
+		`+bidReturning, bidId, name, desc, price, outOfBudget))
+		return err
+	})
+	if err != nil {
+		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if decision == "Rejected" {
-		stmt, err = s.db.Prepare(`
-		UPDATE decisions
-		SET status = 'Closed'
-		`)
+	return resp, nil
+}
+
+// RollbackBid restores the name, description, price and attachments of an
+// earlier version as a new version. The status is left as it is, since going
+// back to an earlier status would bypass its transitions. guard is called
+// with the current and the restored price under the bid row lock and may
+// refuse the rollback.
+func (s *Storage) RollbackBid(bidId string, version int, guard func(current, restored *float64) error) (bids.BidResponse, error) {
+	const op = "storage.postgres.RollbackBid"
+
+	var resp bids.BidResponse
+	err := s.inTx(func(tx *Storage) error {
+		var current *float64
+		err := tx.db.QueryRow(`SELECT price FROM bid WHERE id = $1 FOR UPDATE`, bidId).Scan(&current)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 
-		_, err = stmt.Exec()
+		var name, description string
+		var price *float64
+		var outOfBudget bool
+		err = tx.db.QueryRow(`
+		SELECT name, description, price, outOfBudget
+		FROM bidHistory
+		WHERE bidId = $1 AND version = $2
+		`, bidId, version).Scan(&name, &description, &price, &outOfBudget)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: the bid has no version %d", ErrNotFound, version)
+		}
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
 		}
 
-		stmt, err = s.db.Prepare(`
-		INSERT INTO voted(username, user_id, decision, bidId)
-		VALUES ($1, $2, $3, $4)
-		`)
+		err = guard(current, price)
 		if err != nil {
-			return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
-		_, err = stmt.Exec(username, uuid, decision, bidId)
+
+		err = tx.archiveEntity(AttachmentBid, bidId)
 		if err != nil {
-			return bids.BidResponse{}, ErrBadRequest
+			return err
 		}
 
-		return bid, nil
-	}
+		resp, err = scanBidResponse(tx.db.QueryRow(`
+		UPDATE bid
+		SET name = $1, description = $2, price = $3, outOfBudget = $4, version = version + 1
+		WHERE id = $5
+		`+bidReturning, name, description, price, outOfBudget, bidId))
+		if err != nil {
+			return err
+		}
 
-	stmt, err = s.db.Prepare(`
-	UPDATE decisions
-	SET numApproved = numApproved + 1
-	`)
+		return tx.restoreAttachments(bidId, version)
+	})
 	if err != nil {
 		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec()
-	if err != nil {
-		return bids.BidResponse{}, ErrBadRequest
-	}
+	return resp, nil
+}
 
-	stmt, err = s.db.Prepare(`
-		INSERT INTO voted(username, user_id, decision, bidId)
-		VALUES ($1, $2, $3, $4)
-		`)
+// DecideBid moves a bid into a status set by the server, Approved or Rejected
+// after voting or Submitted when it wins a reverse auction, keeping the
+// previous version in bidHistory.
+func (s *Storage) DecideBid(bid *bids.BidResponse, decision string) error {
+	err := s.archiveEntity(AttachmentBid, bid.Id)
 	if err != nil {
-		return bids.BidResponse{}, fmt.Errorf("%s: %w", op, err)
+		return err
 	}
-	_, err = stmt.Exec(username, uuid, decision, bidId)
+
+	stmt, err := s.db.Prepare(`
+	UPDATE bid
+	SET status = $1, version = version + 1
+	WHERE id = $2
+	RETURNING status, version
+	`)
 	if err != nil {
-		return bids.BidResponse{}, ErrBadRequest
+		return err
 	}
 
-	return bid, nil
+	return stmt.QueryRow(decision, bid.Id).Scan(&bid.Status, &bid.Version)
 }
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"tender_system/internal/domain/ratelimit"
+	"time"
+)
+
+// bucketRetention is how long idle rate limit buckets are kept. It only has
+// to exceed the time any configured bucket takes to refill.
+const bucketRetention = 24 * time.Hour
+
+// RateLimitStore keeps rate limit buckets in the rateLimitBucket table, so
+// replicas sharing the database share the limits.
+type RateLimitStore struct {
+	db *sql.DB
+}
+
+func (s *Storage) RateLimitStore() *RateLimitStore {
+	return &RateLimitStore{db: s.conn}
+}
+
+// Take takes a token from every bucket of the charges or from none, locking
+// the bucket rows while they are updated. Rows are locked in key order so
+// that concurrent requests sharing buckets cannot deadlock.
+func (r *RateLimitStore) Take(charges []ratelimit.Charge, now time.Time) (bool, time.Duration, error) {
+	const op = "storage.postgres.RateLimitStore.Take"
+
+	charges = slices.Clone(charges)
+	slices.SortFunc(charges, func(a, b ratelimit.Charge) int { return strings.Compare(a.Key, b.Key) })
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	buckets := make([]ratelimit.Bucket, len(charges))
+	limits := make([]ratelimit.Limit, len(charges))
+	for i, c := range charges {
+		full := ratelimit.Full(c.Limit, now.UTC())
+		_, err = tx.Exec(`
+		INSERT INTO rateLimitBucket(key, tokens, updatedAt)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING
+		`, c.Key, full.Tokens, full.UpdatedAt)
+		if err != nil {
+			return false, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = tx.QueryRow(`SELECT tokens, updatedAt FROM rateLimitBucket WHERE key = $1 FOR UPDATE`, c.Key).Scan(&buckets[i].Tokens, &buckets[i].UpdatedAt)
+		if err != nil {
+			return false, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		limits[i] = c.Limit
+	}
+
+	next, allowed, wait := ratelimit.TakeAll(buckets, limits, now.UTC())
+
+	for i, c := range charges {
+		_, err = tx.Exec(`UPDATE rateLimitBucket SET tokens = $2, updatedAt = $3 WHERE key = $1`, c.Key, next[i].Tokens, next[i].UpdatedAt)
+		if err != nil {
+			return false, 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, wait, nil
+}
+
+// Purge removes the buckets idle long enough to be full.
+func (r *RateLimitStore) Purge(now time.Time) error {
+	const op = "storage.postgres.RateLimitStore.Purge"
+
+	stmt, err := r.db.Prepare(`DELETE FROM rateLimitBucket WHERE updatedAt < $1`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(now.Add(-bucketRetention).UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
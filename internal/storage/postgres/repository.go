@@ -0,0 +1,453 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"tender_system/internal/models/bids"
+	"tender_system/internal/models/tender"
+
+	"github.com/lib/pq"
+)
+
+// The methods below are the plain data access used by the service layer.
+// They do not check permissions or business rules.
+
+func (s *Storage) GetTender(tenderId string) (tender.Tender, error) {
+	const op = "storage.postgres.GetTender"
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, coalesce(description, ''), serviceType, status, type, organizationId, version, createdAt, deadline,
+		budgetMin, budgetMax, coalesce(currency, ''), budgetVisibility, budgetPolicy, criteria, publishAt
+	FROM tender
+	WHERE id = $1
+	`)
+	if err != nil {
+		return tender.Tender{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var ten tender.Tender
+	err = stmt.QueryRow(tenderId).Scan(
+		&ten.Id,
+		&ten.Name,
+		&ten.Description,
+		&ten.ServiceType,
+		&ten.Status,
+		&ten.Type,
+		&ten.OrganizationId,
+		&ten.Version,
+		&ten.CreatedAt,
+		&ten.Deadline,
+		&ten.Budget.Min,
+		&ten.Budget.Max,
+		&ten.Budget.Currency,
+		&ten.Budget.Visibility,
+		&ten.Budget.Policy,
+		pq.Array(&ten.Criteria),
+		&ten.PublishAt,
+	)
+	if err != nil {
+		return tender.Tender{}, ErrNotFound
+	}
+
+	return ten, nil
+}
+
+func (s *Storage) GetBid(bidId string) (bids.Bid, error) {
+	const op = "storage.postgres.GetBid"
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, status, coalesce(description, ''), tenderId, authorType, authorId, price, version, createdAt
+	FROM bid
+	WHERE id = $1
+	`)
+	if err != nil {
+		return bids.Bid{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var bid bids.Bid
+	err = stmt.QueryRow(bidId).Scan(
+		&bid.Id,
+		&bid.Name,
+		&bid.Status,
+		&bid.Description,
+		&bid.TenderId,
+		&bid.AuthorType,
+		&bid.AuthorId,
+		&bid.Price,
+		&bid.Version,
+		&bid.CreatedAt,
+	)
+	if err != nil {
+		return bids.Bid{}, ErrNotFound
+	}
+
+	return bid, nil
+}
+
+// LockBid reads the bid holding the row lock of its tender and then of the
+// bid itself, the order AwardBid takes them in, until the transaction of s
+// ends.
+func (s *Storage) LockBid(bidId string) (bids.Bid, error) {
+	const op = "storage.postgres.LockBid"
+
+	_, err := s.db.Exec(`
+	SELECT t.id
+	FROM tender t
+	JOIN bid b ON b.tenderId = t.id
+	WHERE b.id = $1
+	FOR UPDATE OF t
+	`, bidId)
+	if err != nil {
+		return bids.Bid{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var bid bids.Bid
+	err = s.db.QueryRow(`
+	SELECT id, name, status, coalesce(description, ''), tenderId, authorType, authorId, price, version, createdAt
+	FROM bid
+	WHERE id = $1
+	FOR UPDATE
+	`, bidId).Scan(
+		&bid.Id,
+		&bid.Name,
+		&bid.Status,
+		&bid.Description,
+		&bid.TenderId,
+		&bid.AuthorType,
+		&bid.AuthorId,
+		&bid.Price,
+		&bid.Version,
+		&bid.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return bids.Bid{}, ErrNotFound
+	}
+	if err != nil {
+		return bids.Bid{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bid, nil
+}
+
+func (s *Storage) IsOrganizationResponsible(organizationId, userId string) (bool, error) {
+	const op = "storage.postgres.IsOrganizationResponsible"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM organization_responsible
+	WHERE organization_id = $1 AND user_id = $2
+	`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(organizationId, userId).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+func (s *Storage) CountOrganizationResponsibles(organizationId string) (int, error) {
+	const op = "storage.postgres.CountOrganizationResponsibles"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM organization_responsible
+	WHERE organization_id = $1
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(organizationId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (s *Storage) ReadAuthorFeedback(tenderId, authorId string, limit, offset int) ([]bids.BidReviewResponse, error) {
+	const op = "storage.postgres.ReadAuthorFeedback"
+	response := make([]bids.BidReviewResponse, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT f.id, f.description, f.rating, f.createdAt
+	FROM bid b
+	JOIN feedback f
+	ON b.id = f.bidId
+	WHERE b.tenderId = $1 AND b.authorType = 'User' AND b.authorId = $2 AND f.deletedAt IS NULL
+	ORDER BY f.createdAt
+	LIMIT $3
+	OFFSET $4
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId, authorId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resp bids.BidReviewResponse
+		err = rows.Scan(&resp.Id, &resp.Description, &resp.Rating, &resp.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		response = append(response, resp)
+	}
+
+	return response, nil
+}
+
+// ReadDecision returns the running decision on a bid, or on one lot of the
+// bid when lotId is not empty.
+func (s *Storage) ReadDecision(bidId, lotId string) (bids.Decision, error) {
+	const op = "storage.postgres.ReadDecision"
+
+	stmt, err := s.db.Prepare(`
+	SELECT bidId, coalesce(lotId::text, ''), status, numApproved
+	FROM decisions
+	WHERE bidId = $1 AND lotId IS NOT DISTINCT FROM NULLIF($2, '')::uuid
+	`)
+	if err != nil {
+		return bids.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var dec bids.Decision
+	err = stmt.QueryRow(bidId, lotId).Scan(&dec.BidId, &dec.LotId, &dec.Status, &dec.NumApproved)
+	if err != nil {
+		return bids.Decision{}, ErrNotFound
+	}
+
+	return dec, nil
+}
+
+func (s *Storage) HasVoted(bidId, lotId, userId string) (bool, error) {
+	const op = "storage.postgres.HasVoted"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM voted
+	WHERE bidId = $1 AND lotId IS NOT DISTINCT FROM NULLIF($2, '')::uuid AND user_id = $3
+	`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(bidId, lotId, userId).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+// SaveVote stores the vote and updates the running decision for the bid,
+// creating a Pending decision on the first vote. A second vote of the user
+// on the same bid and lot is a conflict.
+func (s *Storage) SaveVote(bidId, lotId, userId, username, decision string) (bids.Decision, error) {
+	const op = "storage.postgres.SaveVote"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO voted(username, user_id, decision, bidId, lotId)
+	VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid)
+	`)
+	if err != nil {
+		return bids.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(username, userId, decision, bidId, lotId)
+	if isUniqueViolation(err) {
+		return bids.Decision{}, fmt.Errorf("%w: the user has already voted", ErrConflict)
+	}
+	if err != nil {
+		return bids.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	approved := 0
+	if decision == "Approved" {
+		approved = 1
+	}
+
+	stmt, err = s.db.Prepare(`
+	INSERT INTO decisions(status, bidId, lotId, numApproved)
+	VALUES ('Pending', $1, NULLIF($2, '')::uuid, $3)
+	ON CONFLICT (bidId, coalesce(lotId, '00000000-0000-0000-0000-000000000000'))
+	DO UPDATE SET numApproved = decisions.numApproved + EXCLUDED.numApproved
+	RETURNING bidId, coalesce(lotId::text, ''), status, numApproved
+	`)
+	if err != nil {
+		return bids.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var dec bids.Decision
+	err = stmt.QueryRow(bidId, lotId, approved).Scan(&dec.BidId, &dec.LotId, &dec.Status, &dec.NumApproved)
+	if err != nil {
+		return bids.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dec, nil
+}
+
+func (s *Storage) CloseDecision(bidId, lotId string) error {
+	const op = "storage.postgres.CloseDecision"
+
+	stmt, err := s.db.Prepare(`
+	UPDATE decisions
+	SET status = 'Closed'
+	WHERE bidId = $1 AND lotId IS NOT DISTINCT FROM NULLIF($2, '')::uuid
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(bidId, lotId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListTenderBids pages through the bids of a tender. A nil authorIds lists
+// every bid, otherwise only bids by one of the given authors. A non-empty
+// lotId only lists bids targeting that lot.
+func (s *Storage) ListTenderBids(tenderId string, authorIds []string, lotId string, limit, offset int) ([]bids.BidResponse, error) {
+	const op = "storage.postgres.ListTenderBids"
+	resp := make([]bids.BidResponse, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, description, tenderId, status, authorType, authorId, price, version, createdAt,
+		coalesce((SELECT array_agg(bl.lotId::text ORDER BY bl.lotId) FROM bidLot bl WHERE bl.bidId = bid.id), '{}')
+	FROM bid
+	WHERE tenderId = $1 AND ($2::uuid[] IS NULL OR authorId = ANY($2))
+		AND ($3 = '' OR EXISTS (SELECT 1 FROM bidLot bl WHERE bl.bidId = bid.id AND bl.lotId::text = $3))
+	ORDER BY name
+	LIMIT $4
+	OFFSET $5
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId, pq.Array(authorIds), lotId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bid bids.BidResponse
+		err = rows.Scan(
+			&bid.Id,
+			&bid.Name,
+			&bid.Description,
+			&bid.TenderId,
+			&bid.Status,
+			&bid.AuthorType,
+			&bid.AuthorId,
+			&bid.Price,
+			&bid.Version,
+			&bid.CreatedAt,
+			pq.Array(&bid.LotIds),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		resp = append(resp, bid)
+	}
+
+	return resp, nil
+}
+
+func (s *Storage) ListAuthorBids(authorIds []string, limit, offset int) ([]bids.BidResponse, error) {
+	const op = "storage.postgres.ListAuthorBids"
+	resp := make([]bids.BidResponse, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, name, description, tenderId, status, authorType, authorId, price, version, createdAt
+	FROM bid
+	WHERE authorId = ANY($1::uuid[])
+	ORDER BY name
+	LIMIT $2
+	OFFSET $3
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(pq.Array(authorIds), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bid bids.BidResponse
+		err = rows.Scan(
+			&bid.Id,
+			&bid.Name,
+			&bid.Description,
+			&bid.TenderId,
+			&bid.Status,
+			&bid.AuthorType,
+			&bid.AuthorId,
+			&bid.Price,
+			&bid.Version,
+			&bid.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		resp = append(resp, bid)
+	}
+
+	return resp, nil
+}
+
+// ListCreatorTenders pages through the tenders created by username, only
+// those of organizationId unless it is empty.
+func (s *Storage) ListCreatorTenders(username, organizationId string, limit, offset int) ([]tender.TenderResponse, error) {
+	const op = "storage.postgres.ListCreatorTenders"
+	result := make([]tender.TenderResponse, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT t.id, name, description, status, serviceType, t.organizationId, version, createdAt, deadline
+	FROM tender t
+	INNER JOIN tenderHolder th
+	ON th.tenderId = t.id
+	WHERE creatorUsername = $1 AND ($2 = '' OR t.organizationId::text = $2)
+	ORDER BY name
+	LIMIT $3
+	OFFSET $4
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(username, organizationId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ten tender.TenderResponse
+		err := rows.Scan(&ten.Id, &ten.Name, &ten.Description, &ten.Status, &ten.ServiceType, &ten.OrganizationId, &ten.Version, &ten.CreatedAt, &ten.Deadline)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, ten)
+	}
+
+	return result, nil
+}
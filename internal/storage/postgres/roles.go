@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"fmt"
+	"tender_system/internal/authz"
+	"tender_system/internal/models/user"
+
+	"github.com/lib/pq"
+)
+
+// membersQuery yields (user_id, role) pairs of an organization. Responsibles
+// are implicit Owners, explicit grants live in organization_role.
+const membersQuery = `
+	SELECT user_id, 'Owner' AS role
+	FROM organization_responsible
+	WHERE organization_id = $1
+	UNION
+	SELECT user_id, role
+	FROM organization_role
+	WHERE organization_id = $1
+`
+
+func (s *Storage) ReadMemberRoles(organizationId, userId string) ([]string, error) {
+	const op = "storage.postgres.ReadMemberRoles"
+	roles := make([]string, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT m.role
+	FROM (` + membersQuery + `) m
+	WHERE m.user_id = $2
+	ORDER BY m.role
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(organizationId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role string
+		err = rows.Scan(&role)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+func (s *Storage) ListOrganizationMembers(organizationId string) ([]user.Member, error) {
+	const op = "storage.postgres.ListOrganizationMembers"
+	members := make([]user.Member, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT e.id, e.username, array_agg(m.role ORDER BY m.role)
+	FROM (` + membersQuery + `) m
+	JOIN employee e
+	ON e.id = m.user_id
+	GROUP BY e.id, e.username
+	ORDER BY e.username
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member user.Member
+		err = rows.Scan(&member.UserId, &member.Username, pq.Array(&member.Roles))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+func (s *Storage) GrantRole(organizationId, userId string, role authz.Role) error {
+	const op = "storage.postgres.GrantRole"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO organization_role(organization_id, user_id, role)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(organizationId, userId, string(role))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeRole(organizationId, userId string, role authz.Role) error {
+	const op = "storage.postgres.RevokeRole"
+
+	stmt, err := s.db.Prepare(`
+	DELETE FROM organization_role
+	WHERE organization_id = $1 AND user_id = $2 AND role = $3
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(organizationId, userId, string(role))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) ReadUserOrganizations(userId string) ([]string, error) {
+	const op = "storage.postgres.ReadUserOrganizations"
+	result := make([]string, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT organization_id
+	FROM organization_responsible
+	WHERE user_id = $1
+	UNION
+	SELECT organization_id
+	FROM organization_role
+	WHERE user_id = $1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orgId string
+		err = rows.Scan(&orgId)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, orgId)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) OrganizationExists(organizationId string) (bool, error) {
+	const op = "storage.postgres.OrganizationExists"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM organization
+	WHERE id::text = $1
+	`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(organizationId).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+func (s *Storage) EmployeeExists(userId string) (bool, error) {
+	const op = "storage.postgres.EmployeeExists"
+
+	stmt, err := s.db.Prepare(`
+	SELECT count(*)
+	FROM employee
+	WHERE id::text = $1
+	`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = stmt.QueryRow(userId).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+// ReadUserMemberships lists every organization userId belongs to together
+// with the roles held there.
+func (s *Storage) ReadUserMemberships(userId string) ([]user.Membership, error) {
+	const op = "storage.postgres.ReadUserMemberships"
+	result := make([]user.Membership, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT o.id, o.name, coalesce(o.type::text, ''), array_agg(m.role ORDER BY m.role)
+	FROM (
+		SELECT organization_id, 'Owner' AS role
+		FROM organization_responsible
+		WHERE user_id = $1
+		UNION
+		SELECT organization_id, role
+		FROM organization_role
+		WHERE user_id = $1
+	) m
+	JOIN organization o
+	ON o.id = m.organization_id
+	GROUP BY o.id, o.name, o.type
+	ORDER BY o.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var membership user.Membership
+		err = rows.Scan(&membership.OrganizationId, &membership.OrganizationName, &membership.OrganizationType, pq.Array(&membership.Roles))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, membership)
+	}
+
+	return result, nil
+}
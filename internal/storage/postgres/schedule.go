@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"tender_system/internal/models/schedule"
+	"time"
+)
+
+// SchedulerLock is the advisory lock key held by the replica running the
+// scheduler's periodic tasks.
+const SchedulerLock = 7_250_002
+
+// SetPublishAt changes when the tender is published automatically, nil
+// meaning never.
+func (s *Storage) SetPublishAt(tenderId string, publishAt *time.Time) error {
+	const op = "storage.postgres.SetPublishAt"
+
+	stmt, err := s.db.Prepare(`UPDATE tender SET publishAt = $2 WHERE id = $1`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(tenderId, utcOrNil(publishAt))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+const recurrenceColumns = `tenderId, frequency, publish, nextRunAt, coalesce(lastTenderId::text, ''), createdBy, createdAt`
+
+func scanRecurrence(row scanner) (schedule.Recurrence, error) {
+	var r schedule.Recurrence
+	err := row.Scan(&r.TenderId, &r.Frequency, &r.Publish, &r.NextRunAt, &r.LastTenderId, &r.CreatedBy, &r.CreatedAt)
+	return r, err
+}
+
+func (s *Storage) GetRecurrence(tenderId string) (schedule.Recurrence, error) {
+	const op = "storage.postgres.GetRecurrence"
+
+	stmt, err := s.db.Prepare(`SELECT ` + recurrenceColumns + ` FROM recurrence WHERE tenderId = $1`)
+	if err != nil {
+		return schedule.Recurrence{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	r, err := scanRecurrence(stmt.QueryRow(tenderId))
+	if err != nil {
+		return schedule.Recurrence{}, ErrNotFound
+	}
+
+	return r, nil
+}
+
+// SaveRecurrence creates the tender's recurrence or replaces its settings.
+func (s *Storage) SaveRecurrence(r schedule.Recurrence) (schedule.Recurrence, error) {
+	const op = "storage.postgres.SaveRecurrence"
+
+	stmt, err := s.db.Prepare(`
+	INSERT INTO recurrence(tenderId, frequency, publish, nextRunAt, createdBy)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (tenderId) DO UPDATE SET
+		frequency = EXCLUDED.frequency,
+		publish = EXCLUDED.publish,
+		nextRunAt = EXCLUDED.nextRunAt,
+		createdBy = EXCLUDED.createdBy
+	RETURNING ` + recurrenceColumns)
+	if err != nil {
+		return schedule.Recurrence{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	r, err = scanRecurrence(stmt.QueryRow(r.TenderId, r.Frequency, r.Publish, r.NextRunAt.UTC(), r.CreatedBy))
+	if err != nil {
+		return schedule.Recurrence{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return r, nil
+}
+
+// AdvanceRecurrence records the tender spawned last and when the next one is
+// due.
+func (s *Storage) AdvanceRecurrence(tenderId, lastTenderId string, nextRunAt time.Time) error {
+	const op = "storage.postgres.AdvanceRecurrence"
+
+	stmt, err := s.db.Prepare(`UPDATE recurrence SET lastTenderId = $2, nextRunAt = $3 WHERE tenderId = $1`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(tenderId, lastTenderId, nextRunAt.UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteRecurrence(tenderId string) error {
+	const op = "storage.postgres.DeleteRecurrence"
+
+	stmt, err := s.db.Prepare(`DELETE FROM recurrence WHERE tenderId = $1`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(tenderId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Lease is leadership among the server replicas, held as a session-level
+// advisory lock on a dedicated connection. Postgres releases the lock when
+// the connection drops, so a replica that dies loses leadership at once.
+type Lease struct {
+	db   *sql.DB
+	key  int64
+	conn *sql.Conn
+}
+
+// NewLease returns a lease on the advisory lock key.
+func (s *Storage) NewLease(key int64) *Lease {
+	return &Lease{db: s.conn, key: key}
+}
+
+// Acquire reports whether this replica leads, trying to take the lock when it
+// does not yet hold it and checking the connection when it does.
+func (l *Lease) Acquire(ctx context.Context) (bool, error) {
+	const op = "storage.postgres.Lease.Acquire"
+
+	if l.conn != nil {
+		_, err := l.conn.ExecContext(ctx, `SELECT 1`)
+		if err != nil {
+			l.discard()
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&locked)
+	if err != nil || !locked {
+		conn.Close()
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release gives up leadership.
+func (l *Lease) Release() {
+	if l.conn == nil {
+		return
+	}
+
+	_, err := l.conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, l.key)
+	if err != nil {
+		l.discard()
+		return
+	}
+	l.conn.Close()
+	l.conn = nil
+}
+
+// discard closes the lease's connection instead of returning it to the pool,
+// where it would keep holding the lock for whoever borrows it next. Postgres
+// releases the lock with the session.
+func (l *Lease) discard() {
+	l.conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"fmt"
+	"tender_system/internal/models/supplier"
+)
+
+// ReadSupplierStats counts the bids, decisions, ratings and deliveries of a
+// bid author across all tenders. Drafts and deleted feedback are left out.
+func (s *Storage) ReadSupplierStats(authorType, authorId string) (supplier.Stats, error) {
+	const op = "storage.postgres.ReadSupplierStats"
+
+	stmt, err := s.db.Prepare(`
+	SELECT
+		(SELECT count(*) FROM bid b
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND b.status NOT IN ('Draft', 'Created')),
+		(SELECT count(DISTINCT b.tenderId) FROM bid b
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND b.status NOT IN ('Draft', 'Created')),
+		(SELECT count(*) FROM bid b
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND b.status IN ('Approved', 'Rejected')),
+		(SELECT count(*) FROM bid b
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND b.status = 'Approved'),
+		(SELECT count(f.rating) FROM feedback f JOIN bid b ON b.id = f.bidId
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND f.deletedAt IS NULL),
+		(SELECT coalesce(sum(f.rating), 0) FROM feedback f JOIN bid b ON b.id = f.bidId
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND f.deletedAt IS NULL),
+		(SELECT count(a.deliveredOnTime) FROM award a JOIN bid b ON b.id = a.bidId
+			WHERE b.authorType = $1 AND b.authorId::text = $2),
+		(SELECT count(*) FROM award a JOIN bid b ON b.id = a.bidId
+			WHERE b.authorType = $1 AND b.authorId::text = $2 AND a.deliveredOnTime),
+		coalesce(
+			(SELECT o.name FROM organization o WHERE $1 = 'Organization' AND o.id::text = $2),
+			(SELECT e.username FROM employee e WHERE $1 = 'User' AND e.id::text = $2),
+			''
+		)
+	`)
+	if err != nil {
+		return supplier.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := supplier.Stats{AuthorType: authorType, AuthorId: authorId}
+	err = stmt.QueryRow(authorType, authorId).Scan(
+		&result.Bids,
+		&result.Tenders,
+		&result.Decided,
+		&result.Won,
+		&result.Ratings,
+		&result.RatingSum,
+		&result.Deliveries,
+		&result.OnTimeDeliveries,
+		&result.Name,
+	)
+	if err != nil {
+		return supplier.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if result.Name == "" {
+		return supplier.Stats{}, ErrNotFound
+	}
+
+	return result, nil
+}
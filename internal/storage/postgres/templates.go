@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"tender_system/internal/models/template"
+)
+
+const templateColumns = `id, organizationId, title, spec, createdBy, createdAt, updatedAt`
+
+func scanTemplate(row scanner) (template.Template, error) {
+	var t template.Template
+	var spec []byte
+	err := row.Scan(&t.Id, &t.OrganizationId, &t.Title, &spec, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return template.Template{}, err
+	}
+	err = json.Unmarshal(spec, &t.Spec)
+	return t, err
+}
+
+func (s *Storage) ListTemplates(organizationId string) ([]template.Template, error) {
+	const op = "storage.postgres.ListTemplates"
+	result := make([]template.Template, 0)
+
+	stmt, err := s.db.Prepare(`SELECT ` + templateColumns + ` FROM tenderTemplate WHERE organizationId = $1 ORDER BY title`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) GetTemplate(organizationId, templateId string) (template.Template, error) {
+	const op = "storage.postgres.GetTemplate"
+
+	stmt, err := s.db.Prepare(`SELECT ` + templateColumns + ` FROM tenderTemplate WHERE organizationId = $1 AND id::text = $2`)
+	if err != nil {
+		return template.Template{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	t, err := scanTemplate(stmt.QueryRow(organizationId, templateId))
+	if err != nil {
+		return template.Template{}, ErrNotFound
+	}
+
+	return t, nil
+}
+
+// SaveTemplate stores a new template, or replaces the title and spec of an
+// existing one when t.Id is set. Titles are unique within an organization.
+func (s *Storage) SaveTemplate(t template.Template) (template.Template, error) {
+	const op = "storage.postgres.SaveTemplate"
+
+	spec, err := json.Marshal(t.Spec)
+	if err != nil {
+		return template.Template{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+	INSERT INTO tenderTemplate(organizationId, title, spec, createdBy)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (organizationId, title) DO NOTHING
+	RETURNING ` + templateColumns
+	args := []any{t.OrganizationId, t.Title, spec, t.CreatedBy}
+	if t.Id != "" {
+		query = `
+		UPDATE tenderTemplate
+		SET title = $3, spec = $4, updatedAt = CURRENT_TIMESTAMP
+		WHERE organizationId = $1 AND id::text = $2
+			AND NOT EXISTS (SELECT 1 FROM tenderTemplate o WHERE o.organizationId = $1 AND o.title = $3 AND o.id::text != $2)
+		RETURNING ` + templateColumns
+		args = []any{t.OrganizationId, t.Id, t.Title, spec}
+	}
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return template.Template{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := scanTemplate(stmt.QueryRow(args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return template.Template{}, fmt.Errorf("%w: a template titled %q already exists", ErrConflict, t.Title)
+	}
+	if err != nil {
+		return template.Template{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) DeleteTemplate(organizationId, templateId string) error {
+	const op = "storage.postgres.DeleteTemplate"
+
+	stmt, err := s.db.Prepare(`DELETE FROM tenderTemplate WHERE organizationId = $1 AND id::text = $2`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.Exec(organizationId, templateId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"tender_system/internal/models/transfer"
+
+	"github.com/lib/pq"
+)
+
+// EachTender streams the tenders matching the filter to fn, oldest first,
+// and stops at the first error fn returns.
+func (s *Storage) EachTender(f transfer.TenderFilter, fn func(transfer.Tender) error) error {
+	const op = "storage.postgres.EachTender"
+
+	stmt, err := s.db.Prepare(`
+	SELECT t.id, t.name, coalesce(t.description, ''), coalesce(t.serviceType, ''), coalesce(t.status, ''),
+		t.organizationId, coalesce(th.creatorUsername, ''), t.version, t.createdAt, t.deadline
+	FROM tender t
+	LEFT JOIN tenderHolder th ON th.tenderId = t.id
+	WHERE t.organizationId::text = ANY($1)
+		AND ($2 = '' OR t.serviceType = $2)
+		AND ($3 = '' OR t.status = $3)
+	ORDER BY t.createdAt, t.id
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(pq.Array(f.OrganizationIds), f.ServiceType, f.Status)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t transfer.Tender
+		err = rows.Scan(&t.Id, &t.Name, &t.Description, &t.ServiceType, &t.Status,
+			&t.OrganizationId, &t.CreatorUsername, &t.Version, &t.CreatedAt, &t.Deadline)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = fn(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// EachBid streams the bids matching the filter to fn, oldest first, and
+// stops at the first error fn returns.
+func (s *Storage) EachBid(f transfer.BidFilter, fn func(transfer.Bid) error) error {
+	const op = "storage.postgres.EachBid"
+
+	stmt, err := s.db.Prepare(`
+	SELECT b.id, b.name, coalesce(b.description, ''), b.tenderId, coalesce(b.authorType, ''), b.authorId,
+		coalesce(b.status, ''), b.price, b.version, b.createdAt
+	FROM bid b
+	JOIN tender t ON t.id = b.tenderId
+	WHERE (t.organizationId::text = ANY($1) OR b.authorId::text = ANY($2))
+		AND ($3 = '' OR b.tenderId::text = $3)
+		AND ($4 = '' OR b.status = $4)
+	ORDER BY b.createdAt, b.id
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(pq.Array(f.OrganizationIds), pq.Array(f.AuthorIds), f.TenderId, f.Status)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b transfer.Bid
+		err = rows.Scan(&b.Id, &b.Name, &b.Description, &b.TenderId, &b.AuthorType, &b.AuthorId,
+			&b.Status, &b.Price, &b.Version, &b.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = fn(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ExistingTenders returns which of the ids are taken by tenders.
+func (s *Storage) ExistingTenders(ids []string) ([]string, error) {
+	return s.existingIds("storage.postgres.ExistingTenders", `SELECT id::text FROM tender WHERE id::text = ANY($1)`, ids)
+}
+
+// ExistingBids returns which of the ids are taken by bids.
+func (s *Storage) ExistingBids(ids []string) ([]string, error) {
+	return s.existingIds("storage.postgres.ExistingBids", `SELECT id::text FROM bid WHERE id::text = ANY($1)`, ids)
+}
+
+func (s *Storage) existingIds(op, query string, ids []string) ([]string, error) {
+	result := make([]string, 0)
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// ImportTenders runs fill in a transaction, handing it a function that
+// copies a batch of tenders in with COPY. Nothing is imported when fill
+// fails.
+func (s *Storage) ImportTenders(fill func(copy func([]transfer.Tender) error) error) error {
+	return s.inTx(func(tx *Storage) error {
+		return fill(func(batch []transfer.Tender) error {
+			return copyTenders(tx.tx, batch)
+		})
+	})
+}
+
+// ImportBids runs fill in a transaction, handing it a function that copies
+// a batch of bids in with COPY. Nothing is imported when fill fails.
+func (s *Storage) ImportBids(fill func(copy func([]transfer.Bid) error) error) error {
+	return s.inTx(func(tx *Storage) error {
+		return fill(func(batch []transfer.Bid) error {
+			return copyBids(tx.tx, batch)
+		})
+	})
+}
+
+// COPY quotes the column names, so they are spelled the way Postgres folded
+// the unquoted names of the schema. Imported rows keep their version, without
+// the history of the earlier ones.
+
+func copyTenders(tx *sql.Tx, batch []transfer.Tender) error {
+	const op = "storage.postgres.copyTenders"
+
+	ids := make([]*string, len(batch))
+	for i := range batch {
+		ids[i] = &batch[i].Id
+	}
+	err := generateIds(tx, ids)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("tender",
+		"id", "name", "description", "servicetype", "status", "organizationid", "version", "createdat", "deadline"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, t := range batch {
+		_, err = stmt.Exec(t.Id, t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationId, t.Version, t.CreatedAt.UTC(), utcOrNil(t.Deadline))
+		if err != nil {
+			stmt.Close()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	err = closeCopy(stmt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err = tx.Prepare(pq.CopyIn("tenderholder", "tenderid", "creatorusername"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, t := range batch {
+		_, err = stmt.Exec(t.Id, t.CreatorUsername)
+		if err != nil {
+			stmt.Close()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	err = closeCopy(stmt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func copyBids(tx *sql.Tx, batch []transfer.Bid) error {
+	const op = "storage.postgres.copyBids"
+
+	ids := make([]*string, len(batch))
+	for i := range batch {
+		ids[i] = &batch[i].Id
+	}
+	err := generateIds(tx, ids)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("bid",
+		"id", "name", "description", "status", "tenderid", "authortype", "authorid", "price", "outofbudget", "version", "createdat"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, b := range batch {
+		_, err = stmt.Exec(b.Id, b.Name, b.Description, b.Status, b.TenderId, b.AuthorType, b.AuthorId, b.Price, b.OutOfBudget, b.Version, b.CreatedAt.UTC())
+		if err != nil {
+			stmt.Close()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	err = closeCopy(stmt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// generateIds fills the empty ids with fresh UUIDs.
+func generateIds(tx *sql.Tx, ids []*string) error {
+	var missing []*string
+	for _, id := range ids {
+		if *id == "" {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(`SELECT uuid_generate_v4()::text FROM generate_series(1, $1)`, len(missing))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for i := 0; rows.Next(); i++ {
+		err = rows.Scan(missing[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// closeCopy flushes the buffered rows of a COPY statement.
+func closeCopy(stmt *sql.Stmt) error {
+	_, err := stmt.Exec()
+	if err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"fmt"
+	tenderdomain "tender_system/internal/domain/tender"
+	"tender_system/internal/models/tender"
+)
+
+// ListTenderTransitions lists the status changes of the tender, oldest
+// first.
+func (s *Storage) ListTenderTransitions(tenderId string) ([]tender.TenderTransition, error) {
+	const op = "storage.postgres.ListTenderTransitions"
+	result := make([]tender.TenderTransition, 0)
+
+	stmt, err := s.db.Prepare(`
+	SELECT id, tenderId, fromStatus, toStatus, actor, reason, createdAt
+	FROM tenderTransition
+	WHERE tenderId = $1
+	ORDER BY createdAt
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.Query(tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tr tender.TenderTransition
+		err = rows.Scan(&tr.Id, &tr.TenderId, &tr.From, &tr.To, &tr.Actor, &tr.Reason, &tr.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result = append(result, tr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// ReadTenderFacts counts what the status guards of the tender depend on.
+func (s *Storage) ReadTenderFacts(tenderId string) (tenderdomain.Facts, error) {
+	var facts tenderdomain.Facts
+
+	stmt, err := s.db.Prepare(`
+	SELECT
+		(SELECT count(*)
+		FROM decisions d
+		JOIN bid b ON d.bidId = b.id
+		WHERE b.tenderId = $1 AND d.status = 'Pending'),
+		(SELECT count(*)
+		FROM bid
+		WHERE tenderId = $1 AND status = 'Approved')
+	`)
+	if err != nil {
+		return facts, err
+	}
+
+	err = stmt.QueryRow(tenderId).Scan(&facts.PendingDecisions, &facts.ApprovedBids)
+	return facts, err
+}
+
+func (s *Storage) recordTenderTransition(tenderId, from, to, actor, reason string) error {
+	stmt, err := s.db.Prepare(`
+	INSERT INTO tenderTransition(tenderId, fromStatus, toStatus, actor, reason)
+	VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(tenderId, from, to, actor, reason)
+	return err
+}
+
+// ChangeTenderStatus is used for transitions made by the server itself, such
+// as awarding a tender after the approval quorum. It checks no guard: callers
+// validate the transition first, as AwardBid does under the tender row lock.
+func (s *Storage) ChangeTenderStatus(tenderId, to, actor, reason string) error {
+	stmt, err := s.db.Prepare(`
+	SELECT status
+	FROM tender
+	WHERE id = $1
+	`)
+	if err != nil {
+		return err
+	}
+
+	var from string
+	err = stmt.QueryRow(tenderId).Scan(&from)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	err = s.archiveEntity(AttachmentTender, tenderId)
+	if err != nil {
+		return err
+	}
+
+	stmt, err = s.db.Prepare(`
+	UPDATE tender
+	SET status = $1, version = version + 1
+	WHERE id = $2
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(to, tenderId)
+	if err != nil {
+		return err
+	}
+
+	return s.recordTenderTransition(tenderId, from, to, actor, reason)
+}
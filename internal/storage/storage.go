@@ -0,0 +1,11 @@
+package storage
+
+import "errors"
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUserNotFound = errors.New("user doesn't exist or is invalid")
+	ErrForbidden    = errors.New("not enough access rights")
+	ErrNotFound     = errors.New("404 Not Found")
+	ErrConflict     = errors.New("conflicts with the current state")
+)